@@ -0,0 +1,203 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+)
+
+const brownfieldUsage = `Usage:
+  appgw-ingress brownfield import [--file <appgw.json>] [--namespace <namespace>]
+  appgw-ingress brownfield report [--url <url>] [--ownership managed|prohibited|orphaned|foreign]
+
+import: Reads the JSON of an existing App Gateway (for instance the output of "az network application-gateway show")
+from the given file or from stdin, and prints AzureIngressProhibitedTarget resources covering all of its
+listeners, routing rules and path maps.
+
+report: Fetches the ownership report from the debug endpoint of a running Ingress Controller (for instance through
+"kubectl port-forward") and prints the App Gateway sub-resources with their ownership as JSON.
+`
+
+const defaultReportURL = "http://localhost:8123/debug/brownfield"
+
+// prohibitedTargetManifest is the subset of an AzureIngressProhibitedTarget written by the import command.
+type prohibitedTargetManifest struct {
+	APIVersion string       `json:"apiVersion"`
+	Kind       string       `json:"kind"`
+	Metadata   manifestMeta `json:"metadata"`
+	Spec       manifestSpec `json:"spec"`
+}
+
+type manifestMeta struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type manifestSpec struct {
+	IP       string   `json:"ip,omitempty"`
+	Hostname string   `json:"hostname,omitempty"`
+	Port     int32    `json:"port,omitempty"`
+	Paths    []string `json:"paths,omitempty"`
+}
+
+// runBrownfield executes the brownfield sub-commands and returns the exit code of the process.
+func runBrownfield(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "report" {
+		return runBrownfieldReport(args[1:], stdout, stderr)
+	}
+	if len(args) == 0 || args[0] != "import" {
+		fmt.Fprint(stderr, brownfieldUsage)
+		return 2
+	}
+
+	importFlags := pflag.NewFlagSet("brownfield import", pflag.ContinueOnError)
+	importFlags.SetOutput(stderr)
+	file := importFlags.StringP("file", "f", "-", "Path to the App Gateway JSON; Use - for stdin.")
+	namespace := importFlags.StringP("namespace", "n", "", "Namespace of the generated prohibited targets. Optional.")
+	if err := importFlags.Parse(args[1:]); err != nil {
+		fmt.Fprint(stderr, brownfieldUsage)
+		return 2
+	}
+
+	input := stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			fmt.Fprintln(stderr, "Error opening App Gateway JSON:", err)
+			return 1
+		}
+		defer f.Close()
+		input = f
+	}
+
+	if err := importProhibitedTargets(input, stdout, stderr, *namespace); err != nil {
+		fmt.Fprintln(stderr, "Error generating prohibited targets:", err)
+		return 1
+	}
+	return 0
+}
+
+// importProhibitedTargets reads App Gateway JSON and writes a multi-document YAML of prohibited targets.
+func importProhibitedTargets(input io.Reader, output io.Writer, stderr io.Writer, namespace string) error {
+	appGwJSON, err := ioutil.ReadAll(input)
+	if err != nil {
+		return err
+	}
+
+	var appGw n.ApplicationGateway
+	if err := appGw.UnmarshalJSON(appGwJSON); err != nil {
+		return err
+	}
+	if appGw.ApplicationGatewayPropertiesFormat == nil {
+		return ErrMissingAppGwProperties
+	}
+
+	prohibitedTargets, skippedListeners := brownfield.GetProhibitedTargetsForExistingConfig(appGw)
+	for _, listener := range skippedListeners {
+		fmt.Fprintf(stderr, "Skipped listener %s: it has no hostname, and its frontend IP or port is unknown\n", listener)
+	}
+	for _, prohibitedTarget := range prohibitedTargets {
+		if prohibitedTarget.Spec.Hostname == "" {
+			fmt.Fprintf(stderr, "Prohibited target %s has no hostname; It prohibits every hostname on %s port %d\n", prohibitedTarget.Name, prohibitedTarget.Spec.IP, prohibitedTarget.Spec.Port)
+		}
+	}
+
+	for idx, prohibitedTarget := range prohibitedTargets {
+		manifest := prohibitedTargetManifest{
+			APIVersion: prohibitedTarget.APIVersion,
+			Kind:       prohibitedTarget.Kind,
+			Metadata: manifestMeta{
+				Name:      prohibitedTarget.Name,
+				Namespace: namespace,
+			},
+			Spec: manifestSpec{
+				IP:       prohibitedTarget.Spec.IP,
+				Hostname: prohibitedTarget.Spec.Hostname,
+				Port:     prohibitedTarget.Spec.Port,
+				Paths:    prohibitedTarget.Spec.Paths,
+			},
+		}
+		manifestYAML, err := yaml.Marshal(manifest)
+		if err != nil {
+			return err
+		}
+		if idx > 0 {
+			fmt.Fprintln(output, "---")
+		}
+		if _, err := output.Write(manifestYAML); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runBrownfieldReport executes the report sub-command and returns the exit code of the process.
+func runBrownfieldReport(args []string, stdout io.Writer, stderr io.Writer) int {
+	reportFlags := pflag.NewFlagSet("brownfield report", pflag.ContinueOnError)
+	reportFlags.SetOutput(stderr)
+	url := reportFlags.String("url", defaultReportURL, "URL of the ownership report of the Ingress Controller.")
+	ownership := reportFlags.String("ownership", "", "List only the sub-resources with this ownership. Optional.")
+	if err := reportFlags.Parse(args); err != nil {
+		fmt.Fprint(stderr, brownfieldUsage)
+		return 2
+	}
+
+	resp, err := http.Get(*url)
+	if err != nil {
+		fmt.Fprintln(stderr, "Error fetching ownership report:", err)
+		return 1
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintln(stderr, "Error fetching ownership report:", resp.Status)
+		return 1
+	}
+
+	if err := printOwnershipReports(resp.Body, stdout, brownfield.Ownership(*ownership)); err != nil {
+		fmt.Fprintln(stderr, "Error printing ownership report:", err)
+		return 1
+	}
+	return 0
+}
+
+// printOwnershipReports writes the given ownership reports as JSON, keeping only the resources with the given
+// ownership unless it is blank.
+func printOwnershipReports(input io.Reader, output io.Writer, ownership brownfield.Ownership) error {
+	var reports []brownfield.OwnershipReport
+	if err := json.NewDecoder(input).Decode(&reports); err != nil {
+		return err
+	}
+
+	if ownership != "" {
+		for idx := range reports {
+			var resources []brownfield.ResourceOwnership
+			for _, resource := range reports[idx].Resources {
+				if resource.Ownership == ownership {
+					resources = append(resources, resource)
+				}
+			}
+			reports[idx].Resources = resources
+		}
+	}
+
+	reportsJSON, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(output, string(reportsJSON))
+	return err
+}
@@ -0,0 +1,90 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("Test the brownfield command", func() {
+
+	Context("test brownfield import", func() {
+		It("should print prohibited targets for the App Gateway JSON", func() {
+			appGwJSON, err := fixtures.GetAppGateway().MarshalJSON()
+			Expect(err).ToNot(HaveOccurred())
+
+			var stdout, stderr bytes.Buffer
+			exitCode := runBrownfield([]string{"import", "--namespace", "prod"}, bytes.NewReader(appGwJSON), &stdout, &stderr)
+			Expect(exitCode).To(Equal(0))
+
+			documents := strings.Split(stdout.String(), "---\n")
+			Expect(documents).To(HaveLen(4))
+			Expect(documents[1]).To(Equal(`apiVersion: appgw.ingress.k8s.io/v1
+kind: AzureIngressProhibitedTarget
+metadata:
+  name: bye-com
+  namespace: prod
+spec:
+  hostname: bye.com
+`))
+			Expect(stderr.String()).To(ContainSubstring("Skipped listener " + fixtures.DefaultHTTPListenerName))
+		})
+
+		It("should fail on invalid input", func() {
+			var stdout, stderr bytes.Buffer
+			Expect(runBrownfield([]string{"import"}, strings.NewReader("{}"), &stdout, &stderr)).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring(ErrMissingAppGwProperties.Error()))
+			Expect(runBrownfield([]string{"export"}, strings.NewReader("{}"), &stdout, &stderr)).To(Equal(2))
+		})
+	})
+
+	Context("test brownfield report", func() {
+		reports := []brownfield.OwnershipReport{{
+			AppGateway: "appgw",
+			Resources: []brownfield.ResourceOwnership{
+				{Kind: brownfield.KindListener, Name: "fl-80", Ownership: brownfield.OwnershipManaged, Ingresses: []string{"default/ing"}},
+				{Kind: brownfield.KindListener, Name: "legacy", Ownership: brownfield.OwnershipOrphaned},
+			},
+		}}
+
+		It("should print the sub-resources with the requested ownership", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				Expect(req.URL.Path).To(Equal("/debug/brownfield"))
+				_ = json.NewEncoder(w).Encode(reports)
+			}))
+			defer server.Close()
+
+			var stdout, stderr bytes.Buffer
+			exitCode := runBrownfield([]string{"report", "--url", server.URL + "/debug/brownfield", "--ownership", "orphaned"}, nil, &stdout, &stderr)
+			Expect(exitCode).To(Equal(0), stderr.String())
+
+			var actual []brownfield.OwnershipReport
+			Expect(json.Unmarshal(stdout.Bytes(), &actual)).To(Succeed())
+			Expect(actual).To(HaveLen(1))
+			Expect(actual[0].AppGateway).To(Equal("appgw"))
+			Expect(actual[0].Resources).To(Equal(reports[0].Resources[1:]))
+		})
+
+		It("should fail when the report cannot be fetched", func() {
+			server := httptest.NewServer(http.NotFoundHandler())
+			defer server.Close()
+
+			var stdout, stderr bytes.Buffer
+			Expect(runBrownfield([]string{"report", "--url", server.URL}, nil, &stdout, &stderr)).To(Equal(1))
+			Expect(stderr.String()).To(ContainSubstring("404"))
+		})
+	})
+})
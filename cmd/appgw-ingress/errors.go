@@ -9,4 +9,5 @@ import "errors"
 
 var (
 	ErrUnexpectedARMStatusCode = errors.New("unexpected ARM status code")
+	ErrMissingAppGwProperties  = errors.New("App Gateway JSON has no properties")
 )
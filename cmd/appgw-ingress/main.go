@@ -36,6 +36,7 @@ import (
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/controller"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned"
 	istio "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned"
@@ -63,6 +64,10 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "brownfield" {
+		os.Exit(runBrownfield(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	// Log output is buffered... Calling Flush before exiting guarantees all log output is written.
 	defer glog.Flush()
 	if err := flags.Parse(os.Args); err != nil {
@@ -125,8 +130,26 @@ func main() {
 		glog.Fatal("Got a fatal validation error on existing Application Gateway config. Please update Application Gateway or the controller's helm config. Error:", err)
 	}
 
-	appGwIngressController := controller.NewAppGwIngressController(appGwClient, appGwIdentifier, k8sContext, recorder)
+	controllers := []*controller.AppGwIngressController{
+		controller.NewAppGwIngressController(appGwClient, appGwIdentifier, k8sContext, recorder),
+	}
 
+	// Each additional App Gateway gets its own ARM client (it may live in another subscription) and controller.
+	for _, identifier := range getAdditionalAppGwIdentifiers(env.AdditionalAppGwIDs) {
+		client := n.NewApplicationGatewaysClient(identifier.SubscriptionID)
+		client.Authorizer = appGwClient.Authorizer
+		additionalAppGw, err := client.Get(context.Background(), identifier.ResourceGroup, identifier.AppGwName)
+		if err != nil {
+			glog.Fatalf("Failed fetching config for additional App Gateway %s: %s", identifier.AppGwName, err)
+		}
+		if err := appgw.FatalValidateOnExistingConfig(recorder, additionalAppGw.ApplicationGatewayPropertiesFormat, env); err != nil {
+			glog.Fatalf("Got a fatal validation error on existing config of additional App Gateway %s. Error: %s", identifier.AppGwName, err)
+		}
+		glog.Infof("Ingress Controller will also manage App Gateway %s in resource group %s", identifier.AppGwName, identifier.ResourceGroup)
+		controllers = append(controllers, controller.NewAppGwIngressController(client, identifier, k8sContext, recorder))
+	}
+
+	appGwIngressController := controller.NewMultiAppGwIngressController(k8sContext, controllers...)
 	if err := appGwIngressController.Start(env); err != nil {
 		glog.Fatal("Could not start AGIC: ", err)
 	}
@@ -185,6 +208,27 @@ func getNamespacesToWatch(namespaceEnvVar string) []string {
 	return []string{namespaceEnvVar}
 }
 
+// getAdditionalAppGwIdentifiers parses the comma separated list of App Gateway resource IDs.
+func getAdditionalAppGwIdentifiers(appGwIDs string) []appgw.Identifier {
+	var identifiers []appgw.Identifier
+	for _, appGwID := range strings.Split(appGwIDs, ",") {
+		appGwID = strings.TrimSpace(appGwID)
+		if appGwID == "" {
+			continue
+		}
+		subscriptionID, resourceGroup, appGwName := azure.ParseResourceID(appGwID)
+		if appGwName == "" {
+			glog.Fatalf("%s contains an invalid App Gateway resource ID: %s", environment.AdditionalAppGwIDsVarName, appGwID)
+		}
+		identifiers = append(identifiers, appgw.Identifier{
+			SubscriptionID: string(subscriptionID),
+			ResourceGroup:  string(resourceGroup),
+			AppGwName:      string(appGwName),
+		})
+	}
+	return identifiers
+}
+
 func getAuthorizer(vars environment.EnvVariables) (autorest.Authorizer, error) {
 	if vars.AuthLocation == "" {
 		// requires aad-pod-identity to be deployed in the AKS cluster
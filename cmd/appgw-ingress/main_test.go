@@ -8,6 +8,7 @@ package main
 import (
 	"testing"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	. "github.com/onsi/ginkgo"
@@ -40,6 +41,20 @@ var _ = Describe("Test functions used in main.go", func() {
 		})
 	})
 
+	Context("test additional App Gateway IDs env var parser", func() {
+		It("should return nothing for an empty env var", func() {
+			Expect(getAdditionalAppGwIdentifiers("")).To(BeEmpty())
+		})
+		It("should parse comma separated resource IDs", func() {
+			actual := getAdditionalAppGwIdentifiers("/subscriptions/sub1/resourceGroups/rg1/providers/Microsoft.Network/applicationGateways/one, /subscriptions/sub2/resourceGroups/rg2/providers/Microsoft.Network/applicationGateways/two")
+			expected := []appgw.Identifier{
+				{SubscriptionID: "sub1", ResourceGroup: "rg1", AppGwName: "one"},
+				{SubscriptionID: "sub2", ResourceGroup: "rg2", AppGwName: "two"},
+			}
+			Expect(actual).To(Equal(expected))
+		})
+	})
+
 	Context("test getVerbosity", func() {
 		flagVerbosity := 9
 		envVerbosity := "8"
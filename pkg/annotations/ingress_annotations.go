@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/knative/pkg/apis/istio/v1alpha3"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
@@ -44,6 +45,26 @@ const (
 	// BackendProtocolKey defines the key to determine whether to use private ip with the ingress.
 	BackendProtocolKey = ApplicationGatewayPrefix + "/backend-protocol"
 
+	// OverrideDeletionGuardKey defines the key to let AGIC deploy config, which removes more App Gateway
+	// listeners, rules or pools than the deletion guard threshold allows.
+	OverrideDeletionGuardKey = ApplicationGatewayPrefix + "/override-deletion-guard"
+
+	// AppGwNameKey defines the key to select, by name, the App Gateway an ingress should be applied to,
+	// when AGIC manages more than one App Gateway. Ingresses without it are applied to the default App Gateway.
+	AppGwNameKey = ApplicationGatewayPrefix + "/appgw-name"
+
+	// CrossNamespaceSecretsKey defines the key for a comma separated list of "namespace/secret" references; A TLS
+	// section of the ingress naming one of these secrets uses the secret of the referenced namespace.
+	CrossNamespaceSecretsKey = ApplicationGatewayPrefix + "/cross-namespace-secrets"
+
+	// AllowedNamespacesKey defines the key of the annotation of a secret listing, comma separated, the namespaces
+	// whose ingresses may reference the secret; "*" allows all namespaces.
+	AllowedNamespacesKey = ApplicationGatewayPrefix + "/allowed-namespaces"
+
+	// DisableDefaultCertificateKey defines the key to keep the controller-wide default certificate from being used for
+	// the rules of the ingress no TLS secret matches.
+	DisableDefaultCertificateKey = ApplicationGatewayPrefix + "/disable-default-certificate"
+
 	// IngressClassKey defines the key of the annotation which needs to be set in order to specify
 	// that this is an ingress resource meant for the application gateway ingress controller.
 	IngressClassKey = "kubernetes.io/ingress.class"
@@ -77,7 +98,19 @@ var ProtocolEnumLookup = map[string]ProtocolEnum{
 // IsApplicationGatewayIngress checks if the Ingress resource can be handled by the Application Gateway ingress controller.
 func IsApplicationGatewayIngress(ing *v1beta1.Ingress) (bool, error) {
 	controllerName, err := parseString(ing, IngressClassKey)
-	return controllerName == ApplicationGatewayIngressClass, err
+	return controllerName == ApplicationGatewayIngressClass || strings.HasPrefix(controllerName, ApplicationGatewayIngressClass+"/"), err
+}
+
+// AppGwName provides the name of the App Gateway the ingress is meant for. It is taken from the AppGwNameKey annotation,
+// or from an ingress class of the form "azure/application-gateway/<name>".
+func AppGwName(ing *v1beta1.Ingress) (string, error) {
+	if appGwName, err := parseString(ing, AppGwNameKey); err == nil {
+		return appGwName, nil
+	}
+	if controllerName, _ := parseString(ing, IngressClassKey); strings.HasPrefix(controllerName, ApplicationGatewayIngressClass+"/") {
+		return strings.TrimPrefix(controllerName, ApplicationGatewayIngressClass+"/"), nil
+	}
+	return "", errors.ErrMissingAnnotations
 }
 
 // IsIstioGatewayIngress checks if this gateway should be handled by AGIC or not
@@ -138,6 +171,61 @@ func BackendProtocol(ing *v1beta1.Ingress) (ProtocolEnum, error) {
 	return HTTP, errors.NewInvalidAnnotationContent(BackendProtocolKey, protocol)
 }
 
+// IsDeletionGuardOverridden determines whether the deletion guard should let a mass deletion through.
+func IsDeletionGuardOverridden(ing *v1beta1.Ingress) (bool, error) {
+	return parseBool(ing, OverrideDeletionGuardKey)
+}
+
+// IsDefaultCertificateDisabled determines whether the default certificate must not be used for the ingress.
+func IsDefaultCertificateDisabled(ing *v1beta1.Ingress) (bool, error) {
+	return parseBool(ing, DisableDefaultCertificateKey)
+}
+
+// CrossNamespaceSecrets provides the namespaces of the secrets the ingress references in other namespaces, by secret name.
+func CrossNamespaceSecrets(ing *v1beta1.Ingress) (map[string]string, error) {
+	val, err := parseString(ing, CrossNamespaceSecretsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaceBySecret := make(map[string]string)
+	for _, reference := range strings.Split(val, ",") {
+		parts := strings.Split(strings.TrimSpace(reference), "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.NewInvalidAnnotationContent(CrossNamespaceSecretsKey, val)
+		}
+		namespaceBySecret[parts[1]] = parts[0]
+	}
+	return namespaceBySecret, nil
+}
+
+// TLSSecretNamespace provides the namespace of the secret a TLS section of the ingress names: The namespace the
+// CrossNamespaceSecretsKey annotation references the secret in, otherwise the namespace of the ingress.
+func TLSSecretNamespace(ing *v1beta1.Ingress, secretName string) string {
+	if namespaceBySecret, err := CrossNamespaceSecrets(ing); err == nil {
+		if namespace, exists := namespaceBySecret[secretName]; exists {
+			return namespace
+		}
+	}
+	return ing.Namespace
+}
+
+// IsSecretAllowedInNamespace determines whether ingresses of the given namespace may use the secret. Ingresses may
+// always use the secrets of their own namespace; Other namespaces must be granted access with the AllowedNamespacesKey
+// annotation of the secret.
+func IsSecretAllowedInNamespace(secret *v1.Secret, namespace string) bool {
+	if secret.Namespace == namespace {
+		return true
+	}
+	for _, allowed := range strings.Split(secret.Annotations[AllowedNamespacesKey], ",") {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == "*" || allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
+
 func parseBool(ing *v1beta1.Ingress, name string) (bool, error) {
 	if val, ok := ing.Annotations[name]; ok {
 		if boolVal, err := strconv.ParseBool(val); err == nil {
@@ -13,6 +13,7 @@ import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -138,6 +139,27 @@ var _ = Describe("Test ingress annotation functions", func() {
 		})
 	})
 
+	Context("test IsDefaultCertificateDisabled", func() {
+		It("returns error when ingress has no annotations", func() {
+			ing := &v1beta1.Ingress{}
+			actual, err := IsDefaultCertificateDisabled(ing)
+			Expect(err).To(HaveOccurred())
+			Expect(actual).To(Equal(false))
+		})
+		It("returns true with correct annotation", func() {
+			ing := &v1beta1.Ingress{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{
+						DisableDefaultCertificateKey: "true",
+					},
+				},
+			}
+			actual, err := IsDefaultCertificateDisabled(ing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal(true))
+		})
+	})
+
 	Context("test IsIstioGatewayIngress", func() {
 		It("returns error when gateway has no annotations", func() {
 			gateway := &v1alpha3.Gateway{}
@@ -171,6 +193,80 @@ var _ = Describe("Test ingress annotation functions", func() {
 		})
 	})
 
+	Context("test AppGwName", func() {
+		It("returns error when ingress has no annotations", func() {
+			ing := &v1beta1.Ingress{}
+			actual, err := AppGwName(ing)
+			Expect(err).To(HaveOccurred())
+			Expect(actual).To(Equal(""))
+		})
+		It("returns the name from the ingress class", func() {
+			ing := &v1beta1.Ingress{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{IngressClassKey: "azure/application-gateway/appgw-two"},
+				},
+			}
+			Expect(IsApplicationGatewayIngress(ing)).To(BeTrue())
+			actual, err := AppGwName(ing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal("appgw-two"))
+		})
+		It("prefers the annotation over the ingress class", func() {
+			ing := &v1beta1.Ingress{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{
+						IngressClassKey: "azure/application-gateway/appgw-two",
+						AppGwNameKey:    "appgw-three",
+					},
+				},
+			}
+			actual, err := AppGwName(ing)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual).To(Equal("appgw-three"))
+		})
+	})
+
+	Context("test cross namespace secrets", func() {
+		It("returns the namespace of the ingress for secrets not referenced by the annotation", func() {
+			ing := &v1beta1.Ingress{ObjectMeta: v1.ObjectMeta{Namespace: "app"}}
+			Expect(TLSSecretNamespace(ing, "tls")).To(Equal("app"))
+		})
+		It("returns the referenced namespace", func() {
+			ing := &v1beta1.Ingress{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace:   "app",
+					Annotations: map[string]string{CrossNamespaceSecretsKey: "shared/wildcard, certs/other"},
+				},
+			}
+			Expect(TLSSecretNamespace(ing, "wildcard")).To(Equal("shared"))
+			Expect(TLSSecretNamespace(ing, "other")).To(Equal("certs"))
+			Expect(TLSSecretNamespace(ing, "tls")).To(Equal("app"))
+		})
+		It("returns error for references without a namespace", func() {
+			ing := &v1beta1.Ingress{
+				ObjectMeta: v1.ObjectMeta{
+					Annotations: map[string]string{CrossNamespaceSecretsKey: "wildcard"},
+				},
+			}
+			_, err := CrossNamespaceSecrets(ing)
+			Expect(errors.IsInvalidContent(err)).To(BeTrue())
+		})
+		It("allows secrets only in the namespaces they grant access to", func() {
+			secret := &corev1.Secret{
+				ObjectMeta: v1.ObjectMeta{
+					Namespace:   "shared",
+					Annotations: map[string]string{AllowedNamespacesKey: "app, web"},
+				},
+			}
+			Expect(IsSecretAllowedInNamespace(secret, "shared")).To(BeTrue())
+			Expect(IsSecretAllowedInNamespace(secret, "web")).To(BeTrue())
+			Expect(IsSecretAllowedInNamespace(secret, "other")).To(BeFalse())
+
+			secret.Annotations[AllowedNamespacesKey] = "*"
+			Expect(IsSecretAllowedInNamespace(secret, "other")).To(BeTrue())
+		})
+	})
+
 	Context("test UsePrivateIP", func() {
 		It("returns error when ingress has no annotations", func() {
 			ing := &v1beta1.Ingress{}
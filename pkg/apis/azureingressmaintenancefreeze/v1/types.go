@@ -0,0 +1,59 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AzureIngressMaintenanceFreeze is a cluster wide switch, which stops AGIC from applying configuration to App Gateway
+type AzureIngressMaintenanceFreeze struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AzureIngressMaintenanceFreezeSpec `json:"spec"`
+
+	// +optional
+	Status AzureIngressMaintenanceFreezeStatus `json:"status,omitempty"`
+}
+
+// AzureIngressMaintenanceFreezeSpec defines for how long and why App Gateway deployments are withheld.
+type AzureIngressMaintenanceFreezeSpec struct {
+	// +optional
+	// Reason is a free form explanation of why the freeze is in place
+	Reason string `json:"reason,omitempty"`
+
+	// +optional
+	// Expiry is the time after which the freeze is no longer in effect and deployments resume; No expiry means the freeze lasts until deleted
+	Expiry *metav1.Time `json:"expiry,omitempty"`
+}
+
+// AzureIngressMaintenanceFreezeStatus is the observed state of the freeze.
+type AzureIngressMaintenanceFreezeStatus struct {
+	// +optional
+	// Frozen is true while AGIC is withholding App Gateway deployments because of this freeze
+	Frozen bool `json:"frozen"`
+
+	// +optional
+	// PendingChanges lists the App Gateway sub-resources, which will be added, modified or removed once the freeze is lifted
+	PendingChanges []string `json:"pendingChanges,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AzureIngressMaintenanceFreezeList is the list of maintenance freezes
+type AzureIngressMaintenanceFreezeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []AzureIngressMaintenanceFreeze `json:"items"`
+}
@@ -0,0 +1,127 @@
+// +build !ignore_autogenerated
+
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by deepcopy-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureIngressMaintenanceFreeze) DeepCopyInto(out *AzureIngressMaintenanceFreeze) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureIngressMaintenanceFreeze.
+func (in *AzureIngressMaintenanceFreeze) DeepCopy() *AzureIngressMaintenanceFreeze {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureIngressMaintenanceFreeze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureIngressMaintenanceFreeze) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureIngressMaintenanceFreezeList) DeepCopyInto(out *AzureIngressMaintenanceFreezeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AzureIngressMaintenanceFreeze, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureIngressMaintenanceFreezeList.
+func (in *AzureIngressMaintenanceFreezeList) DeepCopy() *AzureIngressMaintenanceFreezeList {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureIngressMaintenanceFreezeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AzureIngressMaintenanceFreezeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureIngressMaintenanceFreezeSpec) DeepCopyInto(out *AzureIngressMaintenanceFreezeSpec) {
+	*out = *in
+	if in.Expiry != nil {
+		in, out := &in.Expiry, &out.Expiry
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureIngressMaintenanceFreezeSpec.
+func (in *AzureIngressMaintenanceFreezeSpec) DeepCopy() *AzureIngressMaintenanceFreezeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureIngressMaintenanceFreezeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureIngressMaintenanceFreezeStatus) DeepCopyInto(out *AzureIngressMaintenanceFreezeStatus) {
+	*out = *in
+	if in.PendingChanges != nil {
+		in, out := &in.PendingChanges, &out.PendingChanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureIngressMaintenanceFreezeStatus.
+func (in *AzureIngressMaintenanceFreezeStatus) DeepCopy() *AzureIngressMaintenanceFreezeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureIngressMaintenanceFreezeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -6,6 +6,7 @@
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -20,6 +21,9 @@ type AzureIngressProhibitedTarget struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	Spec AzureIngressProhibitedTargetSpec `json:"spec"`
+
+	// +optional
+	Status AzureIngressProhibitedTargetStatus `json:"status,omitempty"`
 }
 
 // AzureIngressProhibitedTargetSpec defines a list of uniquely identifiable targets for which the AGIC is not allowed to mutate config.
@@ -45,6 +49,64 @@ type AzureIngressProhibitedTargetSpec struct {
 	Paths []string `json:"paths,omitempty"`
 }
 
+// AzureIngressProhibitedTargetConditionType is the type of a condition of a prohibited target.
+type AzureIngressProhibitedTargetConditionType string
+
+const (
+	// Accepted is True once AGIC has observed the prohibited target and its spec passes validation; AGIC refrains from
+	// changing the config matched by a target which is not accepted all the same
+	Accepted AzureIngressProhibitedTargetConditionType = "Accepted"
+
+	// Invalid is True when the spec of the prohibited target does not pass validation
+	Invalid AzureIngressProhibitedTargetConditionType = "Invalid"
+)
+
+// AzureIngressProhibitedTargetCondition describes the state of a prohibited target at a certain point.
+type AzureIngressProhibitedTargetCondition struct {
+	Type   AzureIngressProhibitedTargetConditionType `json:"type"`
+	Status corev1.ConditionStatus                    `json:"status"`
+
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// AzureIngressProhibitedTargetStatus is the observed state of the prohibited target; It is written by the controller of
+// the default App Gateway only, and lists the config of that App Gateway.
+type AzureIngressProhibitedTargetStatus struct {
+	// +optional
+	// ObservedGeneration is the generation of the spec AGIC last evaluated
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +optional
+	Conditions []AzureIngressProhibitedTargetCondition `json:"conditions,omitempty"`
+
+	// +optional
+	// Listeners are the names of the App Gateway HTTP listeners matched by this prohibited target
+	Listeners []string `json:"listeners,omitempty"`
+
+	// +optional
+	// RoutingRules are the names of the App Gateway request routing rules matched by this prohibited target
+	RoutingRules []string `json:"routingRules,omitempty"`
+
+	// +optional
+	// BackendPools are the names of the App Gateway backend address pools matched by this prohibited target
+	BackendPools []string `json:"backendPools,omitempty"`
+
+	// +optional
+	// BackendHTTPSettings are the names of the App Gateway backend HTTP settings matched by this prohibited target
+	BackendHTTPSettings []string `json:"backendHttpSettings,omitempty"`
+
+	// +optional
+	// Probes are the names of the App Gateway health probes matched by this prohibited target
+	Probes []string `json:"probes,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // AzureIngressProhibitedTargetList is the list of prohibited targets
@@ -30,6 +30,7 @@ func (in *AzureIngressProhibitedTarget) DeepCopyInto(out *AzureIngressProhibited
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -51,6 +52,23 @@ func (in *AzureIngressProhibitedTarget) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureIngressProhibitedTargetCondition) DeepCopyInto(out *AzureIngressProhibitedTargetCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureIngressProhibitedTargetCondition.
+func (in *AzureIngressProhibitedTargetCondition) DeepCopy() *AzureIngressProhibitedTargetCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureIngressProhibitedTargetCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureIngressProhibitedTargetList) DeepCopyInto(out *AzureIngressProhibitedTargetList) {
 	*out = *in
@@ -106,3 +124,51 @@ func (in *AzureIngressProhibitedTargetSpec) DeepCopy() *AzureIngressProhibitedTa
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureIngressProhibitedTargetStatus) DeepCopyInto(out *AzureIngressProhibitedTargetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]AzureIngressProhibitedTargetCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Listeners != nil {
+		in, out := &in.Listeners, &out.Listeners
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RoutingRules != nil {
+		in, out := &in.RoutingRules, &out.RoutingRules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BackendPools != nil {
+		in, out := &in.BackendPools, &out.BackendPools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BackendHTTPSettings != nil {
+		in, out := &in.BackendHTTPSettings, &out.BackendHTTPSettings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureIngressProhibitedTargetStatus.
+func (in *AzureIngressProhibitedTargetStatus) DeepCopy() *AzureIngressProhibitedTargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureIngressProhibitedTargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
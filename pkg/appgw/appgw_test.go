@@ -649,14 +649,10 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 
 			httpsOnlyListenersChecker := func(appGW *n.ApplicationGatewayPropertiesFormat) {
 				// Test the listener.
-				secretID := secretIdentifier{
-					Namespace: ingressNS,
-					Name:      "test-ag-secret",
-				}
-
 				frontendPortID := appGwIdentifier.frontendPortID(generateFrontendPortName(443))
 				httpsListenerName := generateListenerName(listenerIdentifier{FrontendPort: 443, HostName: domainName, UsePrivateIP: false})
-				sslCert := appGwIdentifier.sslCertificateID(secretID.secretFullName())
+				// The SSL certificate is named after the hash of the certificate of the secret.
+				sslCert := appGwIdentifier.sslCertificateID(generateSslCertificateName(ctxt.CertificateSecretStore.GetCertificateHash(secKey)))
 				httpsListener := &n.ApplicationGatewayHTTPListener{
 					Etag: to.StringPtr("*"),
 					Name: &httpsListenerName,
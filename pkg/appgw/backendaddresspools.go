@@ -46,12 +46,11 @@ func (c appGwConfigBuilder) getPools(cbCtx *ConfigBuilderContext) []n.Applicatio
 	}
 
 	if cbCtx.EnvVariables.EnableIstioIntegration {
-		_, _, istioServiceBackendPairMap, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
-		for destinationID, serviceBackendPair := range istioServiceBackendPairMap {
-			glog.V(5).Info("Constructing backend pool for service:", destinationID.serviceKey())
-			if pool := c.getIstioBackendAddressPool(destinationID, serviceBackendPair, managedPoolsByName); pool != nil {
-				managedPoolsByName[*pool.Name] = pool
-			}
+		for _, pool := range c.newIstioBackendPoolMap(cbCtx) {
+			managedPoolsByName[*pool.Name] = pool
+		}
+		for _, pool := range c.getIstioRoutePools(cbCtx) {
+			managedPoolsByName[*pool.Name] = pool
 		}
 	}
 
@@ -61,12 +60,12 @@ func (c appGwConfigBuilder) getPools(cbCtx *ConfigBuilderContext) []n.Applicatio
 	}
 
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, &defaultPool)
+		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, cbCtx.ManagedTargets, &defaultPool)
 
 		// Split the existing pools we obtained from App Gateway into ones AGIC is and is not allowed to change.
 		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedPools()
 
-		brownfield.LogPools(existingBlacklisted, existingNonBlacklisted, agicCreatedPools)
+		c.ownership.AddPools(existingBlacklisted, existingNonBlacklisted, agicCreatedPools)
 
 		// MergePools would produce unique list of pools based on Name. Blacklisted pools, which have the same name
 		// as a managed pool would be overwritten.
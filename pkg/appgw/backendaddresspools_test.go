@@ -188,7 +188,7 @@ var _ = Describe("Test the creation of Backend Pools from Ingress definition", f
 		})
 
 		It("Should get backend pools from istio", func() {
-			actual := cb.getIstioBackendAddressPool(destinationID, serviceBackendPair, addressPools)
+			actual := cb.getIstioBackendAddressPool(destinationID, serviceBackendPair, nil, addressPools)
 			Expect(actual).To(BeNil())
 		})
 
@@ -31,13 +31,13 @@ func (c *appGwConfigBuilder) BackendHTTPSettingsCollection(cbCtx *ConfigBuilderC
 	agicHTTPSettings, _, _, err := c.getBackendsAndSettingsMap(cbCtx)
 
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, cbCtx.ManagedTargets, nil)
 		allExistingSettings := rCtx.HTTPSettings
 
 		// PathMaps we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
 		existingBlacklisted, existingNonBlacklisted := rCtx.GetBlacklistedHTTPSettings()
 
-		brownfield.LogHTTPSettings(glog.V(3), existingBlacklisted, existingNonBlacklisted, agicHTTPSettings)
+		c.ownership.AddHTTPSettings(existingBlacklisted, existingNonBlacklisted, agicHTTPSettings)
 
 		// MergePathMaps would produce unique list of routing rules based on Name. Routing rules, which have the same name
 		// as a managed rule would be overwritten.
@@ -45,6 +45,7 @@ func (c *appGwConfigBuilder) BackendHTTPSettingsCollection(cbCtx *ConfigBuilderC
 	}
 	if cbCtx.EnvVariables.EnableIstioIntegration {
 		istioHTTPSettings, _, _, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
+		istioHTTPSettings = append(istioHTTPSettings, c.getIstioRouteHTTPSettings(cbCtx)...)
 		if istioHTTPSettings != nil {
 			sort.Sort(sorter.BySettingsName(istioHTTPSettings))
 		}
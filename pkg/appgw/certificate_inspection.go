@@ -0,0 +1,149 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metrics"
+)
+
+type certificateExpiry int
+
+const (
+	certificateValid certificateExpiry = iota
+	certificateExpiring
+	certificateExpired
+)
+
+// getCertificateExpiryWarningWindow returns how long before its expiry a certificate is reported as expiring.
+func getCertificateExpiryWarningWindow(env environment.EnvVariables) time.Duration {
+	days, err := strconv.Atoi(env.CertificateExpiryWarningDays)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// reportCertificateExpiry emits an event on every Ingress and Istio Gateway using a certificate which has expired or
+// expires within the warning window, the default certificate included, and publishes the number of such certificates
+// for the App Gateway.
+func (c *appGwConfigBuilder) reportCertificateExpiry(cbCtx *ConfigBuilderContext) {
+	window := getCertificateExpiryWarningWindow(cbCtx.EnvVariables)
+	now := time.Now()
+	expiries := make(map[secretIdentifier]certificateExpiry)
+
+	for _, ingress := range cbCtx.IngressList {
+		for _, tls := range ingress.Spec.TLS {
+			if len(tls.SecretName) == 0 {
+				continue
+			}
+			secretID, allowed := c.getTLSSecretID(ingress, tls.SecretName)
+			if !allowed {
+				continue
+			}
+			leaf := c.k8sContext.CertificateSecretStore.GetLeafCertificate(secretID.secretKey())
+			expiries[secretID] = c.checkCertificateExpiry(ingress, secretID, leaf, window, now)
+		}
+		for _, rule := range ingress.Spec.Rules {
+			if _, secretID := c.getDefaultCertificate(ingress, rule.Host, cbCtx.EnvVariables); secretID != nil {
+				leaf := c.k8sContext.CertificateSecretStore.GetLeafCertificate(secretID.secretKey())
+				expiries[*secretID] = c.checkCertificateExpiry(ingress, *secretID, leaf, window, now)
+				break
+			}
+		}
+	}
+
+	if cbCtx.EnvVariables.EnableIstioIntegration {
+		for _, gateway := range cbCtx.IstioGateways {
+			for _, server := range gateway.Spec.Servers {
+				if server.TLS == nil || server.TLS.CredentialName == "" {
+					continue
+				}
+				secretID := secretIdentifier{Namespace: gateway.Namespace, Name: server.TLS.CredentialName}
+				leaf := c.k8sContext.CertificateSecretStore.GetLeafCertificate(secretID.secretKey())
+				expiries[secretID] = c.checkCertificateExpiry(gateway, secretID, leaf, window, now)
+			}
+		}
+	}
+
+	var expiring, expired int64
+	for _, expiry := range expiries {
+		switch expiry {
+		case certificateExpiring:
+			expiring++
+		case certificateExpired:
+			expired++
+		}
+	}
+	metrics.SetGauge(metrics.ExpiringCertificates, c.appGwIdentifier.appGwID(), expiring)
+	metrics.SetGauge(metrics.ExpiredCertificates, c.appGwIdentifier.appGwID(), expired)
+}
+
+// checkCertificateExpiry tells whether the certificate of the given secret has expired or expires within the window,
+// and emits an event on the object using it if so.
+func (c *appGwConfigBuilder) checkCertificateExpiry(obj runtime.Object, secretID secretIdentifier, leaf *x509.Certificate, window time.Duration, now time.Time) certificateExpiry {
+	if leaf == nil {
+		return certificateValid
+	}
+
+	if now.After(leaf.NotAfter) {
+		logLine := fmt.Sprintf("The certificate of secret [%s] expired on %s", secretID.secretKey(), leaf.NotAfter.UTC().Format(time.RFC3339))
+		glog.Warning(logLine)
+		c.recorder.Event(obj, v1.EventTypeWarning, events.ReasonCertificateExpired, logLine)
+		return certificateExpired
+	}
+	if now.Add(window).After(leaf.NotAfter) {
+		logLine := fmt.Sprintf("The certificate of secret [%s] expires on %s", secretID.secretKey(), leaf.NotAfter.UTC().Format(time.RFC3339))
+		glog.Warning(logLine)
+		c.recorder.Event(obj, v1.EventTypeWarning, events.ReasonCertificateExpiring, logLine)
+		return certificateExpiring
+	}
+	return certificateValid
+}
+
+// reportCertificateHostMismatch emits an event on the ingress using a certificate for a host the certificate does not
+// cover, which clients would refuse to connect to. The host to secret map of an ingress is built for each of its rules,
+// so every mismatch is reported once per App Gateway configuration built.
+func (c *appGwConfigBuilder) reportCertificateHostMismatch(ingress *v1beta1.Ingress, secretID secretIdentifier, dnsNames []string, host string) {
+	mismatchKey := strings.Join([]string{ingress.Namespace, ingress.Name, secretID.Name, host}, "/")
+	if c.mem.reportedHostMismatches == nil {
+		c.mem.reportedHostMismatches = &map[string]interface{}{}
+	}
+	if _, reported := (*c.mem.reportedHostMismatches)[mismatchKey]; reported {
+		return
+	}
+	(*c.mem.reportedHostMismatches)[mismatchKey] = nil
+
+	logLine := fmt.Sprintf("The certificate of secret [%s] does not cover host %s; It covers %s", secretID.secretKey(), host, strings.Join(dnsNames, ", "))
+	glog.Warning(logLine)
+	c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonCertificateHostMismatch, logLine)
+}
+
+// certificateCoversHost tells whether the host is among the subject alternative names of the certificate. A wildcard
+// host is covered only by the same wildcard.
+func certificateCoversHost(leaf *x509.Certificate, host string) bool {
+	if !strings.HasPrefix(host, "*.") {
+		return leaf.VerifyHostname(host) == nil
+	}
+	for _, dnsName := range leaf.DNSNames {
+		if strings.EqualFold(dnsName, host) {
+			return true
+		}
+	}
+	return false
+}
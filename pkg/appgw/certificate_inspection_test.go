@@ -0,0 +1,140 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"crypto/x509"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metrics"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test certificate inspection", func() {
+	secretID := secretIdentifier{Namespace: tests.Namespace, Name: tests.NameOfSecret}
+	now := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+	window := 30 * 24 * time.Hour
+
+	var cb appGwConfigBuilder
+	var recorder *record.FakeRecorder
+	BeforeEach(func() {
+		cb = newConfigBuilderFixture(nil)
+		recorder = cb.recorder.(*record.FakeRecorder)
+	})
+
+	Context("Test getCertificateExpiryWarningWindow()", func() {
+		It("should convert days to a duration", func() {
+			Expect(getCertificateExpiryWarningWindow(environment.EnvVariables{CertificateExpiryWarningDays: "30"})).To(Equal(window))
+			Expect(getCertificateExpiryWarningWindow(environment.EnvVariables{})).To(Equal(time.Duration(0)))
+		})
+	})
+
+	Context("Test checkCertificateExpiry()", func() {
+		ingress := tests.NewIngressFixture()
+
+		It("should not report certificates valid beyond the window", func() {
+			leaf := &x509.Certificate{NotAfter: now.Add(2 * window)}
+			Expect(cb.checkCertificateExpiry(ingress, secretID, leaf, window, now)).To(Equal(certificateValid))
+			Expect(recorder.Events).To(BeEmpty())
+		})
+
+		It("should warn about certificates expiring within the window", func() {
+			leaf := &x509.Certificate{NotAfter: now.Add(window / 2)}
+			Expect(cb.checkCertificateExpiry(ingress, secretID, leaf, window, now)).To(Equal(certificateExpiring))
+			Expect(<-recorder.Events).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonCertificateExpiring))
+		})
+
+		It("should warn about expired certificates", func() {
+			leaf := &x509.Certificate{NotAfter: now.Add(-time.Hour)}
+			Expect(cb.checkCertificateExpiry(ingress, secretID, leaf, window, now)).To(Equal(certificateExpired))
+			Expect(<-recorder.Events).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonCertificateExpired))
+		})
+	})
+
+	Context("Test reportCertificateExpiry()", func() {
+		defaultSecretID := secretIdentifier{Namespace: "certificates", Name: "default"}
+
+		It("should publish the numbers of each App Gateway and check the default certificate", func() {
+			newBuilder := func(appGwName string, notAfter time.Time) appGwConfigBuilder {
+				builder := newConfigBuilderFixture(&map[string]interface{}{defaultSecretID.secretKey(): []byte("default")})
+				builder.appGwIdentifier.AppGwName = appGwName
+				builder.k8sContext.CertificateSecretStore = leafSecretStore{
+					SecretsKeeper: builder.k8sContext.CertificateSecretStore,
+					leaves:        map[string]*x509.Certificate{defaultSecretID.secretKey(): {NotAfter: notAfter}},
+				}
+				return builder
+			}
+			ingress := tests.NewIngressFixture()
+			ingress.Spec.TLS = nil
+			cbCtx := &ConfigBuilderContext{
+				IngressList:  []*v1beta1.Ingress{ingress},
+				EnvVariables: environment.EnvVariables{DefaultSSLCertificateSecret: defaultSecretID.secretKey(), CertificateExpiryWarningDays: "30"},
+			}
+
+			expiringBuilder := newBuilder("expiring-appgw", time.Now().Add(time.Hour))
+			expiredBuilder := newBuilder("expired-appgw", time.Now().Add(-time.Hour))
+			expiringBuilder.reportCertificateExpiry(cbCtx)
+			expiredBuilder.reportCertificateExpiry(cbCtx)
+
+			expiringID := expiringBuilder.appGwIdentifier.appGwID()
+			expiredID := expiredBuilder.appGwIdentifier.appGwID()
+			Expect(metrics.ExpiringCertificates.Get(expiringID).String()).To(Equal("1"))
+			Expect(metrics.ExpiredCertificates.Get(expiringID).String()).To(Equal("0"))
+			Expect(metrics.ExpiringCertificates.Get(expiredID).String()).To(Equal("0"))
+			Expect(metrics.ExpiredCertificates.Get(expiredID).String()).To(Equal("1"))
+
+			expiringRecorder := expiringBuilder.recorder.(*record.FakeRecorder)
+			Expect(<-expiringRecorder.Events).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonCertificateExpiring))
+		})
+	})
+
+	Context("Test certificateCoversHost()", func() {
+		leaf := &x509.Certificate{DNSNames: []string{"www.contoso.com", "*.api.contoso.com"}}
+
+		It("should match hosts against the subject alternative names", func() {
+			Expect(certificateCoversHost(leaf, "www.contoso.com")).To(BeTrue())
+			Expect(certificateCoversHost(leaf, "orders.api.contoso.com")).To(BeTrue())
+			Expect(certificateCoversHost(leaf, "ftp.contoso.com")).To(BeFalse())
+		})
+
+		It("should match wildcard hosts only against the same wildcard", func() {
+			Expect(certificateCoversHost(leaf, "*.api.contoso.com")).To(BeTrue())
+			Expect(certificateCoversHost(leaf, "*.contoso.com")).To(BeFalse())
+		})
+	})
+
+	Context("Test reportCertificateHostMismatch()", func() {
+		It("should report every mismatch once", func() {
+			ingress := tests.NewIngressFixture()
+			cb.reportCertificateHostMismatch(ingress, secretID, []string{"www.contoso.com"}, "ftp.contoso.com")
+			cb.reportCertificateHostMismatch(ingress, secretID, []string{"www.contoso.com"}, "ftp.contoso.com")
+
+			Expect(recorder.Events).To(HaveLen(1))
+			event := <-recorder.Events
+			Expect(event).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonCertificateHostMismatch))
+			Expect(event).To(ContainSubstring("ftp.contoso.com"))
+		})
+	})
+})
+
+// leafSecretStore is a secret store knowing the leaf certificates of its secrets.
+type leafSecretStore struct {
+	k8scontext.SecretsKeeper
+	leaves map[string]*x509.Certificate
+}
+
+func (s leafSecretStore) GetLeafCertificate(secretKey string) *x509.Certificate {
+	return s.leaves[secretKey]
+}
@@ -9,13 +9,19 @@ import (
 	"encoding/base64"
 	"fmt"
 	"sort"
+	"strings"
 
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/glog"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
 )
@@ -33,15 +39,56 @@ func (c *appGwConfigBuilder) getSslCertificates(cbCtx *ConfigBuilderContext) *[]
 		}
 	}
 
+	if cbCtx.EnvVariables.EnableIstioIntegration {
+		for _, listenerConfig := range c.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices) {
+			if listenerConfig.Protocol != n.HTTPS {
+				continue
+			}
+			if cert := c.k8sContext.CertificateSecretStore.GetPfxCertificate(listenerConfig.Secret.secretKey()); cert != nil {
+				secretIDCertificateMap[listenerConfig.Secret] = to.StringPtr(base64.StdEncoding.EncodeToString(cert))
+			}
+		}
+	}
+
+	// The default certificate is not named by the TLS sections of ingresses; It is uploaded when listeners use it.
+	for _, listenerConfig := range c.getListenerConfigs(cbCtx) {
+		if listenerConfig.Protocol != n.HTTPS {
+			continue
+		}
+		if _, exists := secretIDCertificateMap[listenerConfig.Secret]; exists {
+			continue
+		}
+		if cert := c.k8sContext.CertificateSecretStore.GetPfxCertificate(listenerConfig.Secret.secretKey()); cert != nil {
+			secretIDCertificateMap[listenerConfig.Secret] = to.StringPtr(base64.StdEncoding.EncodeToString(cert))
+		}
+	}
+
+	c.reportCertificateExpiry(cbCtx)
+
+	// Secrets holding the same certificate share an SSL certificate; It is uploaded from the secret with the lowest key,
+	// so that the choice is the same in every build.
+	secretIDByCertName := make(map[string]secretIdentifier)
+	for secretID := range secretIDCertificateMap {
+		certName := c.getSslCertificateName(secretID)
+		if chosen, exists := secretIDByCertName[certName]; !exists || secretID.secretKey() < chosen.secretKey() {
+			secretIDByCertName[certName] = secretID
+		}
+	}
+
 	var sslCertificates []n.ApplicationGatewaySslCertificate
-	for secretID, cert := range secretIDCertificateMap {
-		sslCertificates = append(sslCertificates, c.newCert(secretID, cert))
+	for _, secretID := range secretIDByCertName {
+		sslCertificates = append(sslCertificates, c.newCert(secretID, secretIDCertificateMap[secretID]))
 	}
 
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		// MergePools would produce unique list of pools based on Name. Blacklisted pools, which have the same name
-		// as a managed pool would be overwritten.
-		sslCertificates = brownfield.MergeCerts(*c.appGw.SslCertificates, sslCertificates)
+		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, cbCtx.ManagedTargets, nil)
+		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedCertificates()
+		c.ownership.AddCertificates(existingBlacklisted, existingNonBlacklisted, sslCertificates)
+
+		// Only certificates used by blacklisted listeners are retained; Stale certificates AGIC created earlier
+		// are dropped. MergeCerts keeps the last certificate with a given name, so a blacklisted certificate is
+		// never overwritten by a managed one with the same name.
+		sslCertificates = brownfield.MergeCerts(sslCertificates, existingBlacklisted)
 	}
 
 	sort.Sort(sorter.ByCertificateName(sslCertificates))
@@ -50,8 +97,18 @@ func (c *appGwConfigBuilder) getSslCertificates(cbCtx *ConfigBuilderContext) *[]
 }
 
 func (c *appGwConfigBuilder) getSecretToCertificateMap(ingress *v1beta1.Ingress) map[secretIdentifier]*string {
-	if c.mem.secretToCert != nil {
-		return *c.mem.secretToCert
+	// The map is memoized per ingress; Ingresses in other namespaces reference other secrets.
+	ingressKey := getResourceKey(ingress.Namespace, ingress.Name)
+	if c.mem.secretToCert == nil {
+		c.mem.secretToCert = &map[string]map[secretIdentifier]*string{}
+	} else if secretIDCertificateMap, exists := (*c.mem.secretToCert)[ingressKey]; exists {
+		return secretIDCertificateMap
+	}
+
+	if _, err := annotations.CrossNamespaceSecrets(ingress); err != nil && !errors.IsMissingAnnotations(err) {
+		logLine := fmt.Sprintf("Ignoring annotation %s of ingress %s/%s: %s", annotations.CrossNamespaceSecretsKey, ingress.Namespace, ingress.Name, err)
+		glog.Warning(logLine)
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonInvalidAnnotation, logLine)
 	}
 
 	secretIDCertificateMap := make(map[secretIdentifier]*string)
@@ -60,24 +117,65 @@ func (c *appGwConfigBuilder) getSecretToCertificateMap(ingress *v1beta1.Ingress)
 			continue
 		}
 
-		tlsSecret := secretIdentifier{
-			Name:      tls.SecretName,
-			Namespace: ingress.Namespace,
+		tlsSecret, allowed := c.getTLSSecretID(ingress, tls.SecretName)
+		if !allowed && !c.k8sContext.IsNamespaceWatched(tlsSecret.Namespace) {
+			logLine := fmt.Sprintf("Secret [%s] is in namespace %s, which AGIC does not watch; Add the namespace to the watched namespaces to use the secret", tlsSecret.secretKey(), tlsSecret.Namespace)
+			glog.Warning(logLine)
+			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonSecretNamespaceNotWatched, logLine)
+			continue
+		}
+		if !allowed {
+			logLine := fmt.Sprintf("Secret [%s] does not allow ingresses of namespace %s to use it; Annotate the secret with %s: \"%s\" to allow them", tlsSecret.secretKey(), ingress.Namespace, annotations.AllowedNamespacesKey, ingress.Namespace)
+			glog.Warning(logLine)
+			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonSecretNotAllowed, logLine)
+			continue
 		}
 
 		// add hostname-tlsSecret mapping to a per-ingress map
-		if cert := c.k8sContext.CertificateSecretStore.GetPfxCertificate(tlsSecret.secretKey()); cert != nil {
+		cert := c.k8sContext.CertificateSecretStore.GetPfxCertificate(tlsSecret.secretKey())
+		if cert != nil {
 			secretIDCertificateMap[tlsSecret] = to.StringPtr(base64.StdEncoding.EncodeToString(cert))
-		} else {
-			logLine := fmt.Sprintf("Unable to find the secret associated to secretId: [%s]", tlsSecret.secretKey())
-			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonSecretNotFound, logLine)
 		}
+		c.reportUnusableSecret(ingress, tlsSecret, cert)
 	}
 
-	c.mem.secretToCert = &secretIDCertificateMap
+	(*c.mem.secretToCert)[ingressKey] = secretIDCertificateMap
 	return secretIDCertificateMap
 }
 
+// getTLSSecretID identifies the secret a TLS section of the ingress names, and tells whether the ingress may use it; A
+// secret referenced in another namespace must allow the namespace of the ingress.
+func (c *appGwConfigBuilder) getTLSSecretID(ingress *v1beta1.Ingress, secretName string) (secretIdentifier, bool) {
+	secretID := secretIdentifier{
+		Name:      secretName,
+		Namespace: annotations.TLSSecretNamespace(ingress, secretName),
+	}
+	if secretID.Namespace == ingress.Namespace {
+		return secretID, true
+	}
+	secret := c.k8sContext.GetSecret(secretID.secretKey())
+	return secretID, secret != nil && annotations.IsSecretAllowedInNamespace(secret, ingress.Namespace)
+}
+
+// reportUnusableSecret emits an event on the object referencing the given TLS secret when the secret does not exist or
+// its certificate could not be converted to PFX; A certificate converted earlier keeps being used until the secret is
+// fixed.
+func (c *appGwConfigBuilder) reportUnusableSecret(obj runtime.Object, secretID secretIdentifier, cert []byte) {
+	if err := c.k8sContext.CertificateSecretStore.GetConversionError(secretID.secretKey()); err != nil {
+		logLine := fmt.Sprintf("Unable to convert the certificate of secret [%s]: %s", secretID.secretKey(), err)
+		if cert != nil {
+			logLine += "; The last valid certificate of the secret is used"
+		}
+		glog.Warning(logLine)
+		c.recorder.Event(obj, v1.EventTypeWarning, events.ReasonInvalidSecret, logLine)
+		return
+	}
+	if cert == nil {
+		logLine := fmt.Sprintf("Unable to find the secret associated to secretId: [%s]", secretID.secretKey())
+		c.recorder.Event(obj, v1.EventTypeWarning, events.ReasonSecretNotFound, logLine)
+	}
+}
+
 func (c *appGwConfigBuilder) getCertificate(ingress *v1beta1.Ingress, hostname string, hostnameSecretIDMap map[string]secretIdentifier) (*string, *secretIdentifier) {
 	if hostnameSecretIDMap == nil {
 		return nil, nil
@@ -100,6 +198,51 @@ func (c *appGwConfigBuilder) getCertificate(ingress *v1beta1.Ingress, hostname s
 	return cert, &secID
 }
 
+// getDefaultCertificate returns the certificate of the controller-wide default secret for the given host of the ingress,
+// when IsDefaultCertificateHost holds; Ingresses annotated with disable-default-certificate do not use it.
+func (c *appGwConfigBuilder) getDefaultCertificate(ingress *v1beta1.Ingress, hostname string, env environment.EnvVariables) (*string, *secretIdentifier) {
+	nameParts := strings.Split(env.DefaultSSLCertificateSecret, "/")
+	if len(nameParts) != 2 || !IsDefaultCertificateHost(ingress, hostname) {
+		return nil, nil
+	}
+	if disabled, _ := annotations.IsDefaultCertificateDisabled(ingress); disabled {
+		return nil, nil
+	}
+
+	secID := secretIdentifier{Namespace: nameParts[0], Name: nameParts[1]}
+	cert := c.k8sContext.CertificateSecretStore.GetPfxCertificate(secID.secretKey())
+	if cert == nil {
+		return nil, nil
+	}
+	return to.StringPtr(base64.StdEncoding.EncodeToString(cert)), &secID
+}
+
+// IsDefaultCertificateHost tells whether the default certificate may serve the given host of the ingress: The ingress
+// has no TLS section, or the host is listed by a TLS section without a secret. A host referencing a secret, which does not
+// exist or could not be converted, is not served with the default certificate; reportUnusableSecret reports it.
+func IsDefaultCertificateHost(ingress *v1beta1.Ingress, hostname string) bool {
+	if len(ingress.Spec.TLS) == 0 {
+		return true
+	}
+	withoutSecret := false
+	for _, tls := range ingress.Spec.TLS {
+		listed := len(tls.Hosts) == 0
+		for _, host := range tls.Hosts {
+			listed = listed || host == hostname
+		}
+		if !listed {
+			continue
+		}
+		if len(tls.SecretName) > 0 {
+			return false
+		}
+		withoutSecret = true
+	}
+	return withoutSecret
+}
+
+// newHostToSecretMap maps the hosts of the TLS sections of the ingress to their secrets; An event is emitted for the
+// hosts the certificate of their secret does not cover, which clients would refuse to connect to.
 func (c *appGwConfigBuilder) newHostToSecretMap(ingress *v1beta1.Ingress) map[string]secretIdentifier {
 	hostToSecretMap := make(map[string]secretIdentifier)
 	for _, tls := range ingress.Spec.TLS {
@@ -107,9 +250,9 @@ func (c *appGwConfigBuilder) newHostToSecretMap(ingress *v1beta1.Ingress) map[st
 			continue
 		}
 
-		tlsSecret := secretIdentifier{
-			Name:      tls.SecretName,
-			Namespace: ingress.Namespace,
+		tlsSecret, allowed := c.getTLSSecretID(ingress, tls.SecretName)
+		if !allowed {
+			continue
 		}
 
 		// add hostname-tlsSecret mapping to a per-ingress map
@@ -123,26 +266,42 @@ func (c *appGwConfigBuilder) newHostToSecretMap(ingress *v1beta1.Ingress) map[st
 			hostToSecretMap[""] = tlsSecret
 		}
 
+		leaf := c.k8sContext.CertificateSecretStore.GetLeafCertificate(tlsSecret.secretKey())
 		for _, hostname := range tls.Hosts {
 			// default secret
 			if len(hostname) == 0 {
 				hostToSecretMap[""] = tlsSecret
-			} else {
-				hostToSecretMap[hostname] = tlsSecret
+				continue
+			}
+
+			hostToSecretMap[hostname] = tlsSecret
+			if leaf != nil && !certificateCoversHost(leaf, hostname) {
+				c.reportCertificateHostMismatch(ingress, tlsSecret, leaf.DNSNames, hostname)
 			}
 		}
 	}
 	return hostToSecretMap
 }
 
+// getSslCertificateName returns the name of the SSL certificate of the given secret, which is derived from the hash of
+// its certificate chain; Secrets not converted by the secret store are named after their namespace and name.
+func (c *appGwConfigBuilder) getSslCertificateName(secretID secretIdentifier) string {
+	certificateHash := c.k8sContext.CertificateSecretStore.GetCertificateHash(secretID.secretKey())
+	if certificateHash == "" {
+		return secretID.secretFullName()
+	}
+	return generateSslCertificateName(certificateHash)
+}
+
 func (c *appGwConfigBuilder) newCert(secretID secretIdentifier, cert *string) n.ApplicationGatewaySslCertificate {
+	certName := c.getSslCertificateName(secretID)
 	return n.ApplicationGatewaySslCertificate{
 		Etag: to.StringPtr("*"),
-		Name: to.StringPtr(secretID.secretFullName()),
-		ID:   to.StringPtr(c.appGwIdentifier.sslCertificateID(secretID.secretFullName())),
+		Name: to.StringPtr(certName),
+		ID:   to.StringPtr(c.appGwIdentifier.sslCertificateID(certName)),
 		ApplicationGatewaySslCertificatePropertiesFormat: &n.ApplicationGatewaySslCertificatePropertiesFormat{
 			Data:     cert,
-			Password: to.StringPtr("msazure"),
+			Password: to.StringPtr(c.k8sContext.CertificateSecretStore.GetPfxPassword(secretID.secretKey())),
 		},
 	}
 }
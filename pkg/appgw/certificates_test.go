@@ -1,6 +1,23 @@
 package appgw
 
 import (
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -77,3 +94,159 @@ var _ = Describe("Testing function newHostToSecretMap", func() {
 		})
 	})
 })
+
+var _ = Describe("Testing function getSslCertificates", func() {
+	Context("Test brownfield deployment with existing certificates", func() {
+		cb := newConfigBuilderFixture(nil)
+		cb.appGw.HTTPListeners = &[]n.ApplicationGatewayHTTPListener{
+			{
+				Name: to.StringPtr("prohibited-listener"),
+				ApplicationGatewayHTTPListenerPropertiesFormat: &n.ApplicationGatewayHTTPListenerPropertiesFormat{
+					HostName:       to.StringPtr("prohibited.contoso.com"),
+					SslCertificate: &n.SubResource{ID: to.StringPtr("x/y/z/prohibited-cert")},
+				},
+			},
+		}
+		cb.appGw.SslCertificates = &[]n.ApplicationGatewaySslCertificate{
+			{Name: to.StringPtr("prohibited-cert")},
+			{Name: to.StringPtr("stale-cert")},
+		}
+		cbCtx := &ConfigBuilderContext{
+			IngressList: []*v1beta1.Ingress{tests.NewIngressFixture()},
+			ProhibitedTargets: []*ptv1.AzureIngressProhibitedTarget{
+				{Spec: ptv1.AzureIngressProhibitedTargetSpec{Hostname: "prohibited.contoso.com"}},
+			},
+			EnvVariables: environment.EnvVariables{EnableBrownfieldDeployment: true},
+		}
+
+		var certNames []string
+		for _, cert := range *cb.getSslCertificates(cbCtx) {
+			certNames = append(certNames, *cert.Name)
+		}
+
+		It("should retain the certificates of prohibited listeners", func() {
+			Expect(certNames).To(ContainElement("prohibited-cert"))
+		})
+
+		It("should remove existing certificates no longer used", func() {
+			Expect(certNames).ToNot(ContainElement("stale-cert"))
+		})
+
+		It("should add the certificates of the ingress", func() {
+			Expect(certNames).To(ContainElement(secretIdentifier{Namespace: tests.Namespace, Name: tests.NameOfSecret}.secretFullName()))
+		})
+	})
+})
+
+// hashedSecretStore is a secret store knowing the certificate hashes and PFX passwords of its secrets.
+type hashedSecretStore struct {
+	k8scontext.SecretsKeeper
+	hashes map[string]string
+}
+
+func (s hashedSecretStore) GetCertificateHash(secretKey string) string {
+	return s.hashes[secretKey]
+}
+
+func (s hashedSecretStore) GetPfxPassword(secretKey string) string {
+	return "password-of-" + secretKey
+}
+
+var _ = Describe("Testing SSL certificate deduplication", func() {
+	const otherNamespace = "other-namespace"
+	const sharedHash = "0123456789abcdef"
+
+	Context("Test secrets holding the same certificate in several namespaces", func() {
+		otherSecretKey := otherNamespace + "/" + tests.NameOfSecret
+		cb := newConfigBuilderFixture(&map[string]interface{}{otherSecretKey: []byte("xyz")})
+		cb.k8sContext.CertificateSecretStore = hashedSecretStore{
+			SecretsKeeper: cb.k8sContext.CertificateSecretStore,
+			hashes: map[string]string{
+				tests.Namespace + "/" + tests.NameOfSecret: sharedHash,
+				otherSecretKey: sharedHash,
+			},
+		}
+
+		ingress := tests.NewIngressFixture()
+		otherIngress := tests.NewIngressFixture()
+		otherIngress.Namespace = otherNamespace
+		for idx := range otherIngress.Spec.Rules {
+			// The TLS section of the fixture has a default secret, which the listeners of these hosts use.
+			otherIngress.Spec.Rules[idx].Host = "other-" + otherIngress.Spec.Rules[idx].Host
+		}
+		cbCtx := &ConfigBuilderContext{
+			IngressList: []*v1beta1.Ingress{ingress, otherIngress},
+		}
+
+		certs := *cb.getSslCertificates(cbCtx)
+		listenerConfigs := cb.getListenerConfigs(cbCtx)
+
+		It("should upload the certificate once, named after its hash", func() {
+			Expect(certs).To(HaveLen(1))
+			Expect(*certs[0].Name).To(Equal(generateSslCertificateName(sharedHash)))
+		})
+
+		It("should upload the PFX of the secret with the lowest key", func() {
+			Expect(*certs[0].Password).To(Equal("password-of-" + tests.Namespace + "/" + tests.NameOfSecret))
+		})
+
+		It("should make the listeners of both ingresses use the certificate", func() {
+			var secretNamespaces []string
+			for _, config := range listenerConfigs {
+				if config.Protocol == n.HTTPS {
+					secretNamespaces = append(secretNamespaces, config.Secret.Namespace)
+					Expect(cb.getSslCertificateName(config.Secret)).To(Equal(*certs[0].Name))
+				}
+			}
+			Expect(secretNamespaces).To(ContainElement(tests.Namespace))
+			Expect(secretNamespaces).To(ContainElement(otherNamespace))
+		})
+	})
+})
+
+var _ = Describe("Testing cross namespace TLS secrets", func() {
+	const sharedNamespace = "shared"
+	sharedSecretID := secretIdentifier{Namespace: sharedNamespace, Name: tests.NameOfSecret}
+
+	var cb appGwConfigBuilder
+	var ingress *v1beta1.Ingress
+	BeforeEach(func() {
+		cb = newConfigBuilderFixture(&map[string]interface{}{sharedSecretID.secretKey(): []byte("shared")})
+		ingress = tests.NewIngressFixture()
+		ingress.Annotations[annotations.CrossNamespaceSecretsKey] = sharedSecretID.secretKey()
+	})
+
+	It("should use the referenced secret when its namespace allows the ingress", func() {
+		_ = cb.k8sContext.Caches.Secret.Add(&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   sharedNamespace,
+				Name:        tests.NameOfSecret,
+				Annotations: map[string]string{annotations.AllowedNamespacesKey: tests.Namespace},
+			},
+		})
+
+		Expect(cb.getSecretToCertificateMap(ingress)).To(HaveKey(sharedSecretID))
+		Expect(cb.newHostToSecretMap(ingress)).To(HaveKeyWithValue(tests.Host, sharedSecretID))
+	})
+
+	It("should refuse the referenced secret when its namespace does not allow the ingress", func() {
+		_ = cb.k8sContext.Caches.Secret.Add(&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: sharedNamespace, Name: tests.NameOfSecret},
+		})
+
+		Expect(cb.getSecretToCertificateMap(ingress)).To(BeEmpty())
+		Expect(cb.newHostToSecretMap(ingress)).To(BeEmpty())
+
+		recorder := cb.recorder.(*record.FakeRecorder)
+		Expect(<-recorder.Events).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonSecretNotAllowed))
+	})
+
+	It("should report a referenced secret in a namespace AGIC does not watch", func() {
+		cb.k8sContext = k8scontext.NewContext(testclient.NewSimpleClientset(), fake.NewSimpleClientset(), istio_fake.NewSimpleClientset(), []string{tests.Namespace}, 1000*time.Second)
+
+		Expect(cb.getSecretToCertificateMap(ingress)).To(BeEmpty())
+
+		recorder := cb.recorder.(*record.FakeRecorder)
+		Expect(<-recorder.Events).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonSecretNamespaceNotWatched))
+	})
+})
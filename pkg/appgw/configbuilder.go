@@ -17,6 +17,7 @@ import (
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure/tags"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/version"
@@ -27,11 +28,13 @@ type ConfigBuilder interface {
 	PreBuildValidate(cbCtx *ConfigBuilderContext) error
 	Build(cbCtx *ConfigBuilderContext) (*n.ApplicationGateway, error)
 	PostBuildValidate(cbCtx *ConfigBuilderContext) error
+	OwnershipReport() *brownfield.OwnershipReport
 }
 
 type memoization struct {
 	listeners                    *[]n.ApplicationGatewayHTTPListener
 	listenerConfigs              *map[listenerIdentifier]listenerAzConfig
+	listenerConfigsByIngress     *map[*v1beta1.Ingress]map[listenerIdentifier]listenerAzConfig
 	routingRules                 *[]n.ApplicationGatewayRequestRoutingRule
 	pathMaps                     *[]n.ApplicationGatewayURLPathMap
 	probesByName                 *map[string]n.ApplicationGatewayProbe
@@ -42,7 +45,9 @@ type memoization struct {
 	serviceBackendPairsByBackend *map[backendIdentifier]serviceBackendPortPair
 	pools                        *[]n.ApplicationGatewayBackendAddressPool
 	certs                        *[]n.ApplicationGatewaySslCertificate
-	secretToCert                 *map[secretIdentifier]*string
+	secretToCert                 *map[string]map[secretIdentifier]*string
+	reportedHostMismatches       *map[string]interface{}
+	istioListenerConfigs         *map[listenerIdentifier]listenerAzConfig
 }
 
 type appGwConfigBuilder struct {
@@ -51,6 +56,7 @@ type appGwConfigBuilder struct {
 	appGw           n.ApplicationGateway
 	recorder        record.EventRecorder
 	mem             memoization
+	ownership       *brownfield.OwnershipReport
 }
 
 // NewConfigBuilder construct a builder
@@ -65,6 +71,16 @@ func NewConfigBuilder(context *k8scontext.Context, appGwIdentifier *Identifier,
 
 // Build gets a pointer to updated ApplicationGatewayPropertiesFormat.
 func (c *appGwConfigBuilder) Build(cbCtx *ConfigBuilderContext) (*n.ApplicationGateway, error) {
+	c.ownership = nil
+	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
+		c.ownership = brownfield.NewOwnershipReport(c.appGw, cbCtx.ProhibitedTargets, cbCtx.ManagedTargets)
+	}
+
+	var tracker *ownershipTracker
+	if cbCtx.EnvVariables.EnableOwnershipRecord || cbCtx.EnvVariables.ClusterID != "" {
+		tracker = c.newOwnershipTracker(cbCtx.EnvVariables.ClusterID)
+	}
+
 	err := c.HealthProbesCollection(cbCtx)
 	if err != nil {
 		glog.Errorf("unable to generate Health Probes, error [%v]", err.Error())
@@ -94,6 +110,9 @@ func (c *appGwConfigBuilder) Build(cbCtx *ConfigBuilderContext) (*n.ApplicationG
 		return nil, ErrGeneratingListeners
 	}
 
+	// RewriteRuleSets are referenced by the request routing rules and path rules created in the next step.
+	c.RewriteRuleSets(cbCtx)
+
 	// SSL redirection configurations created elsewhere will be attached to the appropriate rule in this step.
 	err = c.RequestRoutingRules(cbCtx)
 	if err != nil {
@@ -101,11 +120,25 @@ func (c *appGwConfigBuilder) Build(cbCtx *ConfigBuilderContext) (*n.ApplicationG
 		return nil, ErrGeneratingRoutingRules
 	}
 
-	c.addTags()
+	c.addTags(cbCtx)
+
+	// Existing config AGIC did not create is retained, and what AGIC did create is recorded in App Gateway tags.
+	if tracker != nil {
+		c.retainForeignResources(cbCtx, tracker)
+	}
+
+	c.addIngressOwnership(cbCtx)
+	c.ownership.Complete(glog.V(3))
 
 	return &c.appGw, nil
 }
 
+// OwnershipReport returns the ownership of the App Gateway sub-resources determined by the last Build;
+// It is nil unless brownfield deployment is enabled.
+func (c *appGwConfigBuilder) OwnershipReport() *brownfield.OwnershipReport {
+	return c.ownership
+}
+
 type valFunc func(eventRecorder record.EventRecorder, config *n.ApplicationGatewayPropertiesFormat, envVariables environment.EnvVariables, ingressList []*v1beta1.Ingress, serviceList []*v1.Service) error
 
 // PreBuildValidate runs all the validators that suggest misconfiguration in Kubernetes resources.
@@ -190,12 +223,16 @@ func generateListenerID(rule *v1beta1.IngressRule, protocol n.ApplicationGateway
 }
 
 // addTags will add certain tags to Application Gateway
-func (c *appGwConfigBuilder) addTags() {
+func (c *appGwConfigBuilder) addTags(cbCtx *ConfigBuilderContext) {
 	if c.appGw.Tags == nil {
 		c.appGw.Tags = make(map[string]*string)
 	}
 	// Identify the App Gateway as being exclusively managed by a Kubernetes Ingress.
 	c.appGw.Tags[tags.ManagedByK8sIngress] = to.StringPtr(fmt.Sprintf("%s/%s/%s", version.Version, version.GitCommit, version.BuildDate))
+	if cbCtx.EnvVariables.ClusterID != "" {
+		// Clusters sharing the App Gateway are identified by their ownership records instead.
+		return
+	}
 	if aksResourceID, err := azure.ConvertToClusterResourceGroup(c.k8sContext.GetInfrastructureResourceGroupID()); err == nil {
 		c.appGw.Tags[tags.IngressForAKSClusterID] = to.StringPtr(aksResourceID)
 	} else {
@@ -25,9 +25,12 @@ import (
 	"k8s.io/client-go/tools/record"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure/tags"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
 	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/version"
@@ -392,5 +395,320 @@ var _ = Describe("Tests `appgw.ConfigBuilder`", func() {
 			}
 
 		})
+
+		It("Should report ownership of the App Gateway sub-resources in brownfield deployments", func() {
+			Expect(configBuilder.OwnershipReport()).To(BeNil())
+
+			brownfieldCtx := *cbCtx
+			brownfieldCtx.EnvVariables.EnableBrownfieldDeployment = true
+			brownfieldBuilder := NewConfigBuilder(ctxt, &appGwIdentifier, appGwy, record.NewFakeRecorder(100))
+			_, err := brownfieldBuilder.Build(&brownfieldCtx)
+			Expect(err).ToNot(HaveOccurred())
+
+			report := brownfieldBuilder.OwnershipReport()
+			Expect(report).ToNot(BeNil())
+			Expect(report.Resources).To(ContainElement(brownfield.ResourceOwnership{
+				Kind:      brownfield.KindListener,
+				Name:      "fl-foo.baz-80",
+				Ownership: brownfield.OwnershipManaged,
+				Ingresses: []string{ingress.Namespace + "/" + ingress.Name},
+			}))
+			Expect(report.Resources).To(ContainElement(brownfield.ResourceOwnership{
+				Kind:      brownfield.KindBackendPool,
+				Name:      DefaultBackendAddressPoolName,
+				Ownership: brownfield.OwnershipManaged,
+			}))
+		})
+
+		It("Should retain foreign config and remove stale config recorded as AGIC's", func() {
+			properties := n.ApplicationGatewayPropertiesFormat{
+				FrontendIPConfigurations: appGwy.FrontendIPConfigurations,
+			}
+			properties.HTTPListeners = &[]n.ApplicationGatewayHTTPListener{
+				{
+					Name: to.StringPtr("vmss-listener"),
+					ApplicationGatewayHTTPListenerPropertiesFormat: &n.ApplicationGatewayHTTPListenerPropertiesFormat{
+						HostName: to.StringPtr("vmss.contoso.com"),
+					},
+				},
+				{
+					Name: to.StringPtr("old-prefix-fl-foo.baz-80"),
+					ApplicationGatewayHTTPListenerPropertiesFormat: &n.ApplicationGatewayHTTPListenerPropertiesFormat{
+						HostName: to.StringPtr("foo.baz"),
+					},
+				},
+			}
+			existing := n.ApplicationGateway{ApplicationGatewayPropertiesFormat: &properties}
+			err := brownfield.SetOwnershipRecord(&existing, brownfield.ClusterRecord{
+				Resources: brownfield.OwnershipRecord{
+					brownfield.KindListener: {"old-prefix-fl-foo.baz-80"},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			recordCtx := *cbCtx
+			recordCtx.EnvVariables.EnableOwnershipRecord = true
+			recordBuilder := NewConfigBuilder(ctxt, &appGwIdentifier, &existing, record.NewFakeRecorder(100))
+			appGw, err := recordBuilder.Build(&recordCtx)
+			Expect(err).ToNot(HaveOccurred())
+
+			var listenerNames []string
+			for _, listener := range *appGw.HTTPListeners {
+				listenerNames = append(listenerNames, *listener.Name)
+			}
+			Expect(listenerNames).To(ConsistOf("fl-foo.baz-80", "vmss-listener"))
+
+			ownershipRecord, err := brownfield.GetOwnershipRecord(*appGw, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ownershipRecord.Resources[brownfield.KindListener]).To(ConsistOf("fl-foo.baz-80"))
+			Expect(ownershipRecord.Resources[brownfield.KindBackendPool]).To(ContainElement(DefaultBackendAddressPoolName))
+		})
+
+		It("Should share pools with other clusters and keep their config", func() {
+			properties := n.ApplicationGatewayPropertiesFormat{
+				FrontendIPConfigurations: appGwy.FrontendIPConfigurations,
+				HTTPListeners: &[]n.ApplicationGatewayHTTPListener{
+					{
+						Name: to.StringPtr("green-listener"),
+						ApplicationGatewayHTTPListenerPropertiesFormat: &n.ApplicationGatewayHTTPListenerPropertiesFormat{
+							HostName: to.StringPtr("green.contoso.com"),
+						},
+					},
+				},
+				BackendAddressPools: &[]n.ApplicationGatewayBackendAddressPool{
+					{
+						Name: to.StringPtr(DefaultBackendAddressPoolName),
+						ApplicationGatewayBackendAddressPoolPropertiesFormat: &n.ApplicationGatewayBackendAddressPoolPropertiesFormat{
+							BackendAddresses: &[]n.ApplicationGatewayBackendAddress{
+								{IPAddress: to.StringPtr("10.1.1.1")},
+								{IPAddress: to.StringPtr("10.9.9.9")},
+							},
+						},
+					},
+				},
+			}
+			existing := n.ApplicationGateway{ApplicationGatewayPropertiesFormat: &properties}
+			Expect(brownfield.SetOwnershipRecord(&existing, brownfield.ClusterRecord{
+				Cluster: "green",
+				Resources: brownfield.OwnershipRecord{
+					brownfield.KindListener:    {"green-listener"},
+					brownfield.KindBackendPool: {DefaultBackendAddressPoolName},
+				},
+				PoolAddresses: map[string][]string{DefaultBackendAddressPoolName: {"10.9.9.9"}},
+			})).ToNot(HaveOccurred())
+			Expect(brownfield.SetOwnershipRecord(&existing, brownfield.ClusterRecord{
+				Cluster: "blue",
+				Resources: brownfield.OwnershipRecord{
+					brownfield.KindBackendPool: {DefaultBackendAddressPoolName},
+				},
+				PoolAddresses: map[string][]string{DefaultBackendAddressPoolName: {"10.1.1.1"}},
+			})).ToNot(HaveOccurred())
+
+			clusterCtx := *cbCtx
+			clusterCtx.EnvVariables.ClusterID = "blue"
+			clusterBuilder := NewConfigBuilder(ctxt, &appGwIdentifier, &existing, record.NewFakeRecorder(100))
+			appGw, err := clusterBuilder.Build(&clusterCtx)
+			Expect(err).ToNot(HaveOccurred())
+
+			var listenerNames []string
+			for _, listener := range *appGw.HTTPListeners {
+				listenerNames = append(listenerNames, *listener.Name)
+			}
+			Expect(listenerNames).To(ConsistOf("fl-foo.baz-80", "green-listener"))
+
+			// The address blue contributed before is gone; The one of green is kept.
+			Expect(*appGw.BackendAddressPools).To(HaveLen(1))
+			Expect(*(*appGw.BackendAddressPools)[0].BackendAddresses).To(ConsistOf(n.ApplicationGatewayBackendAddress{IPAddress: to.StringPtr("10.9.9.9")}))
+			Expect(appGw.Tags).ToNot(HaveKey(tags.IngressForAKSClusterID))
+
+			blueRecord, err := brownfield.GetOwnershipRecord(*appGw, "blue")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blueRecord.Resources[brownfield.KindListener]).To(ConsistOf("fl-foo.baz-80"))
+			Expect(blueRecord.Resources[brownfield.KindBackendPool]).To(ConsistOf(DefaultBackendAddressPoolName))
+			Expect(blueRecord.PoolAddresses).To(BeEmpty())
+
+			greenRecord, err := brownfield.GetOwnershipRecord(*appGw, "green")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(greenRecord.Resources[brownfield.KindListener]).To(ConsistOf("green-listener"))
+		})
+
+		It("Should keep the rewrite rule sets of other clusters and remove stale ones recorded as AGIC's", func() {
+			properties := n.ApplicationGatewayPropertiesFormat{
+				FrontendIPConfigurations: appGwy.FrontendIPConfigurations,
+				RequestRoutingRules: &[]n.ApplicationGatewayRequestRoutingRule{
+					{
+						Name: to.StringPtr("green-rule"),
+						ApplicationGatewayRequestRoutingRulePropertiesFormat: &n.ApplicationGatewayRequestRoutingRulePropertiesFormat{
+							RewriteRuleSet: resourceRef(appGwIdentifier.rewriteRuleSetID("green-rw")),
+						},
+					},
+				},
+				RewriteRuleSets: &[]n.ApplicationGatewayRewriteRuleSet{
+					{Name: to.StringPtr("green-rw")},
+					{Name: to.StringPtr("vmss-rw")},
+					{Name: to.StringPtr("old-prefix-rw-default-vs-0")},
+				},
+			}
+			existing := n.ApplicationGateway{ApplicationGatewayPropertiesFormat: &properties}
+			Expect(brownfield.SetOwnershipRecord(&existing, brownfield.ClusterRecord{
+				Cluster: "green",
+				Resources: brownfield.OwnershipRecord{
+					brownfield.KindRoutingRule:    {"green-rule"},
+					brownfield.KindRewriteRuleSet: {"green-rw"},
+				},
+			})).ToNot(HaveOccurred())
+			Expect(brownfield.SetOwnershipRecord(&existing, brownfield.ClusterRecord{
+				Cluster: "blue",
+				Resources: brownfield.OwnershipRecord{
+					brownfield.KindRewriteRuleSet: {"old-prefix-rw-default-vs-0"},
+				},
+			})).ToNot(HaveOccurred())
+
+			clusterCtx := *cbCtx
+			clusterCtx.EnvVariables.ClusterID = "blue"
+			clusterBuilder := NewConfigBuilder(ctxt, &appGwIdentifier, &existing, record.NewFakeRecorder(100))
+			appGw, err := clusterBuilder.Build(&clusterCtx)
+			Expect(err).ToNot(HaveOccurred())
+
+			var rewriteRuleSetNames []string
+			for _, rewriteRuleSet := range *appGw.RewriteRuleSets {
+				rewriteRuleSetNames = append(rewriteRuleSetNames, *rewriteRuleSet.Name)
+			}
+			Expect(rewriteRuleSetNames).To(ConsistOf("green-rw", "vmss-rw"))
+
+			var ruleNames []string
+			for _, rule := range *appGw.RequestRoutingRules {
+				ruleNames = append(ruleNames, *rule.Name)
+			}
+			Expect(ruleNames).To(ContainElement("green-rule"))
+
+			blueRecord, err := brownfield.GetOwnershipRecord(*appGw, "blue")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blueRecord.Resources).ToNot(HaveKey(brownfield.KindRewriteRuleSet))
+
+			greenRecord, err := brownfield.GetOwnershipRecord(*appGw, "green")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(greenRecord.Resources[brownfield.KindRewriteRuleSet]).To(ConsistOf("green-rw"))
+		})
+
+		It("Should keep a foreign frontend port on a port number AGIC generates as well", func() {
+			frontendPortID := "/x/y/frontendPorts/myport-80"
+			properties := n.ApplicationGatewayPropertiesFormat{
+				FrontendIPConfigurations: appGwy.FrontendIPConfigurations,
+				FrontendPorts: &[]n.ApplicationGatewayFrontendPort{
+					{
+						Name: to.StringPtr("myport-80"),
+						ID:   to.StringPtr(frontendPortID),
+						ApplicationGatewayFrontendPortPropertiesFormat: &n.ApplicationGatewayFrontendPortPropertiesFormat{
+							Port: to.Int32Ptr(80),
+						},
+					},
+				},
+				HTTPListeners: &[]n.ApplicationGatewayHTTPListener{
+					{
+						Name: to.StringPtr("vmss-listener"),
+						ApplicationGatewayHTTPListenerPropertiesFormat: &n.ApplicationGatewayHTTPListenerPropertiesFormat{
+							FrontendIPConfiguration: &n.SubResource{ID: to.StringPtr("*")},
+							FrontendPort:            &n.SubResource{ID: to.StringPtr(frontendPortID)},
+							HostName:                to.StringPtr("vmss.contoso.com"),
+							Protocol:                n.HTTP,
+						},
+					},
+				},
+			}
+			existing := n.ApplicationGateway{ApplicationGatewayPropertiesFormat: &properties}
+
+			recordCtx := *cbCtx
+			recordCtx.EnvVariables.EnableOwnershipRecord = true
+			recordBuilder := NewConfigBuilder(ctxt, &appGwIdentifier, &existing, record.NewFakeRecorder(100))
+			appGw, err := recordBuilder.Build(&recordCtx)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(*appGw.FrontendPorts).To(HaveLen(1))
+			Expect(*(*appGw.FrontendPorts)[0].Name).To(Equal("myport-80"))
+
+			var listenerNames []string
+			for _, listener := range *appGw.HTTPListeners {
+				listenerNames = append(listenerNames, *listener.Name)
+				Expect(*listener.FrontendPort.ID).To(Equal(frontendPortID))
+			}
+			Expect(listenerNames).To(ConsistOf("fl-foo.baz-80", "vmss-listener"))
+
+			ownershipRecord, err := brownfield.GetOwnershipRecord(*appGw, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ownershipRecord.Resources[brownfield.KindFrontendPort]).To(BeEmpty())
+		})
+
+		It("Should keep a foreign listener serving the same hostname and drop the generated one", func() {
+			frontendPortID := "/x/y/frontendPorts/vmss-port"
+			properties := n.ApplicationGatewayPropertiesFormat{
+				FrontendIPConfigurations: appGwy.FrontendIPConfigurations,
+				FrontendPorts: &[]n.ApplicationGatewayFrontendPort{
+					{
+						Name: to.StringPtr("vmss-port"),
+						ApplicationGatewayFrontendPortPropertiesFormat: &n.ApplicationGatewayFrontendPortPropertiesFormat{
+							Port: to.Int32Ptr(80),
+						},
+					},
+				},
+				HTTPListeners: &[]n.ApplicationGatewayHTTPListener{
+					{
+						Name: to.StringPtr("vmss-listener"),
+						ApplicationGatewayHTTPListenerPropertiesFormat: &n.ApplicationGatewayHTTPListenerPropertiesFormat{
+							FrontendIPConfiguration: &n.SubResource{ID: to.StringPtr("*")},
+							FrontendPort:            &n.SubResource{ID: to.StringPtr(frontendPortID)},
+							HostName:                to.StringPtr("foo.baz"),
+							Protocol:                n.HTTP,
+						},
+					},
+				},
+			}
+			existing := n.ApplicationGateway{ApplicationGatewayPropertiesFormat: &properties}
+
+			recorder := record.NewFakeRecorder(100)
+			recordCtx := *cbCtx
+			recordCtx.EnvVariables.EnableOwnershipRecord = true
+			recordBuilder := NewConfigBuilder(ctxt, &appGwIdentifier, &existing, recorder)
+			appGw, err := recordBuilder.Build(&recordCtx)
+			Expect(err).ToNot(HaveOccurred())
+
+			var listenerNames []string
+			for _, listener := range *appGw.HTTPListeners {
+				listenerNames = append(listenerNames, *listener.Name)
+			}
+			Expect(listenerNames).To(ConsistOf("vmss-listener"))
+			for _, rule := range *appGw.RequestRoutingRules {
+				Expect(*rule.Name).ToNot(Equal("rr-foo.baz-80"))
+			}
+			close(recorder.Events)
+			var emitted []string
+			for event := range recorder.Events {
+				emitted = append(emitted, event)
+			}
+			Expect(emitted).To(ContainElement(ContainSubstring(events.ReasonListenerConflict)))
+		})
+
+		It("Should emit the warnings of an ingress once when recording ownership", func() {
+			annotatedIngress := ingress.DeepCopy()
+			annotatedIngress.Annotations[annotations.CookieBasedAffinityKey] = "not-a-bool"
+
+			recorder := record.NewFakeRecorder(100)
+			recordCtx := *cbCtx
+			recordCtx.IngressList = []*v1beta1.Ingress{annotatedIngress}
+			recordCtx.EnvVariables.EnableOwnershipRecord = true
+			recordCtx.EnvVariables.EnableBrownfieldDeployment = true
+			recordBuilder := NewConfigBuilder(ctxt, &appGwIdentifier, appGwy, recorder)
+			_, err := recordBuilder.Build(&recordCtx)
+			Expect(err).ToNot(HaveOccurred())
+
+			close(recorder.Events)
+			var invalidAnnotationEvents []string
+			for event := range recorder.Events {
+				if strings.Contains(event, events.ReasonInvalidAnnotation) {
+					invalidAnnotationEvents = append(invalidAnnotationEvents, event)
+				}
+			}
+			Expect(invalidAnnotationEvents).To(HaveLen(1))
+		})
 	})
 })
@@ -11,6 +11,7 @@ import (
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/golang/glog"
+	"k8s.io/api/extensions/v1beta1"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
@@ -26,6 +27,10 @@ func (c *appGwConfigBuilder) getListeners(cbCtx *ConfigBuilderContext) *[]n.Appl
 	if cbCtx.EnvVariables.EnableIstioIntegration {
 		for listenerID, config := range c.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices) {
 			listener := c.newListener(listenerID, config.Protocol)
+			if config.Protocol == n.HTTPS {
+				sslCertificateID := c.appGwIdentifier.sslCertificateID(c.getSslCertificateName(config.Secret))
+				listener.SslCertificate = resourceRef(sslCertificateID)
+			}
 			listeners = append(listeners, listener)
 		}
 	}
@@ -33,19 +38,19 @@ func (c *appGwConfigBuilder) getListeners(cbCtx *ConfigBuilderContext) *[]n.Appl
 	for listenerID, config := range c.getListenerConfigs(cbCtx) {
 		listener := c.newListener(listenerID, config.Protocol)
 		if config.Protocol == n.HTTPS {
-			sslCertificateID := c.appGwIdentifier.sslCertificateID(config.Secret.secretFullName())
+			sslCertificateID := c.appGwIdentifier.sslCertificateID(c.getSslCertificateName(config.Secret))
 			listener.SslCertificate = resourceRef(sslCertificateID)
 		}
 		listeners = append(listeners, listener)
 	}
 
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, cbCtx.ManagedTargets, nil)
 
 		// Listeners we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
 		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedListeners()
 
-		brownfield.LogListeners(existingBlacklisted, existingNonBlacklisted, listeners)
+		c.ownership.AddListeners(existingBlacklisted, existingNonBlacklisted, listeners)
 
 		// MergeListeners would produce unique list of listeners based on Name. Blacklisted listeners,
 		// which have the same name as a managed listeners would be overwritten.
@@ -66,14 +71,17 @@ func (c *appGwConfigBuilder) getListenerConfigs(cbCtx *ConfigBuilderContext) map
 		return *c.mem.listenerConfigs
 	}
 
-	// TODO(draychev): Emit an error event if 2 namespaces define different TLS for the same domain!
+	// The controller prunes ingresses, which serve a listener with another certificate than an ingress of another
+	// namespace. Ingresses of the same namespace are not pruned; The last one in the list sets the secret of the listener.
 	allListeners := make(map[listenerIdentifier]listenerAzConfig)
+	listenersByIngress := make(map[*v1beta1.Ingress]map[listenerIdentifier]listenerAzConfig)
 	for _, ingress := range cbCtx.IngressList {
 		glog.V(5).Infof("Processing Rules for Ingress: %s/%s", ingress.Namespace, ingress.Name)
 		azListenerConfigs := c.getListenersFromIngress(ingress, cbCtx.EnvVariables)
 		for listenerID, azConfig := range azListenerConfigs {
 			allListeners[listenerID] = azConfig
 		}
+		listenersByIngress[ingress] = azListenerConfigs
 	}
 
 	// App Gateway must have at least one listener - the default one!
@@ -85,9 +93,21 @@ func (c *appGwConfigBuilder) getListenerConfigs(cbCtx *ConfigBuilderContext) map
 	}
 
 	c.mem.listenerConfigs = &allListeners
+	c.mem.listenerConfigsByIngress = &listenersByIngress
 	return allListeners
 }
 
+// getListenerConfigsByIngress lists the listener configs each ingress contributes, as determined by getListenerConfigs.
+func (c *appGwConfigBuilder) getListenerConfigsByIngress(cbCtx *ConfigBuilderContext) map[*v1beta1.Ingress]map[listenerIdentifier]listenerAzConfig {
+	if c.mem.listenerConfigsByIngress == nil {
+		c.getListenerConfigs(cbCtx)
+	}
+	if c.mem.listenerConfigsByIngress == nil {
+		return nil
+	}
+	return *c.mem.listenerConfigsByIngress
+}
+
 func (c *appGwConfigBuilder) newListener(listenerID listenerIdentifier, protocol n.ApplicationGatewayProtocol) n.ApplicationGatewayHTTPListener {
 	frontIPConfiguration := *LookupIPConfigurationByType(c.appGw.FrontendIPConfigurations, listenerID.UsePrivateIP)
 	frontendPort := c.lookupFrontendPortByListenerIdentifier(listenerID)
@@ -19,10 +19,8 @@ func (c *appGwConfigBuilder) getFrontendPorts(cbCtx *ConfigBuilderContext) *[]n.
 	allPorts := make(map[Port]interface{})
 
 	if cbCtx.EnvVariables.EnableIstioIntegration {
-		for _, gwy := range cbCtx.IstioGateways {
-			for _, server := range gwy.Spec.Servers {
-				allPorts[Port(server.Port.Number)] = nil
-			}
+		for listenerID := range c.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices) {
+			allPorts[listenerID.FrontendPort] = nil
 		}
 	}
 
@@ -53,12 +51,12 @@ func (c *appGwConfigBuilder) getFrontendPorts(cbCtx *ConfigBuilderContext) *[]n.
 	}
 
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, cbCtx.ManagedTargets, nil)
 
 		// Ports we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
 		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedPorts()
 
-		brownfield.LogPorts(existingBlacklisted, existingNonBlacklisted, frontendPorts)
+		c.ownership.AddPorts(existingBlacklisted, existingNonBlacklisted, frontendPorts)
 
 		// MergePorts would produce unique list of ports based on Name. Blacklisted ports,
 		// which have the same name as a managed ports would be overwritten.
@@ -24,6 +24,10 @@ func (agw Identifier) resourceID(provider string, resourceKind string, resourceP
 		agw.SubscriptionID, agw.ResourceGroup, provider, resourceKind, resourcePath)
 }
 
+func (agw Identifier) appGwID() string {
+	return agw.resourceID("Microsoft.Network", "applicationGateways", agw.AppGwName)
+}
+
 func (agw Identifier) gatewayResourceID(subResourceKind string, resourceName string) string {
 	resourcePath := fmt.Sprintf("%s/%s/%s", agw.AppGwName, subResourceKind, resourceName)
 	return agw.resourceID("Microsoft.Network", "applicationGateways", resourcePath)
@@ -61,6 +65,10 @@ func (agw Identifier) redirectConfigurationID(configurationName string) string {
 	return agw.gatewayResourceID("redirectConfigurations", configurationName)
 }
 
+func (agw Identifier) rewriteRuleSetID(rewriteRuleSetName string) string {
+	return agw.gatewayResourceID("rewriteRuleSets", rewriteRuleSetName)
+}
+
 func (agw Identifier) probeID(probeName string) string {
 	return agw.gatewayResourceID("probes", probeName)
 }
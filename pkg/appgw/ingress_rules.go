@@ -55,6 +55,9 @@ func (c *appGwConfigBuilder) processIngressRule(rule *v1beta1.IngressRule, ingre
 	usePrivateIPForIngress := usePrivateIPFromAnnotation || env.UsePrivateIP == "true"
 
 	cert, secID := c.getCertificate(ingress, rule.Host, ingressHostnameSecretIDMap)
+	if cert == nil {
+		cert, secID = c.getDefaultCertificate(ingress, rule.Host, env)
+	}
 	hasTLS := cert != nil
 	sslRedirect, _ := annotations.IsSslRedirect(ingress)
 	// If a certificate is available we enable only HTTPS; unless ingress is annotated with ssl-redirect - then
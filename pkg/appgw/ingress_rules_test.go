@@ -2,6 +2,8 @@ package appgw
 
 import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -170,6 +172,85 @@ var _ = Describe("Process ingress rules, listeners, and ports", func() {
 			Expect(actualVal.SslRedirectConfigurationName).To(Equal(""))
 		})
 	})
+
+	Context("with the default certificate", func() {
+		defaultSecret := secretIdentifier{Namespace: "default", Name: "tls-default"}
+		env := environment.EnvVariables{DefaultSSLCertificateSecret: "default/tls-default"}
+
+		var cb appGwConfigBuilder
+		var ingress *v1beta1.Ingress
+		BeforeEach(func() {
+			certs := newCertsFixture()
+			cb = newConfigBuilderFixture(&certs)
+			cb.k8sContext.CertificateSecretStore.(*k8scontext.SecretsStore).Cache.Add(defaultSecret.secretKey(), []byte("default"))
+
+			// Ensure there are no certs
+			ingress = tests.NewIngressFixture()
+			ingress.Spec.TLS = nil
+		})
+
+		It("should serve the rules no secret matches with the default certificate", func() {
+			listenerConfigs := cb.getListenersFromIngress(ingress, env)
+			Expect(listenerConfigs).To(HaveKey(expectedListener443))
+			Expect(listenerConfigs[expectedListener443].Protocol).To(BeEquivalentTo("Https"))
+			Expect(listenerConfigs[expectedListener443].Secret).To(Equal(defaultSecret))
+		})
+
+		It("should prefer the secrets of the ingress", func() {
+			ingress = tests.NewIngressFixture()
+			listenerConfigs := cb.getListenersFromIngress(ingress, env)
+			Expect(listenerConfigs[expectedListener443].Secret).To(Equal(secretIdentifier{
+				Namespace: tests.Namespace,
+				Name:      tests.NameOfSecret,
+			}))
+		})
+
+		It("should serve the hosts of TLS sections without a secret with the default certificate", func() {
+			ingress.Spec.TLS = []v1beta1.IngressTLS{{Hosts: []string{tests.Host}}}
+			listenerConfigs := cb.getListenersFromIngress(ingress, env)
+			Expect(listenerConfigs[expectedListener443].Secret).To(Equal(defaultSecret))
+		})
+
+		It("should not use the default certificate for a host whose secret does not exist", func() {
+			ingress.Spec.TLS = []v1beta1.IngressTLS{{Hosts: []string{tests.Host}, SecretName: "mistyped"}}
+			delete(ingress.Annotations, annotations.SslRedirectKey)
+			listenerConfigs := cb.getListenersFromIngress(ingress, env)
+			Expect(listenerConfigs).To(HaveKey(expectedListener80))
+			Expect(listenerConfigs).ToNot(HaveKey(expectedListener443))
+		})
+
+		It("should not use the default certificate for a host no TLS section lists", func() {
+			ingress.Spec.TLS = []v1beta1.IngressTLS{{Hosts: []string{tests.OtherHost}, SecretName: tests.NameOfSecret}}
+			delete(ingress.Annotations, annotations.SslRedirectKey)
+			listenerConfigs := cb.getListenersFromIngress(ingress, env)
+			Expect(listenerConfigs).To(HaveKey(expectedListener80))
+			Expect(listenerConfigs).ToNot(HaveKey(expectedListener443))
+		})
+
+		It("should not use the default certificate for ingresses opting out", func() {
+			ingress.Annotations[annotations.DisableDefaultCertificateKey] = "true"
+			listenerConfigs := cb.getListenersFromIngress(ingress, env)
+			Expect(listenerConfigs).To(HaveKey(expectedListener80))
+			Expect(listenerConfigs).ToNot(HaveKey(expectedListener443))
+		})
+
+		It("should not use a default certificate which does not exist", func() {
+			env := environment.EnvVariables{DefaultSSLCertificateSecret: "default/missing"}
+			listenerConfigs := cb.getListenersFromIngress(ingress, env)
+			Expect(listenerConfigs).To(HaveKey(expectedListener80))
+			Expect(listenerConfigs).ToNot(HaveKey(expectedListener443))
+		})
+
+		It("should upload the default certificate", func() {
+			cbCtx := &ConfigBuilderContext{
+				IngressList:  []*v1beta1.Ingress{ingress},
+				EnvVariables: env,
+			}
+			sslCertificates := cb.getSslCertificates(cbCtx)
+			Expect(*sslCertificates).To(HaveLen(1))
+			Expect(*(*sslCertificates)[0].Name).To(Equal(cb.getSslCertificateName(defaultSecret)))
+		})
+	})
 })
 
 func getMapKeys(m *map[listenerIdentifier]listenerAzConfig) []listenerIdentifier {
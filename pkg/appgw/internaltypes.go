@@ -23,15 +23,18 @@ import (
 )
 
 const (
-	prefixHTTPSettings = "bp"
-	prefixProbe        = "pb"
-	prefixPool         = "pool"
-	prefixPort         = "fp"
-	prefixListener     = "fl"
-	prefixPathMap      = "url"
-	prefixRoutingRule  = "rr"
-	prefixRedirect     = "sslr"
-	prefixPathRule     = "pr"
+	prefixHTTPSettings   = "bp"
+	prefixProbe          = "pb"
+	prefixPool           = "pool"
+	prefixPort           = "fp"
+	prefixListener       = "fl"
+	prefixPathMap        = "url"
+	prefixRoutingRule    = "rr"
+	prefixRedirect       = "sslr"
+	prefixPathRule       = "pr"
+	prefixRouteRedirect  = "rd"
+	prefixRewriteRuleSet = "rw"
+	prefixSslCertificate = "cert"
 )
 
 type backendIdentifier struct {
@@ -105,6 +108,10 @@ func (s secretIdentifier) secretFullName() string {
 	return fmt.Sprintf("%v-%v", s.Namespace, s.Name)
 }
 
+func generateSslCertificateName(certificateHash string) string {
+	return formatPropName(fmt.Sprintf("%s%s-%s", agPrefix, prefixSslCertificate, certificateHash))
+}
+
 func getResourceKey(namespace, name string) string {
 	return formatPropName(fmt.Sprintf("%v/%v", namespace, name))
 }
@@ -121,6 +128,22 @@ func generateAddressPoolName(serviceName string, servicePort string, backendPort
 	return formatPropName(fmt.Sprintf("%s%s-%v-%v-bp-%v", agPrefix, prefixPool, serviceName, servicePort, backendPort))
 }
 
+func generateWeightedAddressPoolName(namespace, virtualService string, ruleIdx int) string {
+	return formatPropName(fmt.Sprintf("%s%s-%s-%s-weighted-%d", agPrefix, prefixPool, namespace, virtualService, ruleIdx))
+}
+
+func generateIstioRouteHTTPSettingsName(namespace, virtualService string, ruleIdx int) string {
+	return formatPropName(fmt.Sprintf("%s%s-%s-%s-route-%d", agPrefix, prefixHTTPSettings, namespace, virtualService, ruleIdx))
+}
+
+func generateIstioRouteRedirectName(namespace, virtualService string, ruleIdx int, listenerID listenerIdentifier) string {
+	return formatPropName(fmt.Sprintf("%s%s-%s-%s-%d-%s", agPrefix, prefixRouteRedirect, namespace, virtualService, ruleIdx, generateListenerName(listenerID)))
+}
+
+func generateRewriteRuleSetName(namespace, virtualService string, ruleIdx int) string {
+	return formatPropName(fmt.Sprintf("%s%s-%s-%s-%d", agPrefix, prefixRewriteRuleSet, namespace, virtualService, ruleIdx))
+}
+
 func generateFrontendPortName(port Port) string {
 	return formatPropName(fmt.Sprintf("%s%s-%v", agPrefix, prefixPort, port))
 }
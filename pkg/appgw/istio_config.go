@@ -6,8 +6,11 @@
 package appgw
 
 import (
+	"fmt"
+
 	"github.com/golang/glog"
 	"github.com/knative/pkg/apis/istio/v1alpha3"
+	v1 "k8s.io/api/core/v1"
 )
 
 func (c *appGwConfigBuilder) resolveIstioPortName(portName string, destinationID *istioDestinationIdentifier) map[Port]interface{} {
@@ -54,8 +57,46 @@ func generateIstioDestinationID(virtualService *v1alpha3.VirtualService, destina
 			Name:      virtualService.Name,
 		},
 
-		DestinationHost:   destination.Host,
-		DestinationSubset: destination.Subset,
-		DestinationPort:   destination.Port.Number,
+		DestinationHost:     destination.Host,
+		DestinationSubset:   destination.Subset,
+		DestinationPort:     destination.Port.Number,
+		DestinationPortName: destination.Port.Name,
+	}
+}
+
+// servicePort is the port of the service the destination selects, by number or by name; It is blank when the
+// destination selects no port.
+func (d istioDestinationIdentifier) servicePort() string {
+	if d.DestinationPort != 0 {
+		return fmt.Sprint(d.DestinationPort)
+	}
+	return d.DestinationPortName
+}
+
+// selectsServicePort tells whether the destination selects the given port of its service. As in Istio, a destination,
+// which selects no port, selects the only port of a service.
+func (d istioDestinationIdentifier) selectsServicePort(servicePort v1.ServicePort, tcpPortCount int) bool {
+	switch {
+	case d.DestinationPort != 0:
+		return uint32(servicePort.Port) == d.DestinationPort || servicePort.TargetPort.String() == fmt.Sprint(d.DestinationPort)
+	case d.DestinationPortName != "":
+		return servicePort.Name == d.DestinationPortName
+	}
+	return tcpPortCount == 1
+}
+
+// name identifies the destination in logs and events, e.g. "reviews" or "reviews/v2" for a subset.
+func (d istioDestinationIdentifier) name() string {
+	if d.DestinationSubset == "" {
+		return d.DestinationHost
+	}
+	return fmt.Sprintf("%s/%s", d.DestinationHost, d.DestinationSubset)
+}
+
+// backendFullName names the backend pool and HTTP settings of the destination; Each subset gets its own.
+func (d istioDestinationIdentifier) backendFullName() string {
+	if d.DestinationSubset == "" {
+		return d.serviceFullName()
 	}
+	return fmt.Sprintf("%s-%s", d.serviceFullName(), d.DestinationSubset)
 }
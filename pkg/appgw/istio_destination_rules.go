@@ -0,0 +1,159 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+)
+
+// getIstioDestinationRule finds the DestinationRule of the service of the given destination.
+func getIstioDestinationRule(cbCtx *ConfigBuilderContext, destinationID istioDestinationIdentifier) *v1alpha3.DestinationRule {
+	for _, destinationRule := range cbCtx.IstioDestinationRules {
+		if istioHostNamesService(destinationRule.Spec.Host, destinationRule.Namespace, destinationID.serviceIdentifier) {
+			return destinationRule
+		}
+	}
+	return nil
+}
+
+// istioHostNamesService tells whether the host of a DestinationRule names the given service. As in Istio, a short name
+// like "reviews" names a service in the namespace of the rule.
+func istioHostNamesService(host string, namespace string, service serviceIdentifier) bool {
+	switch host {
+	case service.Name:
+		return namespace == service.Namespace
+	case fmt.Sprintf("%s.%s", service.Name, service.Namespace),
+		fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", service.Name, service.Namespace):
+		return true
+	}
+	return false
+}
+
+// getIstioDestinationSubset finds the subset of the given destination in the DestinationRule of its service.
+func getIstioDestinationSubset(cbCtx *ConfigBuilderContext, destinationID istioDestinationIdentifier) *v1alpha3.Subset {
+	destinationRule := getIstioDestinationRule(cbCtx, destinationID)
+	if destinationRule == nil {
+		return nil
+	}
+	for idx := range destinationRule.Spec.Subsets {
+		if destinationRule.Spec.Subsets[idx].Name == destinationID.DestinationSubset {
+			return &destinationRule.Spec.Subsets[idx]
+		}
+	}
+	return nil
+}
+
+// getIstioUpstreamTLS finds the TLS settings App Gateway connects to the backends of the given destination with. The
+// traffic policy of a subset overrides the one of the DestinationRule, and settings for the port of the destination
+// override the settings for all ports.
+func getIstioUpstreamTLS(cbCtx *ConfigBuilderContext, destinationID istioDestinationIdentifier) (*v1alpha3.DestinationRule, *v1alpha3.TLSSettings) {
+	destinationRule := getIstioDestinationRule(cbCtx, destinationID)
+	if destinationRule == nil {
+		return nil, nil
+	}
+
+	var policies []*v1alpha3.TrafficPolicy
+	if destinationID.DestinationSubset != "" {
+		if subset := getIstioDestinationSubset(cbCtx, destinationID); subset != nil && subset.TrafficPolicy != nil {
+			policies = append(policies, subset.TrafficPolicy)
+		}
+	}
+	if destinationRule.Spec.TrafficPolicy != nil {
+		policies = append(policies, destinationRule.Spec.TrafficPolicy)
+	}
+
+	for _, policy := range policies {
+		for _, portPolicy := range policy.PortLevelSettings {
+			if isIstioPortOfDestination(portPolicy.Port, destinationID) {
+				return destinationRule, portPolicy.TLS
+			}
+		}
+		if policy.TLS != nil {
+			return destinationRule, policy.TLS
+		}
+	}
+	return destinationRule, nil
+}
+
+// filterIstioSubsetAddresses leaves out the addresses of the given endpoints, which do not belong to a pod with the
+// labels of the subset.
+func (c *appGwConfigBuilder) filterIstioSubsetAddresses(destinationID istioDestinationIdentifier, subsetLabels map[string]string, subset v1.EndpointSubset) v1.EndpointSubset {
+	podNames := make(map[string]interface{})
+	podIPs := make(map[string]interface{})
+	for _, pod := range c.k8sContext.ListPodsByServiceSelector(subsetLabels) {
+		if pod.Namespace != destinationID.serviceIdentifier.Namespace {
+			continue
+		}
+		podNames[pod.Name] = nil
+		if pod.Status.PodIP != "" {
+			podIPs[pod.Status.PodIP] = nil
+		}
+	}
+
+	filtered := v1.EndpointSubset{Ports: subset.Ports}
+	for _, address := range subset.Addresses {
+		if address.TargetRef != nil && address.TargetRef.Kind == "Pod" {
+			if _, exists := podNames[address.TargetRef.Name]; exists {
+				filtered.Addresses = append(filtered.Addresses, address)
+			}
+			continue
+		}
+		if _, exists := podIPs[address.IP]; exists {
+			filtered.Addresses = append(filtered.Addresses, address)
+		}
+	}
+	return filtered
+}
+
+// reportIstioDestinationIssues emits events for the destinations, which name an unknown subset, and for the
+// DestinationRules, which ask for upstream TLS App Gateway cannot originate.
+func (c *appGwConfigBuilder) reportIstioDestinationIssues(cbCtx *ConfigBuilderContext) {
+	reportedRules := make(map[*v1alpha3.DestinationRule]interface{})
+	for _, virtualService := range cbCtx.IstioVirtualServices {
+		for ruleIdx := range virtualService.Spec.HTTP {
+			for _, routeDestination := range virtualService.Spec.HTTP[ruleIdx].Route {
+				destinationID := generateIstioDestinationID(virtualService, &routeDestination.Destination)
+				if destinationID.DestinationSubset != "" && getIstioDestinationSubset(cbCtx, destinationID) == nil {
+					logLine := fmt.Sprintf("HTTP route %d of VirtualService %s/%s routes to subset %s, which no DestinationRule of service %s defines; The default backend serves the route", ruleIdx, virtualService.Namespace, virtualService.Name, destinationID.DestinationSubset, destinationID.serviceKey())
+					glog.Warning(logLine)
+					c.recorder.Event(virtualService, v1.EventTypeWarning, events.ReasonSubsetNotFound, logLine)
+				}
+
+				destinationRule, tls := getIstioUpstreamTLS(cbCtx, destinationID)
+				if tls == nil || isSupportedIstioUpstreamTLSMode(tls.Mode) {
+					continue
+				}
+				if _, reported := reportedRules[destinationRule]; reported {
+					continue
+				}
+				reportedRules[destinationRule] = nil
+				logLine := fmt.Sprintf("DestinationRule %s/%s uses TLS mode %s; App Gateway connects to backends with modes %s and %s only; Connections use plain HTTP", destinationRule.Namespace, destinationRule.Name, tls.Mode, v1alpha3.TLSmodeDisable, v1alpha3.TLSmodeSimple)
+				glog.Warning(logLine)
+				c.recorder.Event(destinationRule, v1.EventTypeWarning, events.ReasonUnsupportedFeature, logLine)
+			}
+		}
+	}
+}
+
+// isIstioPortOfDestination tells whether the port of a port level traffic policy is the port the given destination
+// selects, by number or by name.
+func isIstioPortOfDestination(port v1alpha3.PortSelector, destinationID istioDestinationIdentifier) bool {
+	if port.Number != 0 {
+		return port.Number == destinationID.DestinationPort
+	}
+	return port.Name != "" && port.Name == destinationID.DestinationPortName
+}
+
+func isSupportedIstioUpstreamTLSMode(mode v1alpha3.TLSmode) bool {
+	return mode == "" || mode == v1alpha3.TLSmodeDisable || mode == v1alpha3.TLSmodeSimple
+}
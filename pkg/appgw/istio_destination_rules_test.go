@@ -0,0 +1,209 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/knative/pkg/apis/istio/common/v1alpha1"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test Istio DestinationRules", func() {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: tests.Namespace},
+		Spec: v1.ServiceSpec{
+			Ports:    []v1.ServicePort{{Protocol: v1.ProtocolTCP, Port: 80, TargetPort: intstr.FromInt(9080)}},
+			Selector: map[string]string{"app": "reviews"},
+		},
+	}
+	newPod := func(name, ip, version string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: tests.Namespace, Labels: map[string]string{"app": "reviews", "version": version}},
+			Status:     v1.PodStatus{PodIP: ip},
+		}
+	}
+	newAddress := func(pod string, ip string) v1.EndpointAddress {
+		return v1.EndpointAddress{IP: ip, TargetRef: &v1.ObjectReference{Kind: "Pod", Name: pod, Namespace: tests.Namespace}}
+	}
+	endpoints := &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: tests.Namespace},
+		Subsets: []v1.EndpointSubset{{
+			Addresses: []v1.EndpointAddress{
+				newAddress("reviews-v1-a", "10.0.0.1"),
+				newAddress("reviews-v1-b", "10.0.0.2"),
+				newAddress("reviews-v2-a", "10.0.1.1"),
+			},
+			Ports: []v1.EndpointPort{{Protocol: v1.ProtocolTCP, Port: 9080}},
+		}},
+	}
+
+	destinationRule := &v1alpha3.DestinationRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: tests.Namespace},
+		Spec: v1alpha3.DestinationRuleSpec{
+			Host: "reviews",
+			Subsets: []v1alpha3.Subset{
+				{Name: "v1", Labels: map[string]string{"version": "v1"}},
+				{
+					Name:   "v2",
+					Labels: map[string]string{"version": "v2"},
+					TrafficPolicy: &v1alpha3.TrafficPolicy{
+						TLS: &v1alpha3.TLSSettings{Mode: v1alpha3.TLSmodeSimple, Sni: "reviews.contoso.com"},
+					},
+				},
+			},
+		},
+	}
+	newDestination := func(subset string) v1alpha3.HTTPRouteDestination {
+		return v1alpha3.HTTPRouteDestination{
+			Destination: v1alpha3.Destination{Host: "reviews", Subset: subset, Port: v1alpha3.PortSelector{Number: 80}},
+		}
+	}
+	destinationIDOf := func(virtualService *v1alpha3.VirtualService, subset string) istioDestinationIdentifier {
+		destination := newDestination(subset).Destination
+		return generateIstioDestinationID(virtualService, &destination)
+	}
+	newVirtualService := func(subsets ...string) *v1alpha3.VirtualService {
+		virtualService := &v1alpha3.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: tests.Namespace},
+			Spec:       v1alpha3.VirtualServiceSpec{Hosts: []string{tests.Host}},
+		}
+		for _, subset := range subsets {
+			virtualService.Spec.HTTP = append(virtualService.Spec.HTTP, v1alpha3.HTTPRoute{
+				Match: []v1alpha3.HTTPMatchRequest{{URI: &v1alpha1.StringMatch{Prefix: "/" + subset}}},
+				Route: []v1alpha3.HTTPRouteDestination{newDestination(subset)},
+			})
+		}
+		return virtualService
+	}
+	gateway := &v1alpha3.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: tests.Namespace},
+		Spec: v1alpha3.GatewaySpec{
+			Servers: []v1alpha3.Server{{
+				Port:  v1alpha3.Port{Number: 80, Protocol: v1alpha3.ProtocolHTTP},
+				Hosts: []string{tests.Host},
+			}},
+		},
+	}
+	newContext := func(virtualService *v1alpha3.VirtualService, destinationRules ...*v1alpha3.DestinationRule) *ConfigBuilderContext {
+		cbCtx := &ConfigBuilderContext{
+			IstioGateways:         []*v1alpha3.Gateway{gateway},
+			IstioVirtualServices:  []*v1alpha3.VirtualService{virtualService},
+			IstioDestinationRules: destinationRules,
+		}
+		cbCtx.EnvVariables.EnableIstioIntegration = true
+		return cbCtx
+	}
+
+	getAddresses := func(pool *n.ApplicationGatewayBackendAddressPool) []string {
+		var addresses []string
+		for _, address := range *pool.BackendAddresses {
+			addresses = append(addresses, to.String(address.IPAddress))
+		}
+		return addresses
+	}
+
+	var cb appGwConfigBuilder
+	BeforeEach(func() {
+		cb = newConfigBuilderFixture(nil)
+		_ = cb.k8sContext.Caches.Service.Add(service)
+		_ = cb.k8sContext.Caches.Endpoints.Add(endpoints)
+		_ = cb.k8sContext.Caches.Pods.Add(newPod("reviews-v1-a", "10.0.0.1", "v1"))
+		_ = cb.k8sContext.Caches.Pods.Add(newPod("reviews-v1-b", "10.0.0.2", "v1"))
+		_ = cb.k8sContext.Caches.Pods.Add(newPod("reviews-v2-a", "10.0.1.1", "v2"))
+	})
+
+	Context("Test hosts of DestinationRules", func() {
+		It("should resolve short names in the namespace of the rule", func() {
+			reviews := serviceIdentifier{Namespace: tests.Namespace, Name: "reviews"}
+			Expect(istioHostNamesService("reviews", tests.Namespace, reviews)).To(BeTrue())
+			Expect(istioHostNamesService("reviews", "other", reviews)).To(BeFalse())
+			Expect(istioHostNamesService("reviews."+tests.Namespace+".svc.cluster.local", "other", reviews)).To(BeTrue())
+			Expect(istioHostNamesService("ratings", tests.Namespace, reviews)).To(BeFalse())
+		})
+	})
+
+	Context("Test subsets", func() {
+		It("should create a pool for each subset", func() {
+			virtualService := newVirtualService("v1", "v2")
+			poolByDestination := cb.newIstioBackendPoolMap(newContext(virtualService, destinationRule))
+
+			v1Pool := poolByDestination[destinationIDOf(virtualService, "v1")]
+			Expect(*v1Pool.Name).To(Equal(generateAddressPoolName(tests.Namespace+"-reviews-v1", "80", 9080)))
+			Expect(getAddresses(v1Pool)).To(ConsistOf("10.0.0.1", "10.0.0.2"))
+
+			v2Pool := poolByDestination[destinationIDOf(virtualService, "v2")]
+			Expect(*v2Pool.Name).To(Equal(generateAddressPoolName(tests.Namespace+"-reviews-v2", "80", 9080)))
+			Expect(getAddresses(v2Pool)).To(ConsistOf("10.0.1.1"))
+		})
+
+		It("should serve unknown subsets with the default pool and emit an event", func() {
+			virtualService := newVirtualService("v3")
+			cbCtx := newContext(virtualService, destinationRule)
+			poolByDestination := cb.newIstioBackendPoolMap(cbCtx)
+			Expect(*poolByDestination[destinationIDOf(virtualService, "v3")].Name).To(Equal(DefaultBackendAddressPoolName))
+
+			_ = cb.getIstioPathMaps(cbCtx)
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonSubsetNotFound))
+		})
+	})
+
+	Context("Test TLS settings", func() {
+		It("should connect to backends with TLS when the DestinationRule asks for it", func() {
+			virtualService := newVirtualService("v1", "v2")
+			_, settingsByDestination, _, err := cb.getIstioDestinationsAndSettingsMap(newContext(virtualService, destinationRule))
+			Expect(err).ToNot(HaveOccurred())
+
+			v1Settings := settingsByDestination[destinationIDOf(virtualService, "v1")]
+			Expect(v1Settings.Protocol).To(Equal(n.HTTP))
+			Expect(v1Settings.HostName).To(BeNil())
+
+			v2Settings := settingsByDestination[destinationIDOf(virtualService, "v2")]
+			Expect(v2Settings.Protocol).To(Equal(n.HTTPS))
+			Expect(*v2Settings.HostName).To(Equal("reviews.contoso.com"))
+			Expect(*v2Settings.Name).ToNot(Equal(*v1Settings.Name))
+		})
+
+		It("should prefer settings for the port of the destination", func() {
+			portRule := destinationRule.DeepCopy()
+			portRule.Spec.TrafficPolicy = &v1alpha3.TrafficPolicy{
+				TLS: &v1alpha3.TLSSettings{Mode: v1alpha3.TLSmodeSimple},
+				PortLevelSettings: []v1alpha3.PortTrafficPolicy{
+					{Port: v1alpha3.PortSelector{Number: 80}, TLS: &v1alpha3.TLSSettings{Mode: v1alpha3.TLSmodeDisable}},
+				},
+			}
+			virtualService := newVirtualService("v1")
+			_, tls := getIstioUpstreamTLS(newContext(virtualService, portRule), destinationIDOf(virtualService, "v1"))
+			Expect(tls.Mode).To(Equal(v1alpha3.TLSmodeDisable))
+		})
+
+		It("should emit an event for TLS modes App Gateway does not support", func() {
+			mutualRule := destinationRule.DeepCopy()
+			mutualRule.Spec.TrafficPolicy = &v1alpha3.TrafficPolicy{TLS: &v1alpha3.TLSSettings{Mode: v1alpha3.TLSmodeIstioMutual}}
+			cbCtx := newContext(newVirtualService("v1"), mutualRule)
+
+			settings, _, _, _ := cb.getIstioDestinationsAndSettingsMap(cbCtx)
+			Expect(settings).To(HaveLen(1))
+			Expect(settings[0].Protocol).To(Equal(n.HTTP))
+
+			_ = cb.getIstioPathMaps(cbCtx)
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonUnsupportedFeature))
+		})
+	})
+})
@@ -6,39 +6,78 @@
 package appgw
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/golang/glog"
 	"github.com/knative/pkg/apis/istio/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 )
 
+// getListenerConfigsFromIstio creates the listener configs for the servers of the given Istio Gateways, with a listener
+// for each host of a VirtualService the server exposes. HTTP listeners of servers with tls.httpsRedirect set carry the
+// name of the redirect configuration, which sends their requests to the HTTPS listener of the same host; The HTTPS
+// listener carries the same name, as for Ingresses annotated with ssl-redirect.
 func (c *appGwConfigBuilder) getListenerConfigsFromIstio(istioGateways []*v1alpha3.Gateway, istioVirtualServices []*v1alpha3.VirtualService) map[listenerIdentifier]listenerAzConfig {
-	knownHosts := make(map[string]interface{})
-	for _, virtualService := range istioVirtualServices {
-		for _, host := range virtualService.Spec.Hosts {
-			knownHosts[host] = nil
-		}
+	if c.mem.istioListenerConfigs != nil {
+		return *c.mem.istioListenerConfigs
 	}
 
 	allListeners := make(map[listenerIdentifier]listenerAzConfig)
+	redirectingListeners := make(map[listenerIdentifier]*v1alpha3.Gateway)
 	for _, igwy := range istioGateways {
-		for _, server := range igwy.Spec.Servers {
-			if server.Port.Protocol != v1alpha3.ProtocolHTTP {
+		for serverIdx := range igwy.Spec.Servers {
+			server := &igwy.Spec.Servers[serverIdx]
+			var config listenerAzConfig
+			switch server.Port.Protocol {
+			case v1alpha3.ProtocolHTTP:
+				config = listenerAzConfig{Protocol: n.HTTP}
+			case v1alpha3.ProtocolHTTPS:
+				secretID := c.getIstioServerSecret(igwy, server)
+				if secretID == nil {
+					continue
+				}
+				config = listenerAzConfig{Protocol: n.HTTPS, Secret: *secretID}
+			default:
 				glog.Infof("[istio] AGIC does not support Gateway with Server.Port.Protocol=%+v", server.Port.Protocol)
 				continue
 			}
-			for _, host := range server.Hosts {
-				if _, exist := knownHosts[host]; !exist {
-					continue
-				}
+			for _, hostName := range c.getIstioServerHostNames(igwy, server, istioVirtualServices) {
 				listenerID := listenerIdentifier{
 					FrontendPort: Port(server.Port.Number),
-					HostName:     host,
+					HostName:     hostName,
+				}
+				allListeners[listenerID] = config
+				if config.Protocol == n.HTTP && server.TLS != nil && server.TLS.HTTPSRedirect {
+					redirectingListeners[listenerID] = igwy
 				}
-				allListeners[listenerID] = listenerAzConfig{Protocol: n.HTTP}
 			}
 		}
 	}
 
+	for listenerID, igwy := range redirectingListeners {
+		httpsListenerID, exists := findIstioHTTPSListener(allListeners, listenerID.HostName)
+		if !exists {
+			logLine := fmt.Sprintf("Gateway %s/%s redirects host %s to HTTPS, but no HTTPS server with a certificate serves the host", igwy.Namespace, igwy.Name, listenerID.HostName)
+			glog.Warning(logLine)
+			c.recorder.Event(igwy, v1.EventTypeWarning, events.ReasonRedirectWithNoTLS, logLine)
+			continue
+		}
+		redirectName := generateSSLRedirectConfigurationName(httpsListenerID)
+
+		httpsConfig := allListeners[httpsListenerID]
+		httpsConfig.SslRedirectConfigurationName = redirectName
+		allListeners[httpsListenerID] = httpsConfig
+
+		httpConfig := allListeners[listenerID]
+		httpConfig.SslRedirectConfigurationName = redirectName
+		allListeners[listenerID] = httpConfig
+	}
+
 	// App Gateway must have at least one listener - the default one!
 	if len(allListeners) == 0 {
 		allListeners[defaultFrontendListenerIdentifier()] = listenerAzConfig{
@@ -47,5 +86,156 @@ func (c *appGwConfigBuilder) getListenerConfigsFromIstio(istioGateways []*v1alph
 		}
 	}
 
+	c.mem.istioListenerConfigs = &allListeners
 	return allListeners
 }
+
+// getIstioServerSecret finds the TLS secret of an HTTPS server of an Istio Gateway. App Gateway cannot read the
+// certificate files of an Istio ingress gateway, so the certificate must be given as a Kubernetes secret named by
+// tls.credentialName, in the namespace of the Gateway.
+func (c *appGwConfigBuilder) getIstioServerSecret(igwy *v1alpha3.Gateway, server *v1alpha3.Server) *secretIdentifier {
+	if server.TLS == nil || server.TLS.CredentialName == "" {
+		logLine := fmt.Sprintf("HTTPS server on port %d of Gateway %s/%s has no tls.credentialName; App Gateway can use certificates from Kubernetes secrets only", server.Port.Number, igwy.Namespace, igwy.Name)
+		glog.Warning(logLine)
+		c.recorder.Event(igwy, v1.EventTypeWarning, events.ReasonUnsupportedFeature, logLine)
+		return nil
+	}
+
+	if server.TLS.Mode != "" && server.TLS.Mode != v1alpha3.TLSModeSimple {
+		logLine := fmt.Sprintf("HTTPS server on port %d of Gateway %s/%s uses TLS mode %s; App Gateway supports mode %s only", server.Port.Number, igwy.Namespace, igwy.Name, server.TLS.Mode, v1alpha3.TLSModeSimple)
+		glog.Warning(logLine)
+		c.recorder.Event(igwy, v1.EventTypeWarning, events.ReasonUnsupportedFeature, logLine)
+		return nil
+	}
+
+	secretID := secretIdentifier{
+		Namespace: igwy.Namespace,
+		Name:      server.TLS.CredentialName,
+	}
+	cert := c.k8sContext.GetIstioGatewayCertificate(igwy, secretID.Name)
+	c.reportUnusableSecret(igwy, secretID, cert)
+	if cert == nil {
+		return nil
+	}
+	return &secretID
+}
+
+// findIstioHTTPSListener finds the HTTPS listener requests for the given host are redirected to; Port 443 is preferred,
+// otherwise the lowest port is taken.
+func findIstioHTTPSListener(listeners map[listenerIdentifier]listenerAzConfig, host string) (listenerIdentifier, bool) {
+	var found *listenerIdentifier
+	for listenerID, config := range listeners {
+		if config.Protocol != n.HTTPS || listenerID.HostName != host {
+			continue
+		}
+		if found == nil || listenerID.FrontendPort == 443 || (found.FrontendPort != 443 && listenerID.FrontendPort < found.FrontendPort) {
+			candidate := listenerID
+			found = &candidate
+		}
+	}
+	if found == nil {
+		return listenerIdentifier{}, false
+	}
+	return *found, true
+}
+
+// getIstioServerHostNames lists the host names of the listeners of a Gateway server; A listener is created for each host
+// of a VirtualService, which the server exposes. The host name of a listener taking requests for any host is blank.
+func (c *appGwConfigBuilder) getIstioServerHostNames(igwy *v1alpha3.Gateway, server *v1alpha3.Server, istioVirtualServices []*v1alpha3.VirtualService) []string {
+	hostNames := make(map[string]interface{})
+	for _, virtualService := range istioVirtualServices {
+		for _, serverHost := range server.Hosts {
+			serverHost, exposed := getIstioServerHostForNamespace(igwy, serverHost, virtualService.Namespace)
+			if !exposed {
+				continue
+			}
+			for _, host := range virtualService.Spec.Hosts {
+				hostName, matches := intersectIstioHosts(serverHost, host)
+				if !matches {
+					continue
+				}
+				if strings.HasPrefix(hostName, "*") && hostName != "*" {
+					logLine := fmt.Sprintf("Host %s of VirtualService %s/%s served by Gateway %s/%s is a wildcard; App Gateway listeners take a single host name or any host", host, virtualService.Namespace, virtualService.Name, igwy.Namespace, igwy.Name)
+					glog.Warning(logLine)
+					c.recorder.Event(virtualService, v1.EventTypeWarning, events.ReasonUnsupportedFeature, logLine)
+					continue
+				}
+				hostNames[istioListenerHostName(hostName)] = nil
+			}
+		}
+	}
+
+	var sorted []string
+	for hostName := range hostNames {
+		sorted = append(sorted, hostName)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// getIstioServerHostForNamespace strips the namespace from a host of a Gateway server given as "namespace/host", and
+// tells whether the server exposes the host to VirtualServices of the given namespace.
+func getIstioServerHostForNamespace(igwy *v1alpha3.Gateway, serverHost string, namespace string) (string, bool) {
+	separator := strings.Index(serverHost, "/")
+	if separator < 0 {
+		return serverHost, true
+	}
+	hostNamespace, host := serverHost[:separator], serverHost[separator+1:]
+	switch hostNamespace {
+	case "*":
+		return host, true
+	case ".":
+		return host, namespace == igwy.Namespace
+	}
+	return host, namespace == hostNamespace
+}
+
+// intersectIstioHosts finds the more specific of two hosts, either of which may be a wildcard like "*" or
+// "*.contoso.com"; The hosts do not match if neither includes the other.
+func intersectIstioHosts(first string, second string) (string, bool) {
+	if first == second {
+		return first, true
+	}
+	if istioHostIncludes(first, second) {
+		return second, true
+	}
+	if istioHostIncludes(second, first) {
+		return first, true
+	}
+	return "", false
+}
+
+// istioHostIncludes tells whether the given wildcard host matches all the names the other host matches.
+func istioHostIncludes(wildcard string, host string) bool {
+	if wildcard == "*" {
+		return true
+	}
+	if !strings.HasPrefix(wildcard, "*") {
+		return false
+	}
+	suffix := wildcard[1:]
+	return strings.HasSuffix(host, suffix) && len(host) > len(suffix)
+}
+
+// istioListenerHostName turns the host of a VirtualService into the host name of a listener.
+func istioListenerHostName(host string) string {
+	if host == "*" {
+		return ""
+	}
+	return host
+}
+
+// isIstioListenerOfVirtualService tells whether the given listener takes requests for one of the hosts of the given
+// VirtualService.
+func isIstioListenerOfVirtualService(listenerID listenerIdentifier, virtualService *v1alpha3.VirtualService) bool {
+	listenerHost := listenerID.HostName
+	if listenerHost == "" {
+		listenerHost = "*"
+	}
+	for _, host := range virtualService.Spec.Hosts {
+		if hostName, matches := intersectIstioHosts(listenerHost, host); matches && hostName == listenerHost {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test Istio Gateway servers", func() {
+	httpServer := v1alpha3.Server{
+		Port:  v1alpha3.Port{Number: 80, Protocol: v1alpha3.ProtocolHTTP},
+		Hosts: []string{tests.Host},
+	}
+	httpsServer := v1alpha3.Server{
+		Port:  v1alpha3.Port{Number: 443, Protocol: v1alpha3.ProtocolHTTPS},
+		Hosts: []string{tests.Host},
+		TLS:   &v1alpha3.TLSOptions{Mode: v1alpha3.TLSModeSimple, CredentialName: tests.NameOfSecret},
+	}
+	newGateway := func(servers ...v1alpha3.Server) *v1alpha3.Gateway {
+		return &v1alpha3.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: tests.Namespace},
+			Spec:       v1alpha3.GatewaySpec{Servers: servers},
+		}
+	}
+	virtualService := &v1alpha3.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{Name: "virtual-service", Namespace: tests.Namespace},
+		Spec:       v1alpha3.VirtualServiceSpec{Hosts: []string{tests.Host}},
+	}
+	httpListenerID := listenerIdentifier{FrontendPort: 80, HostName: tests.Host}
+	httpsListenerID := listenerIdentifier{FrontendPort: 443, HostName: tests.Host}
+	secretID := secretIdentifier{Namespace: tests.Namespace, Name: tests.NameOfSecret}
+
+	var cb appGwConfigBuilder
+	BeforeEach(func() {
+		cb = newConfigBuilderFixture(nil)
+	})
+
+	newContext := func(gateway *v1alpha3.Gateway) *ConfigBuilderContext {
+		cbCtx := &ConfigBuilderContext{
+			IstioGateways:        []*v1alpha3.Gateway{gateway},
+			IstioVirtualServices: []*v1alpha3.VirtualService{virtualService},
+		}
+		cbCtx.EnvVariables.EnableIstioIntegration = true
+		return cbCtx
+	}
+
+	Context("Test HTTPS servers", func() {
+		It("should create an HTTPS listener with the certificate of the credential", func() {
+			cbCtx := newContext(newGateway(httpsServer))
+			configs := cb.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices)
+			Expect(configs).To(Equal(map[listenerIdentifier]listenerAzConfig{
+				httpsListenerID: {Protocol: "Https", Secret: secretID},
+			}))
+
+			certs := cb.getSslCertificates(cbCtx)
+			Expect(*certs).To(HaveLen(1))
+			Expect(*(*certs)[0].Name).To(Equal(secretID.secretFullName()))
+		})
+
+		It("should skip servers with unsupported TLS settings", func() {
+			passThrough := httpsServer
+			passThrough.TLS = &v1alpha3.TLSOptions{Mode: v1alpha3.TLSModePassThrough, CredentialName: tests.NameOfSecret}
+			cbCtx := newContext(newGateway(passThrough))
+			configs := cb.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices)
+			Expect(configs).To(Equal(map[listenerIdentifier]listenerAzConfig{
+				defaultFrontendListenerIdentifier(): {Protocol: "Http"},
+			}))
+
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonUnsupportedFeature))
+		})
+
+		It("should emit an event when the secret is missing", func() {
+			missingSecret := httpsServer
+			missingSecret.TLS = &v1alpha3.TLSOptions{CredentialName: "missing"}
+			cbCtx := newContext(newGateway(missingSecret))
+			configs := cb.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices)
+			Expect(configs).ToNot(HaveKey(httpsListenerID))
+
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonSecretNotFound))
+		})
+	})
+
+	Context("Test httpsRedirect", func() {
+		It("should redirect HTTP to the HTTPS listener of the host", func() {
+			redirectingServer := httpServer
+			redirectingServer.TLS = &v1alpha3.TLSOptions{HTTPSRedirect: true}
+			cbCtx := newContext(newGateway(redirectingServer, httpsServer))
+
+			redirectName := generateSSLRedirectConfigurationName(httpsListenerID)
+			configs := cb.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices)
+			Expect(configs).To(Equal(map[listenerIdentifier]listenerAzConfig{
+				httpListenerID:  {Protocol: "Http", SslRedirectConfigurationName: redirectName},
+				httpsListenerID: {Protocol: "Https", Secret: secretID, SslRedirectConfigurationName: redirectName},
+			}))
+
+			redirects := cb.getRedirectConfigurations(cbCtx)
+			Expect(*redirects).To(HaveLen(1))
+			Expect(*(*redirects)[0].Name).To(Equal(redirectName))
+			Expect(*(*redirects)[0].TargetListener.ID).To(Equal(cb.appGwIdentifier.listenerID(generateListenerName(httpsListenerID))))
+
+			pathMaps := cb.getIstioPathMaps(cbCtx)
+			Expect(pathMaps).To(HaveKey(httpListenerID))
+			Expect(*pathMaps[httpListenerID].DefaultRedirectConfiguration.ID).To(Equal(cb.appGwIdentifier.redirectConfigurationID(redirectName)))
+			Expect(pathMaps[httpListenerID].DefaultBackendAddressPool).To(BeNil())
+		})
+
+		It("should emit an event when there is no HTTPS server to redirect to", func() {
+			redirectingServer := httpServer
+			redirectingServer.TLS = &v1alpha3.TLSOptions{HTTPSRedirect: true}
+			cbCtx := newContext(newGateway(redirectingServer))
+
+			configs := cb.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices)
+			Expect(configs).To(Equal(map[listenerIdentifier]listenerAzConfig{
+				httpListenerID: {Protocol: "Http"},
+			}))
+
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonRedirectWithNoTLS))
+		})
+	})
+
+	Context("Test hosts", func() {
+		It("should intersect hosts", func() {
+			expectIntersection := func(first, second, expected string) {
+				host, matches := intersectIstioHosts(first, second)
+				Expect(matches).To(BeTrue())
+				Expect(host).To(Equal(expected))
+			}
+			expectIntersection("*", "www.contoso.com", "www.contoso.com")
+			expectIntersection("www.contoso.com", "*", "www.contoso.com")
+			expectIntersection("*.contoso.com", "www.contoso.com", "www.contoso.com")
+			expectIntersection("*", "*.contoso.com", "*.contoso.com")
+
+			_, matches := intersectIstioHosts("*.contoso.com", "www.fabrikam.com")
+			Expect(matches).To(BeFalse())
+			_, matches = intersectIstioHosts("*.contoso.com", "contoso.com")
+			Expect(matches).To(BeFalse())
+		})
+
+		It("should honor the namespace of server hosts", func() {
+			gateway := newGateway()
+			expectExposed := func(serverHost, namespace string, expected bool) {
+				host, exposed := getIstioServerHostForNamespace(gateway, serverHost, namespace)
+				Expect(host).To(Equal("www.contoso.com"))
+				Expect(exposed).To(Equal(expected))
+			}
+			expectExposed("www.contoso.com", "other", true)
+			expectExposed("*/www.contoso.com", "other", true)
+			expectExposed("./www.contoso.com", "other", false)
+			expectExposed("./www.contoso.com", tests.Namespace, true)
+			expectExposed("prod/www.contoso.com", tests.Namespace, false)
+		})
+
+		It("should create a listener for every host of the VirtualService on the port of the server", func() {
+			anyHost := v1alpha3.Server{
+				Port:  v1alpha3.Port{Number: 8080, Protocol: v1alpha3.ProtocolHTTP},
+				Hosts: []string{"*"},
+			}
+			multiHost := &v1alpha3.VirtualService{
+				ObjectMeta: metav1.ObjectMeta{Name: "multi-host", Namespace: tests.Namespace},
+				Spec:       v1alpha3.VirtualServiceSpec{Hosts: []string{tests.Host, tests.OtherHost, "*.contoso.com"}},
+			}
+			configs := cb.getListenerConfigsFromIstio([]*v1alpha3.Gateway{newGateway(anyHost)}, []*v1alpha3.VirtualService{multiHost})
+			Expect(configs).To(Equal(map[listenerIdentifier]listenerAzConfig{
+				{FrontendPort: 8080, HostName: tests.Host}:      {Protocol: "Http"},
+				{FrontendPort: 8080, HostName: tests.OtherHost}: {Protocol: "Http"},
+			}))
+
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(HaveLen(1))
+			Expect(<-recorder.Events).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonUnsupportedFeature))
+		})
+	})
+})
@@ -13,7 +13,7 @@ import (
 	"github.com/golang/glog"
 )
 
-func (c *appGwConfigBuilder) getIstioBackendAddressPool(destinationID istioDestinationIdentifier, serviceBackendPair serviceBackendPortPair, addressPools map[string]*n.ApplicationGatewayBackendAddressPool) *n.ApplicationGatewayBackendAddressPool {
+func (c *appGwConfigBuilder) getIstioBackendAddressPool(destinationID istioDestinationIdentifier, serviceBackendPair serviceBackendPortPair, subsetLabels map[string]string, addressPools map[string]*n.ApplicationGatewayBackendAddressPool) *n.ApplicationGatewayBackendAddressPool {
 	endpoints, err := c.k8sContext.GetEndpointsByService(destinationID.serviceKey())
 	if err != nil {
 		logLine := fmt.Sprintf("Failed fetching endpoints for service: %s", destinationID.serviceKey())
@@ -24,16 +24,17 @@ func (c *appGwConfigBuilder) getIstioBackendAddressPool(destinationID istioDesti
 
 	for _, subset := range endpoints.Subsets {
 		if _, portExists := getUniqueTCPPorts(subset)[serviceBackendPair.BackendPort]; portExists {
-			backendServicePort := ""
-			if destinationID.DestinationPort != 0 {
-				backendServicePort = fmt.Sprint(destinationID.DestinationPort)
-			} else {
-				// TODO(delqn): lookup port by name
-			}
-			poolName := generateAddressPoolName(destinationID.serviceFullName(), backendServicePort, serviceBackendPair.BackendPort)
+			poolName := generateAddressPoolName(destinationID.backendFullName(), destinationID.servicePort(), serviceBackendPair.BackendPort)
 			if pool, ok := addressPools[poolName]; ok {
 				return pool
 			}
+			if destinationID.DestinationSubset != "" {
+				subset = c.filterIstioSubsetAddresses(destinationID, subsetLabels, subset)
+				if len(subset.Addresses) == 0 {
+					glog.Errorf("No endpoints of service %s belong to subset %s", destinationID.serviceKey(), destinationID.DestinationSubset)
+					return nil
+				}
+			}
 			pool := c.newPool(poolName, subset)
 			pool.ID = to.StringPtr(c.appGwIdentifier.AddressPoolID(poolName))
 			return pool
@@ -54,7 +55,16 @@ func (c *appGwConfigBuilder) newIstioBackendPoolMap(cbCtx *ConfigBuilderContext)
 	_, _, istioServiceBackendPairMap, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
 	for destinationID, serviceBackendPair := range istioServiceBackendPairMap {
 		backendPoolMap[destinationID] = &defaultPool
-		if pool := c.getIstioBackendAddressPool(destinationID, serviceBackendPair, addressPools); pool != nil {
+		var subsetLabels map[string]string
+		if destinationID.DestinationSubset != "" {
+			// A destination with an unknown subset is served by the default pool; See reportIstioDestinationIssues.
+			subset := getIstioDestinationSubset(cbCtx, destinationID)
+			if subset == nil {
+				continue
+			}
+			subsetLabels = subset.Labels
+		}
+		if pool := c.getIstioBackendAddressPool(destinationID, serviceBackendPair, subsetLabels, addressPools); pool != nil {
 			backendPoolMap[destinationID] = pool
 		}
 	}
@@ -0,0 +1,376 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/glog"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+)
+
+const (
+	// App Gateway accepts request timeouts from 1 second to 1 day.
+	minIstioRouteTimeoutInSec = 1
+	maxIstioRouteTimeoutInSec = 86400
+
+	istioHeadersRewriteRuleName     = "headers"
+	istioHeadersRewriteRuleSequence = 100
+)
+
+// getIstioListenersOfVirtualService lists the listeners, which take requests for the hosts of the given VirtualService.
+// HTTP listeners of servers with httpsRedirect only redirect to HTTPS and are left out.
+func getIstioListenersOfVirtualService(listenerConfigs map[listenerIdentifier]listenerAzConfig, virtualService *v1alpha3.VirtualService) []listenerIdentifier {
+	var listenerIDs []listenerIdentifier
+	for listenerID, listenerConfig := range listenerConfigs {
+		if listenerConfig.Protocol == n.HTTP && listenerConfig.SslRedirectConfigurationName != "" {
+			continue
+		}
+		if isIstioListenerOfVirtualService(listenerID, virtualService) {
+			listenerIDs = append(listenerIDs, listenerID)
+		}
+	}
+	sort.Slice(listenerIDs, func(i, j int) bool {
+		return generateListenerName(listenerIDs[i]) < generateListenerName(listenerIDs[j])
+	})
+	return listenerIDs
+}
+
+// isIstioRouteOfListener tells whether the given HTTP route takes requests of the given listener; Matches on another
+// port, and matches App Gateway does not support, take none.
+func isIstioRouteOfListener(http *v1alpha3.HTTPRoute, listenerID listenerIdentifier) bool {
+	if len(http.Match) == 0 {
+		return true
+	}
+	for matchIdx := range http.Match {
+		match := &http.Match[matchIdx]
+		if _, unsupported := getIstioMatchPaths(match); len(unsupported) > 0 {
+			continue
+		}
+		if match.Port == 0 || Port(match.Port) == listenerID.FrontendPort {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIstioRouteTimeout translates the timeout of an HTTP route into the request timeout of App Gateway, which counts
+// whole seconds; The timeout is rounded up to the next second.
+func parseIstioRouteTimeout(timeout string) (int32, error) {
+	duration, err := time.ParseDuration(timeout)
+	if err != nil {
+		return 0, err
+	}
+	seconds := int64(math.Ceil(duration.Seconds()))
+	if seconds < minIstioRouteTimeoutInSec || seconds > maxIstioRouteTimeoutInSec {
+		return 0, fmt.Errorf("timeout %s is not between %ds and %ds", timeout, minIstioRouteTimeoutInSec, maxIstioRouteTimeoutInSec)
+	}
+	return int32(seconds), nil
+}
+
+// newIstioRouteSettings creates the HTTP settings of an HTTP route, which rewrites requests or limits the time they
+// take. The settings are those of the destination of the route, with the rewrite and the timeout applied. A route,
+// which needs no settings of its own, gets none.
+func (c *appGwConfigBuilder) newIstioRouteSettings(virtualService *v1alpha3.VirtualService, ruleIdx int, http *v1alpha3.HTTPRoute, settings *n.ApplicationGatewayBackendHTTPSettings) *n.ApplicationGatewayBackendHTTPSettings {
+	if settings == nil {
+		return nil
+	}
+	var timeout int32
+	if http.Timeout != "" {
+		// An invalid timeout is reported by reportUnsupportedIstioRouteFields.
+		timeout, _ = parseIstioRouteTimeout(http.Timeout)
+	}
+	hasRewrite := http.Rewrite != nil && (http.Rewrite.URI != "" || http.Rewrite.Authority != "")
+	if !hasRewrite && timeout == 0 {
+		return nil
+	}
+
+	props := *settings.ApplicationGatewayBackendHTTPSettingsPropertiesFormat
+	if hasRewrite && http.Rewrite.URI != "" {
+		props.Path = to.StringPtr(http.Rewrite.URI)
+	}
+	if hasRewrite && http.Rewrite.Authority != "" {
+		props.HostName = to.StringPtr(http.Rewrite.Authority)
+		props.PickHostNameFromBackendAddress = to.BoolPtr(false)
+	}
+	if timeout != 0 {
+		props.RequestTimeout = to.Int32Ptr(timeout)
+	}
+
+	settingsName := generateIstioRouteHTTPSettingsName(virtualService.Namespace, virtualService.Name, ruleIdx)
+	return &n.ApplicationGatewayBackendHTTPSettings{
+		Etag: to.StringPtr("*"),
+		Name: to.StringPtr(settingsName),
+		ID:   to.StringPtr(c.appGwIdentifier.HTTPSettingsID(settingsName)),
+		ApplicationGatewayBackendHTTPSettingsPropertiesFormat: &props,
+	}
+}
+
+// getIstioRouteHTTPSettings lists the HTTP settings of the HTTP routes, which rewrite requests or limit the time they
+// take.
+func (c *appGwConfigBuilder) getIstioRouteHTTPSettings(cbCtx *ConfigBuilderContext) []n.ApplicationGatewayBackendHTTPSettings {
+	_, settingsByDestination, _, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
+	backendByDestination := c.newIstioBackendPoolMap(cbCtx)
+
+	var routeSettings []n.ApplicationGatewayBackendHTTPSettings
+	for _, virtualService := range cbCtx.IstioVirtualServices {
+		for ruleIdx := range virtualService.Spec.HTTP {
+			http := &virtualService.Spec.HTTP[ruleIdx]
+			if http.Redirect != nil {
+				continue
+			}
+			backend, found := c.getIstioRouteBackend(virtualService, ruleIdx, http, backendByDestination, settingsByDestination)
+			if !found {
+				continue
+			}
+			if settings := c.newIstioRouteSettings(virtualService, ruleIdx, http, backend.settings); settings != nil {
+				routeSettings = append(routeSettings, *settings)
+			}
+		}
+	}
+	return routeSettings
+}
+
+// newIstioRouteRewriteRuleSet creates the rewrite rule set, which manipulates the request and response headers as the
+// given HTTP route asks for. App Gateway can only set a header; Values to add to a header replace it, and headers to
+// remove are set to a blank value, which App Gateway removes.
+func (c *appGwConfigBuilder) newIstioRouteRewriteRuleSet(virtualService *v1alpha3.VirtualService, ruleIdx int, http *v1alpha3.HTTPRoute) *n.ApplicationGatewayRewriteRuleSet {
+	requestHeaders := make(map[string]string)
+	responseHeaders := make(map[string]string)
+	for name, value := range http.DeprecatedAppendHeaders {
+		requestHeaders[name] = value
+	}
+	for name := range http.RemoveResponseHeaders {
+		responseHeaders[name] = ""
+	}
+	if http.Headers != nil {
+		addIstioHeaderOperations(requestHeaders, http.Headers.Request)
+		addIstioHeaderOperations(responseHeaders, http.Headers.Response)
+	}
+	if len(requestHeaders) == 0 && len(responseHeaders) == 0 {
+		return nil
+	}
+
+	actionSet := n.ApplicationGatewayRewriteRuleActionSet{}
+	if len(requestHeaders) > 0 {
+		actionSet.RequestHeaderConfigurations = newHeaderConfigurations(requestHeaders)
+	}
+	if len(responseHeaders) > 0 {
+		actionSet.ResponseHeaderConfigurations = newHeaderConfigurations(responseHeaders)
+	}
+
+	rewriteRuleSetName := generateRewriteRuleSetName(virtualService.Namespace, virtualService.Name, ruleIdx)
+	return &n.ApplicationGatewayRewriteRuleSet{
+		Etag: to.StringPtr("*"),
+		Name: to.StringPtr(rewriteRuleSetName),
+		ID:   to.StringPtr(c.appGwIdentifier.rewriteRuleSetID(rewriteRuleSetName)),
+		ApplicationGatewayRewriteRuleSetPropertiesFormat: &n.ApplicationGatewayRewriteRuleSetPropertiesFormat{
+			RewriteRules: &[]n.ApplicationGatewayRewriteRule{{
+				Name:         to.StringPtr(istioHeadersRewriteRuleName),
+				RuleSequence: to.Int32Ptr(istioHeadersRewriteRuleSequence),
+				ActionSet:    &actionSet,
+			}},
+		},
+	}
+}
+
+// addIstioHeaderOperations adds the given header operations to the given headers; Istio applies set after add, and
+// remove last.
+func addIstioHeaderOperations(headers map[string]string, operations *v1alpha3.HeaderOperations) {
+	if operations == nil {
+		return
+	}
+	for name, value := range operations.Add {
+		headers[name] = value
+	}
+	for name, value := range operations.Set {
+		headers[name] = value
+	}
+	for _, name := range operations.Remove {
+		headers[name] = ""
+	}
+}
+
+func newHeaderConfigurations(headers map[string]string) *[]n.ApplicationGatewayHeaderConfiguration {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	configurations := make([]n.ApplicationGatewayHeaderConfiguration, 0, len(names))
+	for _, name := range names {
+		configurations = append(configurations, n.ApplicationGatewayHeaderConfiguration{
+			HeaderName:  to.StringPtr(name),
+			HeaderValue: to.StringPtr(headers[name]),
+		})
+	}
+	return &configurations
+}
+
+// getIstioRewriteRuleSets lists the rewrite rule sets of the HTTP routes, which manipulate headers.
+func (c *appGwConfigBuilder) getIstioRewriteRuleSets(cbCtx *ConfigBuilderContext) []n.ApplicationGatewayRewriteRuleSet {
+	var rewriteRuleSets []n.ApplicationGatewayRewriteRuleSet
+	for _, virtualService := range cbCtx.IstioVirtualServices {
+		for ruleIdx := range virtualService.Spec.HTTP {
+			http := &virtualService.Spec.HTTP[ruleIdx]
+			if http.Redirect != nil {
+				continue
+			}
+			if rewriteRuleSet := c.newIstioRouteRewriteRuleSet(virtualService, ruleIdx, http); rewriteRuleSet != nil {
+				rewriteRuleSets = append(rewriteRuleSets, *rewriteRuleSet)
+			}
+		}
+	}
+	return rewriteRuleSets
+}
+
+// newIstioRouteRedirect creates the redirect of an HTTP route for the given listener. Istio keeps the parts of the URL
+// the redirect does not replace; The scheme and port therefore are those of the listener, and the host is that of the
+// listener unless the redirect replaces it. A listener for any host cannot tell the host of the request, so a redirect,
+// which keeps the host, cannot be created for it.
+func (c *appGwConfigBuilder) newIstioRouteRedirect(virtualService *v1alpha3.VirtualService, ruleIdx int, redirect *v1alpha3.HTTPRedirect, listenerID listenerIdentifier, listenerConfig listenerAzConfig) (n.ApplicationGatewayRedirectConfiguration, bool) {
+	host := redirect.Authority
+	if host == "" {
+		host = listenerID.HostName
+	}
+	if host == "" {
+		return n.ApplicationGatewayRedirectConfiguration{}, false
+	}
+
+	scheme := "http"
+	defaultPort := Port(80)
+	if listenerConfig.Protocol == n.HTTPS {
+		scheme = "https"
+		defaultPort = Port(443)
+	}
+	if redirect.Authority == "" && listenerID.FrontendPort != defaultPort {
+		host = fmt.Sprintf("%s:%d", host, listenerID.FrontendPort)
+	}
+
+	props := n.ApplicationGatewayRedirectConfigurationPropertiesFormat{
+		// Istio redirects with 301 Moved Permanently.
+		RedirectType:       n.Permanent,
+		TargetURL:          to.StringPtr(fmt.Sprintf("%s://%s%s", scheme, host, redirect.URI)),
+		IncludeQueryString: to.BoolPtr(true),
+	}
+	if redirect.URI == "" {
+		// Only the authority is replaced; App Gateway appends the path of the request to the target URL.
+		props.TargetURL = to.StringPtr(fmt.Sprintf("%s://%s", scheme, host))
+		props.IncludePath = to.BoolPtr(true)
+	} else {
+		props.IncludePath = to.BoolPtr(false)
+	}
+
+	redirectName := generateIstioRouteRedirectName(virtualService.Namespace, virtualService.Name, ruleIdx, listenerID)
+	return n.ApplicationGatewayRedirectConfiguration{
+		Etag: to.StringPtr("*"),
+		Name: to.StringPtr(redirectName),
+		ID:   to.StringPtr(c.appGwIdentifier.redirectConfigurationID(redirectName)),
+		ApplicationGatewayRedirectConfigurationPropertiesFormat: &props,
+	}, true
+}
+
+// getIstioRouteRedirects lists the redirects of the HTTP routes, which redirect requests, for each listener the routes
+// take requests of.
+func (c *appGwConfigBuilder) getIstioRouteRedirects(cbCtx *ConfigBuilderContext) []n.ApplicationGatewayRedirectConfiguration {
+	listenerConfigs := c.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices)
+
+	var redirects []n.ApplicationGatewayRedirectConfiguration
+	for _, virtualService := range cbCtx.IstioVirtualServices {
+		listenerIDs := getIstioListenersOfVirtualService(listenerConfigs, virtualService)
+		for ruleIdx := range virtualService.Spec.HTTP {
+			http := &virtualService.Spec.HTTP[ruleIdx]
+			if http.Redirect == nil {
+				continue
+			}
+			for _, listenerID := range listenerIDs {
+				if !isIstioRouteOfListener(http, listenerID) {
+					continue
+				}
+				if redirect, ok := c.newIstioRouteRedirect(virtualService, ruleIdx, http.Redirect, listenerID, listenerConfigs[listenerID]); ok {
+					redirects = append(redirects, redirect)
+				}
+			}
+		}
+	}
+	return redirects
+}
+
+// reportUnsupportedIstioRouteFields emits an event for the fields of the given HTTP route, which App Gateway cannot
+// apply.
+func (c *appGwConfigBuilder) reportUnsupportedIstioRouteFields(virtualService *v1alpha3.VirtualService, ruleIdx int, http *v1alpha3.HTTPRoute) {
+	var unsupported []string
+	if http.Retries != nil {
+		unsupported = append(unsupported, "retries")
+	}
+	if http.Fault != nil {
+		unsupported = append(unsupported, "fault")
+	}
+	if http.Mirror != nil {
+		unsupported = append(unsupported, "mirror")
+	}
+	if http.CorsPolicy != nil {
+		unsupported = append(unsupported, "corsPolicy")
+	}
+	if len(unsupported) > 0 {
+		logLine := fmt.Sprintf("HTTP route %d of VirtualService %s/%s uses %s, which App Gateway does not support; The fields are ignored", ruleIdx, virtualService.Namespace, virtualService.Name, strings.Join(unsupported, ", "))
+		glog.Warning(logLine)
+		c.recorder.Event(virtualService, v1.EventTypeWarning, events.ReasonUnsupportedFeature, logLine)
+	}
+
+	if appended := getIstioAppendedHeaders(http); len(appended) > 0 && http.Redirect == nil {
+		logLine := fmt.Sprintf("HTTP route %d of VirtualService %s/%s adds to %s, which App Gateway can only set; The headers are replaced instead", ruleIdx, virtualService.Namespace, virtualService.Name, strings.Join(appended, ", "))
+		glog.Warning(logLine)
+		c.recorder.Event(virtualService, v1.EventTypeWarning, events.ReasonUnsupportedFeature, logLine)
+	}
+
+	if http.Timeout != "" && http.Redirect == nil {
+		if _, err := parseIstioRouteTimeout(http.Timeout); err != nil {
+			logLine := fmt.Sprintf("HTTP route %d of VirtualService %s/%s has a timeout App Gateway cannot apply: %s; The default request timeout applies", ruleIdx, virtualService.Namespace, virtualService.Name, err)
+			glog.Warning(logLine)
+			c.recorder.Event(virtualService, v1.EventTypeWarning, events.ReasonUnsupportedFeature, logLine)
+		}
+	}
+}
+
+// getIstioAppendedHeaders describes the headers the HTTP route adds a value to, rather than sets.
+func getIstioAppendedHeaders(http *v1alpha3.HTTPRoute) []string {
+	requestHeaders := make(map[string]interface{})
+	responseHeaders := make(map[string]interface{})
+	for name := range http.DeprecatedAppendHeaders {
+		requestHeaders[name] = nil
+	}
+	if http.Headers != nil && http.Headers.Request != nil {
+		for name := range http.Headers.Request.Add {
+			requestHeaders[name] = nil
+		}
+	}
+	if http.Headers != nil && http.Headers.Response != nil {
+		for name := range http.Headers.Response.Add {
+			responseHeaders[name] = nil
+		}
+	}
+
+	var appended []string
+	for name := range requestHeaders {
+		appended = append(appended, "request header "+name)
+	}
+	for name := range responseHeaders {
+		appended = append(appended, "response header "+name)
+	}
+	sort.Strings(appended)
+	return appended
+}
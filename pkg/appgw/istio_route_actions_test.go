@@ -0,0 +1,249 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/knative/pkg/apis/istio/common/v1alpha1"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test Istio route actions", func() {
+	gateway := &v1alpha3.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: tests.Namespace},
+		Spec: v1alpha3.GatewaySpec{
+			Servers: []v1alpha3.Server{{
+				Port:  v1alpha3.Port{Number: 80, Protocol: v1alpha3.ProtocolHTTP},
+				Hosts: []string{tests.Host},
+			}},
+		},
+	}
+	destination := v1alpha3.HTTPRouteDestination{
+		Destination: v1alpha3.Destination{Host: "reviews", Port: v1alpha3.PortSelector{Number: 80}},
+	}
+	newVirtualService := func(routes ...v1alpha3.HTTPRoute) *v1alpha3.VirtualService {
+		return &v1alpha3.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: tests.Namespace},
+			Spec: v1alpha3.VirtualServiceSpec{
+				Hosts: []string{tests.Host},
+				HTTP:  routes,
+			},
+		}
+	}
+	newContext := func(virtualService *v1alpha3.VirtualService) *ConfigBuilderContext {
+		cbCtx := &ConfigBuilderContext{
+			IstioGateways:        []*v1alpha3.Gateway{gateway},
+			IstioVirtualServices: []*v1alpha3.VirtualService{virtualService},
+		}
+		cbCtx.EnvVariables.EnableIstioIntegration = true
+		return cbCtx
+	}
+	listenerID := listenerIdentifier{FrontendPort: 80, HostName: tests.Host}
+	baseSettings := &n.ApplicationGatewayBackendHTTPSettings{
+		Name: to.StringPtr("settings"),
+		ApplicationGatewayBackendHTTPSettingsPropertiesFormat: &n.ApplicationGatewayBackendHTTPSettingsPropertiesFormat{
+			Protocol: n.HTTP,
+			Port:     to.Int32Ptr(9080),
+		},
+	}
+
+	var cb appGwConfigBuilder
+	BeforeEach(func() {
+		cb = newConfigBuilderFixture(nil)
+	})
+
+	Context("Test timeouts", func() {
+		It("should round timeouts up to whole seconds", func() {
+			timeout, err := parseIstioRouteTimeout("1.5s")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(timeout).To(Equal(int32(2)))
+
+			timeout, err = parseIstioRouteTimeout("2m")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(timeout).To(Equal(int32(120)))
+		})
+
+		It("should refuse timeouts App Gateway cannot apply", func() {
+			_, err := parseIstioRouteTimeout("ten seconds")
+			Expect(err).To(HaveOccurred())
+
+			_, err = parseIstioRouteTimeout("48h")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Test newIstioRouteSettings()", func() {
+		It("should create no settings for routes without rewrite or timeout", func() {
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{Route: []v1alpha3.HTTPRouteDestination{destination}})
+			Expect(cb.newIstioRouteSettings(virtualService, 0, &virtualService.Spec.HTTP[0], baseSettings)).To(BeNil())
+		})
+
+		It("should apply the rewrite and the timeout to the settings of the destination", func() {
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{
+				Route:   []v1alpha3.HTTPRouteDestination{destination},
+				Rewrite: &v1alpha3.HTTPRewrite{URI: "/v1/reviews", Authority: "reviews.internal"},
+				Timeout: "10s",
+			})
+			settings := cb.newIstioRouteSettings(virtualService, 0, &virtualService.Spec.HTTP[0], baseSettings)
+			Expect(*settings.Name).To(Equal(generateIstioRouteHTTPSettingsName(tests.Namespace, "reviews", 0)))
+			Expect(*settings.Port).To(Equal(int32(9080)))
+			Expect(*settings.Path).To(Equal("/v1/reviews"))
+			Expect(*settings.HostName).To(Equal("reviews.internal"))
+			Expect(*settings.RequestTimeout).To(Equal(int32(10)))
+
+			// The settings of the destination are shared by other routes and stay as they are.
+			Expect(baseSettings.Path).To(BeNil())
+		})
+	})
+
+	Context("Test newIstioRouteRewriteRuleSet()", func() {
+		It("should translate header operations into header configurations", func() {
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{
+				Route: []v1alpha3.HTTPRouteDestination{destination},
+				Headers: &v1alpha3.Headers{
+					Request: &v1alpha3.HeaderOperations{
+						Set: map[string]string{"x-version": "v1"},
+						Add: map[string]string{"x-tag": "canary"},
+					},
+					Response: &v1alpha3.HeaderOperations{Remove: []string{"server"}},
+				},
+			})
+			rewriteRuleSet := cb.newIstioRouteRewriteRuleSet(virtualService, 0, &virtualService.Spec.HTTP[0])
+			Expect(*rewriteRuleSet.Name).To(Equal(generateRewriteRuleSetName(tests.Namespace, "reviews", 0)))
+
+			rules := *rewriteRuleSet.RewriteRules
+			Expect(rules).To(HaveLen(1))
+			Expect(*rules[0].ActionSet.RequestHeaderConfigurations).To(Equal([]n.ApplicationGatewayHeaderConfiguration{
+				{HeaderName: to.StringPtr("x-tag"), HeaderValue: to.StringPtr("canary")},
+				{HeaderName: to.StringPtr("x-version"), HeaderValue: to.StringPtr("v1")},
+			}))
+			Expect(*rules[0].ActionSet.ResponseHeaderConfigurations).To(Equal([]n.ApplicationGatewayHeaderConfiguration{
+				{HeaderName: to.StringPtr("server"), HeaderValue: to.StringPtr("")},
+			}))
+		})
+
+		It("should create no rewrite rule set for routes without header operations", func() {
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{Route: []v1alpha3.HTTPRouteDestination{destination}})
+			Expect(cb.newIstioRouteRewriteRuleSet(virtualService, 0, &virtualService.Spec.HTTP[0])).To(BeNil())
+		})
+	})
+
+	Context("Test newIstioRouteRedirect()", func() {
+		httpConfig := listenerAzConfig{Protocol: n.HTTP}
+		virtualService := newVirtualService()
+
+		It("should redirect to the URI on the host of the listener", func() {
+			redirect, ok := cb.newIstioRouteRedirect(virtualService, 0, &v1alpha3.HTTPRedirect{URI: "/v2/reviews"}, listenerID, httpConfig)
+			Expect(ok).To(BeTrue())
+			Expect(*redirect.TargetURL).To(Equal("http://" + tests.Host + "/v2/reviews"))
+			Expect(*redirect.IncludePath).To(BeFalse())
+			Expect(redirect.RedirectType).To(Equal(n.Permanent))
+		})
+
+		It("should keep the path when only the authority is replaced", func() {
+			otherPortID := listenerIdentifier{FrontendPort: 8080, HostName: tests.Host}
+			redirect, ok := cb.newIstioRouteRedirect(virtualService, 0, &v1alpha3.HTTPRedirect{Authority: tests.OtherHost}, otherPortID, httpConfig)
+			Expect(ok).To(BeTrue())
+			Expect(*redirect.TargetURL).To(Equal("http://" + tests.OtherHost))
+			Expect(*redirect.IncludePath).To(BeTrue())
+		})
+
+		It("should refuse to keep the host for listeners of any host", func() {
+			_, ok := cb.newIstioRouteRedirect(virtualService, 0, &v1alpha3.HTTPRedirect{URI: "/v2/reviews"}, listenerIdentifier{FrontendPort: 80}, httpConfig)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("Test getIstioPathMaps() with route actions", func() {
+		It("should attach redirects to the path rules of redirecting routes", func() {
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{
+				Match:    []v1alpha3.HTTPMatchRequest{{URI: &v1alpha1.StringMatch{Prefix: "/v1"}}},
+				Redirect: &v1alpha3.HTTPRedirect{URI: "/v2"},
+			})
+			cbCtx := newContext(virtualService)
+			pathMaps := cb.getIstioPathMaps(cbCtx)
+			pathRules := *pathMaps[listenerID].PathRules
+			Expect(pathRules).To(HaveLen(1))
+			Expect(pathRules[0].BackendAddressPool).To(BeNil())
+			Expect(pathRules[0].BackendHTTPSettings).To(BeNil())
+
+			redirects := cb.getIstioRouteRedirects(cbCtx)
+			Expect(redirects).To(HaveLen(1))
+			Expect(*pathRules[0].RedirectConfiguration.ID).To(Equal(*redirects[0].ID))
+		})
+
+		It("should make the redirect of a route without matches the default of the path map", func() {
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{Redirect: &v1alpha3.HTTPRedirect{Authority: tests.OtherHost}})
+			pathMaps := cb.getIstioPathMaps(newContext(virtualService))
+			pathMap := pathMaps[listenerID]
+			Expect(pathMap.DefaultRedirectConfiguration).ToNot(BeNil())
+			Expect(pathMap.DefaultBackendAddressPool).To(BeNil())
+			Expect(pathMap.DefaultBackendHTTPSettings).To(BeNil())
+		})
+
+		It("should emit an event for fields App Gateway does not support", func() {
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{
+				Redirect: &v1alpha3.HTTPRedirect{URI: "/v2"},
+				Retries:  &v1alpha3.HTTPRetry{Attempts: 3},
+				Mirror:   &v1alpha3.Destination{Host: "ratings"},
+			})
+			_ = cb.getIstioPathMaps(newContext(virtualService))
+
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(HaveLen(1))
+			event := <-recorder.Events
+			Expect(event).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonUnsupportedFeature))
+			Expect(event).To(ContainSubstring("retries, mirror"))
+		})
+
+		It("should emit an event for headers a route adds to", func() {
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{
+				Route: []v1alpha3.HTTPRouteDestination{destination},
+				Headers: &v1alpha3.Headers{
+					Request:  &v1alpha3.HeaderOperations{Add: map[string]string{"x-trace": "agic"}, Set: map[string]string{"x-version": "v1"}},
+					Response: &v1alpha3.HeaderOperations{Add: map[string]string{"x-served-by": "agic"}},
+				},
+			})
+			_ = cb.getIstioPathMaps(newContext(virtualService))
+
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(HaveLen(1))
+			event := <-recorder.Events
+			Expect(event).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonUnsupportedFeature))
+			Expect(event).To(ContainSubstring("request header x-trace, response header x-served-by"))
+			Expect(event).ToNot(ContainSubstring("x-version"))
+		})
+	})
+
+	Context("Test RewriteRuleSets()", func() {
+		It("should replace the rewrite rule sets AGIC created and keep the others", func() {
+			cb.appGw.RewriteRuleSets = &[]n.ApplicationGatewayRewriteRuleSet{
+				{Name: to.StringPtr("foreign")},
+				{Name: to.StringPtr(generateRewriteRuleSetName(tests.Namespace, "stale", 0))},
+			}
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{
+				Route:   []v1alpha3.HTTPRouteDestination{destination},
+				Headers: &v1alpha3.Headers{Request: &v1alpha3.HeaderOperations{Set: map[string]string{"x-version": "v1"}}},
+			})
+			cb.RewriteRuleSets(newContext(virtualService))
+
+			var names []string
+			for _, rewriteRuleSet := range *cb.appGw.RewriteRuleSets {
+				names = append(names, *rewriteRuleSet.Name)
+			}
+			Expect(names).To(ConsistOf("foreign", generateRewriteRuleSetName(tests.Namespace, "reviews", 0)))
+		})
+	})
+})
@@ -7,87 +7,175 @@ package appgw
 
 import (
 	"fmt"
-	"strconv"
+	"strings"
 
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/glog"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 )
 
 func (c *appGwConfigBuilder) getIstioPathMaps(cbCtx *ConfigBuilderContext) map[listenerIdentifier]*n.ApplicationGatewayURLPathMap {
 	defaultAddressPoolID := to.StringPtr(c.appGwIdentifier.AddressPoolID(DefaultBackendAddressPoolName))
 	defaultHTTPSettingsID := to.StringPtr(c.appGwIdentifier.HTTPSettingsID(DefaultBackendHTTPSettingsName))
 
-	// TODO(delqn)
-	istioHTTPSettings, _, _, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
+	_, settingsByDestination, _, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
 
 	backendByDestination := c.newIstioBackendPoolMap(cbCtx)
 
+	listenerConfigs := c.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices)
+
+	c.reportIstioDestinationIssues(cbCtx)
+
 	urlPathMaps := make(map[listenerIdentifier]*n.ApplicationGatewayURLPathMap)
-	for virtSvcIdx, virtSvc := range cbCtx.IstioVirtualServices {
-		for _, http := range virtSvc.Spec.HTTP {
-			// TODO(delqn): consider weights
-			host := http.Route[0].Destination.Host
-			var port uint32
-			if http.Route[0].Destination.Port.Number != 0 {
-				port = http.Route[0].Destination.Port.Number
-			} else {
-				port64, _ := strconv.ParseUint(http.Route[0].Destination.Port.Name, 10, 32)
-				port = uint32(port64)
+	for listenerID, listenerConfig := range listenerConfigs {
+		// HTTP listeners of servers with httpsRedirect only redirect to HTTPS.
+		if listenerConfig.Protocol == n.HTTP && listenerConfig.SslRedirectConfigurationName != "" {
+			urlPathMaps[listenerID] = &n.ApplicationGatewayURLPathMap{
+				Etag: to.StringPtr("*"),
+				Name: to.StringPtr(generateURLPathMapName(listenerID)),
+				ID:   to.StringPtr(c.appGwIdentifier.urlPathMapID(generateURLPathMapName(listenerID))),
+				ApplicationGatewayURLPathMapPropertiesFormat: &n.ApplicationGatewayURLPathMapPropertiesFormat{
+					DefaultRedirectConfiguration: resourceRef(c.appGwIdentifier.redirectConfigurationID(listenerConfig.SslRedirectConfigurationName)),
+					PathRules:                    &[]n.ApplicationGatewayPathRule{},
+				},
 			}
-			for matchIdx, match := range http.Match {
-				dst := istioDestinationIdentifier{
-					serviceIdentifier: serviceIdentifier{
-						Namespace: virtSvc.Namespace,
-						Name:      virtSvc.Name,
-					},
-					istioVirtualServiceIdentifier: istioVirtualServiceIdentifier{
-						Namespace: virtSvc.Namespace,
-						Name:      virtSvc.Name,
-					},
-					// TODO(delqn)
-					DestinationHost: host,
-					DestinationPort: port,
-				}
+		}
+	}
 
-				// TODO(delqn)
-				listenerID := listenerIdentifier{
-					FrontendPort: 80,
-					HostName:     virtSvc.Spec.Hosts[0], // TODO(delqn),
-				}
-				pool, found := backendByDestination[dst]
+	newPathMap := func(listenerID listenerIdentifier) *n.ApplicationGatewayURLPathMap {
+		return &n.ApplicationGatewayURLPathMap{
+			Etag: to.StringPtr("*"),
+			Name: to.StringPtr(generateURLPathMapName(listenerID)),
+			ID:   to.StringPtr(c.appGwIdentifier.urlPathMapID(generateURLPathMapName(listenerID))),
+			ApplicationGatewayURLPathMapPropertiesFormat: &n.ApplicationGatewayURLPathMapPropertiesFormat{
+				DefaultBackendAddressPool:  &n.SubResource{ID: defaultAddressPoolID},
+				DefaultBackendHTTPSettings: &n.SubResource{ID: defaultHTTPSettingsID},
+				PathRules:                  &[]n.ApplicationGatewayPathRule{},
+			},
+		}
+	}
+
+	// Istio applies the first route matching a request; The listeners, which got their default backend from a route
+	// without matches, keep it.
+	hasDefaultRoute := make(map[listenerIdentifier]bool)
+	for _, virtSvc := range cbCtx.IstioVirtualServices {
+		listenerIDs := getIstioListenersOfVirtualService(listenerConfigs, virtSvc)
 
+		for ruleIdx := range virtSvc.Spec.HTTP {
+			http := &virtSvc.Spec.HTTP[ruleIdx]
+			c.reportUnsupportedIstioRouteFields(virtSvc, ruleIdx, http)
+
+			var target istioRouteTarget
+			if http.Redirect == nil {
+				backend, found := c.getIstioRouteBackend(virtSvc, ruleIdx, http, backendByDestination, settingsByDestination)
 				if !found {
 					continue
 				}
-				pathMap := n.ApplicationGatewayURLPathMap{
-					Etag: to.StringPtr("*"),
-					Name: to.StringPtr(generateURLPathMapName(listenerID)),
-					ID:   to.StringPtr(c.appGwIdentifier.urlPathMapID(generateURLPathMapName(listenerID))),
-					ApplicationGatewayURLPathMapPropertiesFormat: &n.ApplicationGatewayURLPathMapPropertiesFormat{
-						DefaultBackendAddressPool:  &n.SubResource{ID: defaultAddressPoolID},
-						DefaultBackendHTTPSettings: &n.SubResource{ID: defaultHTTPSettingsID},
-						PathRules:                  &[]n.ApplicationGatewayPathRule{},
-					},
+				if backend.approximation != "" {
+					glog.Warning(backend.approximation)
+					c.recorder.Event(virtSvc, v1.EventTypeWarning, events.ReasonWeightsApproximated, backend.approximation)
+				}
+				target.pool = &n.SubResource{ID: backend.pool.ID}
+				target.settings = &n.SubResource{ID: defaultHTTPSettingsID}
+				if backend.settings != nil {
+					target.settings = &n.SubResource{ID: backend.settings.ID}
 				}
+				if routeSettings := c.newIstioRouteSettings(virtSvc, ruleIdx, http, backend.settings); routeSettings != nil {
+					target.settings = &n.SubResource{ID: routeSettings.ID}
+				}
+				if rewriteRuleSet := c.newIstioRouteRewriteRuleSet(virtSvc, ruleIdx, http); rewriteRuleSet != nil {
+					target.rewriteRuleSet = &n.SubResource{ID: rewriteRuleSet.ID}
+				}
+			}
 
-				pathRuleIdx := fmt.Sprintf("%d-%d", virtSvcIdx, matchIdx)
+			// targetOf finds the target of the route on the given listener; A route redirects to a URL, which depends
+			// on the listener.
+			targetOf := func(listenerID listenerIdentifier) (istioRouteTarget, bool) {
+				if http.Redirect == nil {
+					return target, true
+				}
+				redirect, ok := c.newIstioRouteRedirect(virtSvc, ruleIdx, http.Redirect, listenerID, listenerConfigs[listenerID])
+				if !ok {
+					logLine := fmt.Sprintf("HTTP route %d of VirtualService %s/%s redirects without an authority, which the listener for any host on port %d cannot tell; The route is skipped on this listener", ruleIdx, virtSvc.Namespace, virtSvc.Name, listenerID.FrontendPort)
+					glog.Warning(logLine)
+					c.recorder.Event(virtSvc, v1.EventTypeWarning, events.ReasonUnsupportedFeature, logLine)
+					return istioRouteTarget{}, false
+				}
+				return istioRouteTarget{redirect: &n.SubResource{ID: redirect.ID}}, true
+			}
 
-				pathRule := n.ApplicationGatewayPathRule{
-					Etag: to.StringPtr("*"),
-					Name: to.StringPtr(generatePathRuleName(virtSvc.Namespace, virtSvc.Name, pathRuleIdx)),
-					ApplicationGatewayPathRulePropertiesFormat: &n.ApplicationGatewayPathRulePropertiesFormat{
-						Paths: &[]string{
-							match.URI.Prefix,
-						},
-						BackendAddressPool: &n.SubResource{ID: pool.ID},
-						// TODO(delqn)
-						BackendHTTPSettings: &n.SubResource{ID: istioHTTPSettings[0].ID},
-					},
+			if len(http.Match) == 0 {
+				for _, listenerID := range listenerIDs {
+					if hasDefaultRoute[listenerID] {
+						continue
+					}
+					listenerTarget, ok := targetOf(listenerID)
+					if !ok {
+						continue
+					}
+					if _, exists := urlPathMaps[listenerID]; !exists {
+						urlPathMaps[listenerID] = newPathMap(listenerID)
+					}
+					listenerTarget.applyToPathMap(urlPathMaps[listenerID])
+					hasDefaultRoute[listenerID] = true
 				}
-				pathMap.PathRules = &[]n.ApplicationGatewayPathRule{
-					pathRule,
+				continue
+			}
+
+			listenerTargets := make(map[listenerIdentifier]*istioRouteTarget)
+			for matchIdx := range http.Match {
+				match := &http.Match[matchIdx]
+				paths, unsupported := getIstioMatchPaths(match)
+				if len(unsupported) > 0 {
+					logLine := fmt.Sprintf("Match %d of HTTP route %d of VirtualService %s/%s matches on %s, which App Gateway does not support; The match is skipped", matchIdx, ruleIdx, virtSvc.Namespace, virtSvc.Name, strings.Join(unsupported, ", "))
+					glog.Warning(logLine)
+					c.recorder.Event(virtSvc, v1.EventTypeWarning, events.ReasonUnsupportedFeature, logLine)
+					continue
+				}
+
+				pathRuleIdx := fmt.Sprintf("%d-%d", ruleIdx, matchIdx)
+				for _, listenerID := range listenerIDs {
+					if match.Port != 0 && Port(match.Port) != listenerID.FrontendPort {
+						continue
+					}
+					if _, exists := listenerTargets[listenerID]; !exists {
+						listenerTargets[listenerID] = nil
+						if listenerTarget, ok := targetOf(listenerID); ok {
+							listenerTargets[listenerID] = &listenerTarget
+						}
+					}
+					listenerTarget := listenerTargets[listenerID]
+					if listenerTarget == nil {
+						continue
+					}
+					if _, exists := urlPathMaps[listenerID]; !exists {
+						urlPathMaps[listenerID] = newPathMap(listenerID)
+					}
+					pathMap := urlPathMaps[listenerID]
+
+					// A path matched by an earlier route stays with it.
+					newPaths := excludeExistingPaths(*pathMap.PathRules, paths)
+					if len(newPaths) == 0 {
+						continue
+					}
+					pathRule := n.ApplicationGatewayPathRule{
+						Etag: to.StringPtr("*"),
+						Name: to.StringPtr(generatePathRuleName(virtSvc.Namespace, virtSvc.Name, pathRuleIdx)),
+						ApplicationGatewayPathRulePropertiesFormat: &n.ApplicationGatewayPathRulePropertiesFormat{
+							Paths:                 &newPaths,
+							BackendAddressPool:    listenerTarget.pool,
+							BackendHTTPSettings:   listenerTarget.settings,
+							RewriteRuleSet:        listenerTarget.rewriteRuleSet,
+							RedirectConfiguration: listenerTarget.redirect,
+						},
+					}
+					pathRules := append(*pathMap.PathRules, pathRule)
+					pathMap.PathRules = &pathRules
 				}
-				urlPathMaps[listenerID] = &pathMap
 			}
 		}
 	}
@@ -111,3 +199,80 @@ func (c *appGwConfigBuilder) getIstioPathMaps(cbCtx *ConfigBuilderContext) map[l
 
 	return urlPathMaps
 }
+
+// istioRouteTarget is what App Gateway does with the requests of an HTTP route: They are either sent to a backend,
+// with their headers rewritten by a rewrite rule set, or redirected.
+type istioRouteTarget struct {
+	pool           *n.SubResource
+	settings       *n.SubResource
+	rewriteRuleSet *n.SubResource
+	redirect       *n.SubResource
+}
+
+// applyToPathMap makes the target the default of the given path map.
+func (target istioRouteTarget) applyToPathMap(pathMap *n.ApplicationGatewayURLPathMap) {
+	pathMap.DefaultBackendAddressPool = target.pool
+	pathMap.DefaultBackendHTTPSettings = target.settings
+	pathMap.DefaultRewriteRuleSet = target.rewriteRuleSet
+	pathMap.DefaultRedirectConfiguration = target.redirect
+}
+
+// getIstioMatchPaths translates the URI of an HTTP match request into the paths of an App Gateway path rule. App Gateway
+// allows a wildcard only at the end of a path, following a "/"; A prefix like "/reviews" therefore matches "/reviews"
+// and "/reviews/..." but not "/reviews2". The conditions App Gateway cannot match on are listed separately.
+func getIstioMatchPaths(match *v1alpha3.HTTPMatchRequest) ([]string, []string) {
+	var unsupported []string
+	var paths []string
+	switch {
+	case match.URI == nil:
+		paths = []string{"/*"}
+	case match.URI.Exact != "":
+		paths = []string{match.URI.Exact}
+	case match.URI.Prefix != "":
+		if strings.HasSuffix(match.URI.Prefix, "/") {
+			paths = []string{match.URI.Prefix + "*"}
+		} else {
+			paths = []string{match.URI.Prefix, match.URI.Prefix + "/*"}
+		}
+	case match.URI.Suffix != "":
+		unsupported = append(unsupported, "uri.suffix")
+	case match.URI.Regex != "":
+		unsupported = append(unsupported, "uri.regex")
+	default:
+		paths = []string{"/*"}
+	}
+
+	if match.Scheme != nil {
+		unsupported = append(unsupported, "scheme")
+	}
+	if match.Method != nil {
+		unsupported = append(unsupported, "method")
+	}
+	if match.Authority != nil {
+		unsupported = append(unsupported, "authority")
+	}
+	if len(match.Headers) > 0 {
+		unsupported = append(unsupported, "headers")
+	}
+	if len(match.SourceLabels) > 0 {
+		unsupported = append(unsupported, "sourceLabels")
+	}
+	return paths, unsupported
+}
+
+// excludeExistingPaths leaves out the paths, which are already in one of the given path rules.
+func excludeExistingPaths(pathRules []n.ApplicationGatewayPathRule, paths []string) []string {
+	existing := make(map[string]interface{})
+	for _, pathRule := range pathRules {
+		for _, path := range *pathRule.Paths {
+			existing[path] = nil
+		}
+	}
+	var newPaths []string
+	for _, path := range paths {
+		if _, exists := existing[path]; !exists {
+			newPaths = append(newPaths, path)
+		}
+	}
+	return newPaths
+}
@@ -0,0 +1,159 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/knative/pkg/apis/istio/common/v1alpha1"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test Istio routing rules", func() {
+
+	Context("Test getIstioMatchPaths()", func() {
+		It("should translate URI matches into paths", func() {
+			paths, unsupported := getIstioMatchPaths(&v1alpha3.HTTPMatchRequest{URI: &v1alpha1.StringMatch{Exact: "/productpage"}})
+			Expect(paths).To(Equal([]string{"/productpage"}))
+			Expect(unsupported).To(BeEmpty())
+
+			paths, unsupported = getIstioMatchPaths(&v1alpha3.HTTPMatchRequest{URI: &v1alpha1.StringMatch{Prefix: "/reviews"}})
+			Expect(paths).To(Equal([]string{"/reviews", "/reviews/*"}))
+			Expect(unsupported).To(BeEmpty())
+
+			paths, unsupported = getIstioMatchPaths(&v1alpha3.HTTPMatchRequest{URI: &v1alpha1.StringMatch{Prefix: "/static/"}})
+			Expect(paths).To(Equal([]string{"/static/*"}))
+			Expect(unsupported).To(BeEmpty())
+
+			paths, unsupported = getIstioMatchPaths(&v1alpha3.HTTPMatchRequest{})
+			Expect(paths).To(Equal([]string{"/*"}))
+			Expect(unsupported).To(BeEmpty())
+		})
+
+		It("should list the conditions App Gateway cannot match on", func() {
+			_, unsupported := getIstioMatchPaths(&v1alpha3.HTTPMatchRequest{URI: &v1alpha1.StringMatch{Regex: "/reviews/[0-9]+"}})
+			Expect(unsupported).To(Equal([]string{"uri.regex"}))
+
+			_, unsupported = getIstioMatchPaths(&v1alpha3.HTTPMatchRequest{
+				Method:  &v1alpha1.StringMatch{Exact: "GET"},
+				Headers: map[string]v1alpha1.StringMatch{"end-user": {Exact: "jason"}},
+			})
+			Expect(unsupported).To(Equal([]string{"method", "headers"}))
+		})
+	})
+
+	Context("Test getIstioPathMaps()", func() {
+		gateway := &v1alpha3.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: tests.Namespace},
+			Spec: v1alpha3.GatewaySpec{
+				Servers: []v1alpha3.Server{{
+					Port:  v1alpha3.Port{Number: 8080, Protocol: v1alpha3.ProtocolHTTP},
+					Hosts: []string{"*"},
+				}},
+			},
+		}
+		destination := v1alpha3.HTTPRouteDestination{
+			Destination: v1alpha3.Destination{Host: "reviews", Port: v1alpha3.PortSelector{Number: 80}},
+		}
+		newVirtualService := func(routes ...v1alpha3.HTTPRoute) *v1alpha3.VirtualService {
+			return &v1alpha3.VirtualService{
+				ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: tests.Namespace},
+				Spec: v1alpha3.VirtualServiceSpec{
+					Hosts: []string{tests.Host, tests.OtherHost},
+					HTTP:  routes,
+				},
+			}
+		}
+		newContext := func(virtualService *v1alpha3.VirtualService) *ConfigBuilderContext {
+			cbCtx := &ConfigBuilderContext{
+				IstioGateways:        []*v1alpha3.Gateway{gateway},
+				IstioVirtualServices: []*v1alpha3.VirtualService{virtualService},
+			}
+			cbCtx.EnvVariables.EnableIstioIntegration = true
+			return cbCtx
+		}
+		listenerID := listenerIdentifier{FrontendPort: 8080, HostName: tests.Host}
+		otherListenerID := listenerIdentifier{FrontendPort: 8080, HostName: tests.OtherHost}
+
+		var cb appGwConfigBuilder
+		BeforeEach(func() {
+			cb = newConfigBuilderFixture(nil)
+		})
+
+		It("should create path rules for every host of the VirtualService", func() {
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{
+				Match: []v1alpha3.HTTPMatchRequest{
+					{URI: &v1alpha1.StringMatch{Exact: "/productpage"}},
+					{URI: &v1alpha1.StringMatch{Prefix: "/reviews"}},
+				},
+				Route: []v1alpha3.HTTPRouteDestination{destination},
+			})
+			pathMaps := cb.getIstioPathMaps(newContext(virtualService))
+			Expect(pathMaps).To(HaveLen(2))
+			for _, pathMap := range []*n.ApplicationGatewayURLPathMap{pathMaps[listenerID], pathMaps[otherListenerID]} {
+				Expect(pathMap).ToNot(BeNil())
+				Expect(*pathMap.PathRules).To(HaveLen(2))
+				Expect(*(*pathMap.PathRules)[0].Paths).To(Equal([]string{"/productpage"}))
+				Expect(*(*pathMap.PathRules)[1].Paths).To(Equal([]string{"/reviews", "/reviews/*"}))
+			}
+		})
+
+		It("should skip matches on other ports", func() {
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{
+				Match: []v1alpha3.HTTPMatchRequest{
+					{URI: &v1alpha1.StringMatch{Exact: "/productpage"}, Port: 80},
+					{URI: &v1alpha1.StringMatch{Exact: "/reviews"}, Port: 8080},
+				},
+				Route: []v1alpha3.HTTPRouteDestination{destination},
+			})
+			pathMaps := cb.getIstioPathMaps(newContext(virtualService))
+			Expect(*pathMaps[listenerID].PathRules).To(HaveLen(1))
+			Expect(*(*pathMaps[listenerID].PathRules)[0].Paths).To(Equal([]string{"/reviews"}))
+		})
+
+		It("should emit an event for unsupported matches", func() {
+			virtualService := newVirtualService(v1alpha3.HTTPRoute{
+				Match: []v1alpha3.HTTPMatchRequest{
+					{URI: &v1alpha1.StringMatch{Prefix: "/reviews"}, Headers: map[string]v1alpha1.StringMatch{"end-user": {Exact: "jason"}}},
+					{URI: &v1alpha1.StringMatch{Exact: "/productpage"}},
+				},
+				Route: []v1alpha3.HTTPRouteDestination{destination},
+			})
+			pathMaps := cb.getIstioPathMaps(newContext(virtualService))
+			Expect(*pathMaps[listenerID].PathRules).To(HaveLen(1))
+			Expect(*(*pathMaps[listenerID].PathRules)[0].Paths).To(Equal([]string{"/productpage"}))
+
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(HaveLen(1))
+			event := <-recorder.Events
+			Expect(event).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonUnsupportedFeature))
+			Expect(event).To(ContainSubstring("matches on headers"))
+		})
+
+		It("should keep paths with the first route matching them", func() {
+			virtualService := newVirtualService(
+				v1alpha3.HTTPRoute{
+					Match: []v1alpha3.HTTPMatchRequest{{URI: &v1alpha1.StringMatch{Prefix: "/reviews"}}},
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+				v1alpha3.HTTPRoute{
+					Match: []v1alpha3.HTTPMatchRequest{{URI: &v1alpha1.StringMatch{Exact: "/reviews"}}},
+					Route: []v1alpha3.HTTPRouteDestination{destination},
+				},
+			)
+			pathMaps := cb.getIstioPathMaps(newContext(virtualService))
+			Expect(*pathMaps[listenerID].PathRules).To(HaveLen(1))
+			Expect(*(*pathMaps[listenerID].PathRules)[0].Name).To(Equal(generatePathRuleName(tests.Namespace, "reviews", "0-0")))
+		})
+	})
+})
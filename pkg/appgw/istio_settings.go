@@ -25,10 +25,8 @@ func istioMatchDestinationIds(cbCtx *ConfigBuilderContext) ([]istioMatchIdentifi
 			destinations := make([]*v1alpha3.Destination, 0)
 			for _, routeDestination := range rule.Route {
 				if routeDestination.Weight != 0 {
+					// Weights are honored by the pools composed in getIstioRouteBackend.
 					destinations = append(destinations, &routeDestination.Destination)
-					/* TODO(rhea): Weights are being ignored for now, since this is not
-					yet supported on App Gateway. Include gates from routeDestination when
-					this is supported */
 				}
 				destinationID := generateIstioDestinationID(virtualService, &routeDestination.Destination)
 				destinationIDs[destinationID] = nil
@@ -64,12 +62,30 @@ func (c *appGwConfigBuilder) getIstioDestinationsAndSettingsMap(cbCtx *ConfigBui
 			logLine := fmt.Sprintf("Unable to get the service [%s]", destinationID.serviceKey())
 			glog.Errorf(logLine)
 			// TODO(rhea): add error event
-			pair := serviceBackendPortPair{
-				ServicePort: Port(destinationPortNum),
-				BackendPort: Port(destinationPortNum),
+			if destinationPortNum == 0 && destinationID.DestinationPortName != "" {
+				// The ports of endpoints carry the names of the ports of their service.
+				for port := range c.resolveIstioPortName(destinationID.DestinationPortName, &destinationID) {
+					pair := serviceBackendPortPair{
+						ServicePort: port,
+						BackendPort: port,
+					}
+					resolvedBackendPorts[pair] = nil
+				}
+			}
+			if len(resolvedBackendPorts) == 0 {
+				pair := serviceBackendPortPair{
+					ServicePort: Port(destinationPortNum),
+					BackendPort: Port(destinationPortNum),
+				}
+				resolvedBackendPorts[pair] = nil
 			}
-			resolvedBackendPorts[pair] = nil
 		} else {
+			tcpPortCount := 0
+			for _, sp := range service.Spec.Ports {
+				if sp.Protocol == v1.ProtocolTCP {
+					tcpPortCount++
+				}
+			}
 			for _, sp := range service.Spec.Ports {
 				// find the backend port number
 				// check if any service ports matches the specified ports
@@ -78,8 +94,7 @@ func (c *appGwConfigBuilder) getIstioDestinationsAndSettingsMap(cbCtx *ConfigBui
 					continue
 				}
 
-				// TODO(delqn): implement correctly port lookup by name
-				if Port(sp.Port) == destinationPortNum || sp.TargetPort.String() == fmt.Sprint(destinationPortNum) {
+				if destinationID.selectsServicePort(sp, tcpPortCount) {
 					// matched a service port with a port from the service
 					if sp.TargetPort.String() == "" {
 						// targetPort is not defined, by default targetPort == port
@@ -98,10 +113,10 @@ func (c *appGwConfigBuilder) getIstioDestinationsAndSettingsMap(cbCtx *ConfigBui
 							}
 							resolvedBackendPorts[pair] = nil
 						} else {
-							// if service port is defined by name, need to resolve
-							targetPortName := sp.TargetPort.StrVal
-							glog.V(1).Infof("resolving port name %s", targetPortName)
-							targetPortsResolved := c.resolveIstioPortName(targetPortName, &destinationID)
+							// if target port is defined by name, need to resolve; The ports of endpoints carry the
+							// names of the ports of their service.
+							glog.V(5).Infof("resolving port name [%s] for service [%s] and service port [%s]", sp.Name, destinationID.serviceKey(), destinationID.servicePort())
+							targetPortsResolved := c.resolveIstioPortName(sp.Name, &destinationID)
 							for targetPort := range targetPortsResolved {
 								pair := serviceBackendPortPair{
 									ServicePort: Port(sp.Port),
@@ -140,14 +155,8 @@ func (c *appGwConfigBuilder) getIstioDestinationsAndSettingsMap(cbCtx *ConfigBui
 	for destinationID, serviceBackendPairs := range serviceBackendPairsMap {
 		if len(serviceBackendPairs) > 1 {
 			// more than one possible backend port exposed through ingress
-			backendServicePort := ""
-			if destinationID.DestinationPort != 0 {
-				backendServicePort = fmt.Sprint(destinationID.DestinationPort)
-			} else {
-				// TODO(delqn): implement port lookup by name
-			}
 			logLine := fmt.Sprintf("service:port [%s:%s] has more than one service-backend port binding",
-				destinationID.serviceKey(), backendServicePort)
+				destinationID.serviceKey(), destinationID.servicePort())
 			glog.Warning(logLine)
 			//TODO(rhea): add error event recorder
 			return nil, nil, nil, errors.New("more than one service-backend port binding is not allowed")
@@ -174,13 +183,7 @@ func (c *appGwConfigBuilder) getIstioDestinationsAndSettingsMap(cbCtx *ConfigBui
 }
 
 func (c *appGwConfigBuilder) generateIstioHTTPSettings(destinationID istioDestinationIdentifier, port Port, cbCtx *ConfigBuilderContext) n.ApplicationGatewayBackendHTTPSettings {
-	backendServicePort := ""
-	if destinationID.DestinationPort != 0 {
-		backendServicePort = fmt.Sprint(destinationID.DestinationPort)
-	} else {
-		// TODO(delqn): Implement port lookup by name
-	}
-	httpSettingsName := generateHTTPSettingsName(destinationID.serviceFullName(), backendServicePort, port, destinationID.istioVirtualServiceIdentifier.Name)
+	httpSettingsName := generateHTTPSettingsName(destinationID.backendFullName(), destinationID.servicePort(), port, destinationID.istioVirtualServiceIdentifier.Name)
 	glog.V(5).Infof("Created a new HTTP setting w/ name: %s\n", httpSettingsName)
 	httpSettings := n.ApplicationGatewayBackendHTTPSettings{
 		Etag: to.StringPtr("*"),
@@ -192,7 +195,14 @@ func (c *appGwConfigBuilder) generateIstioHTTPSettings(destinationID istioDestin
 		},
 	}
 
-	//TODO(rhea): check relevant annotations and modify http settings accordingly
+	// App Gateway originates TLS to the backends of a DestinationRule with mode SIMPLE; Other modes are reported by
+	// reportIstioDestinationIssues.
+	if _, tls := getIstioUpstreamTLS(cbCtx, destinationID); tls != nil && tls.Mode == v1alpha3.TLSmodeSimple {
+		httpSettings.Protocol = n.HTTPS
+		if tls.Sni != "" {
+			httpSettings.HostName = to.StringPtr(tls.Sni)
+		}
+	}
 
 	return httpSettings
 }
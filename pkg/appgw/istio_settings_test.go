@@ -0,0 +1,88 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test Istio HTTP settings", func() {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: tests.Namespace},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{Name: "http", Protocol: v1.ProtocolTCP, Port: 80, TargetPort: intstr.FromString("http-alt")},
+				{Name: "metrics", Protocol: v1.ProtocolTCP, Port: 9090, TargetPort: intstr.FromInt(9090)},
+			},
+		},
+	}
+	endpoints := &v1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: tests.Namespace},
+		Subsets: []v1.EndpointSubset{{
+			Addresses: []v1.EndpointAddress{{IP: "10.0.0.1"}},
+			Ports: []v1.EndpointPort{
+				{Name: "http", Protocol: v1.ProtocolTCP, Port: 9080},
+				{Name: "metrics", Protocol: v1.ProtocolTCP, Port: 9090},
+			},
+		}},
+	}
+	newContext := func(port v1alpha3.PortSelector) (*ConfigBuilderContext, istioDestinationIdentifier) {
+		virtualService := &v1alpha3.VirtualService{
+			ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: tests.Namespace},
+			Spec: v1alpha3.VirtualServiceSpec{
+				Hosts: []string{tests.Host},
+				HTTP: []v1alpha3.HTTPRoute{{
+					Route: []v1alpha3.HTTPRouteDestination{{Destination: v1alpha3.Destination{Host: "reviews", Port: port}}},
+				}},
+			},
+		}
+		cbCtx := &ConfigBuilderContext{IstioVirtualServices: []*v1alpha3.VirtualService{virtualService}}
+		cbCtx.EnvVariables.EnableIstioIntegration = true
+		return cbCtx, generateIstioDestinationID(virtualService, &virtualService.Spec.HTTP[0].Route[0].Destination)
+	}
+
+	var cb appGwConfigBuilder
+	BeforeEach(func() {
+		cb = newConfigBuilderFixture(nil)
+		_ = cb.k8sContext.Caches.Service.Add(service)
+		_ = cb.k8sContext.Caches.Endpoints.Add(endpoints)
+	})
+
+	Context("Test port resolution", func() {
+		It("should resolve service ports selected by name", func() {
+			cbCtx, destinationID := newContext(v1alpha3.PortSelector{Name: "http"})
+			_, settingsByDestination, pairByDestination, err := cb.getIstioDestinationsAndSettingsMap(cbCtx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pairByDestination[destinationID]).To(Equal(serviceBackendPortPair{ServicePort: 80, BackendPort: 9080}))
+			Expect(*settingsByDestination[destinationID].Name).To(Equal(generateHTTPSettingsName(tests.Namespace+"-reviews", "http", 9080, "reviews")))
+		})
+
+		It("should resolve service ports selected by number", func() {
+			cbCtx, destinationID := newContext(v1alpha3.PortSelector{Number: 9090})
+			_, _, pairByDestination, err := cb.getIstioDestinationsAndSettingsMap(cbCtx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pairByDestination[destinationID]).To(Equal(serviceBackendPortPair{ServicePort: 9090, BackendPort: 9090}))
+		})
+
+		It("should select the only port of a service for destinations without a port", func() {
+			singlePortService := service.DeepCopy()
+			singlePortService.Spec.Ports = singlePortService.Spec.Ports[1:]
+			_ = cb.k8sContext.Caches.Service.Update(singlePortService)
+
+			cbCtx, destinationID := newContext(v1alpha3.PortSelector{})
+			_, _, pairByDestination, err := cb.getIstioDestinationsAndSettingsMap(cbCtx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pairByDestination[destinationID]).To(Equal(serviceBackendPortPair{ServicePort: 9090, BackendPort: 9090}))
+		})
+	})
+})
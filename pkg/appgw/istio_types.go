@@ -25,7 +25,8 @@ type istioDestinationIdentifier struct {
 	serviceIdentifier
 	istioVirtualServiceIdentifier
 
-	DestinationHost   string
-	DestinationSubset string
-	DestinationPort   uint32
+	DestinationHost     string
+	DestinationSubset   string
+	DestinationPort     uint32
+	DestinationPortName string
 }
@@ -0,0 +1,275 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
+)
+
+// weightTolerance is how far apart, as a share of traffic, approximated and requested weights may be to be equal.
+const weightTolerance = 1e-9
+
+// istioWeightedDestination is a destination of an HTTP route along with its share of the traffic.
+type istioWeightedDestination struct {
+	destinationID istioDestinationIdentifier
+	weight        int
+}
+
+// istioRouteBackend is the pool and HTTP settings the requests matching an HTTP route of a VirtualService are sent to.
+type istioRouteBackend struct {
+	pool     *n.ApplicationGatewayBackendAddressPool
+	settings *n.ApplicationGatewayBackendHTTPSettings
+
+	// approximation explains how the weights of the destinations were changed to fit a single backend pool; It is
+	// blank when the weights are honored exactly.
+	approximation string
+}
+
+// getIstioWeightedDestinations lists the destinations of the given HTTP route, which receive traffic. As in Istio, the
+// only destination of a route takes all of the traffic; Destinations of weight 0 take none. Weights are left out
+// entirely by some users; The destinations then share the traffic evenly.
+func getIstioWeightedDestinations(virtualService *v1alpha3.VirtualService, rule *v1alpha3.HTTPRoute) []istioWeightedDestination {
+	var destinations []istioWeightedDestination
+	totalWeight := 0
+	for idx := range rule.Route {
+		totalWeight += rule.Route[idx].Weight
+	}
+	for idx := range rule.Route {
+		weight := rule.Route[idx].Weight
+		if totalWeight == 0 {
+			weight = 1
+		} else if weight <= 0 {
+			continue
+		}
+		destinations = append(destinations, istioWeightedDestination{
+			destinationID: generateIstioDestinationID(virtualService, &rule.Route[idx].Destination),
+			weight:        weight,
+		})
+	}
+	return destinations
+}
+
+// getIstioRouteBackend finds the backend of the given HTTP route. Requests to a route with a single destination are
+// sent to the pool of the destination. App Gateway balances requests evenly across the addresses of a pool, so for a
+// route with several destinations a pool is composed, in which the number of addresses of each destination follows its
+// weight.
+func (c *appGwConfigBuilder) getIstioRouteBackend(virtualService *v1alpha3.VirtualService, ruleIdx int, rule *v1alpha3.HTTPRoute, poolByDestination map[istioDestinationIdentifier]*n.ApplicationGatewayBackendAddressPool, settingsByDestination map[istioDestinationIdentifier]*n.ApplicationGatewayBackendHTTPSettings) (istioRouteBackend, bool) {
+	destinations := getIstioWeightedDestinations(virtualService, rule)
+	if len(destinations) == 0 {
+		return istioRouteBackend{}, false
+	}
+
+	if len(destinations) == 1 {
+		pool, found := poolByDestination[destinations[0].destinationID]
+		if !found {
+			return istioRouteBackend{}, false
+		}
+		return istioRouteBackend{
+			pool:     pool,
+			settings: settingsByDestination[destinations[0].destinationID],
+		}, true
+	}
+
+	var notes []string
+
+	// All addresses of a pool are reached through the same HTTP settings, hence the same backend port.
+	var settings *n.ApplicationGatewayBackendHTTPSettings
+	var candidates []istioWeightedDestination
+	var available [][]n.ApplicationGatewayBackendAddress
+	seen := make(map[string]interface{})
+	for _, destination := range destinations {
+		pool, found := poolByDestination[destination.destinationID]
+		if !found || *pool.Name == DefaultBackendAddressPoolName || pool.BackendAddresses == nil {
+			notes = append(notes, fmt.Sprintf("destination %s has no endpoints", destination.destinationID.name()))
+			continue
+		}
+		destinationSettings := settingsByDestination[destination.destinationID]
+		if len(candidates) == 0 {
+			settings = destinationSettings
+		} else if backendPortOf(destinationSettings) != backendPortOf(settings) {
+			notes = append(notes, fmt.Sprintf("destination %s uses a backend port other than destination %s", destination.destinationID.name(), candidates[0].destinationID.name()))
+			continue
+		}
+
+		// An address can be in a pool only once; Addresses shared with an earlier destination stay with it.
+		addresses := make([]n.ApplicationGatewayBackendAddress, len(*pool.BackendAddresses))
+		copy(addresses, *pool.BackendAddresses)
+		sort.Sort(sorter.ByIPFQDN(addresses))
+		var unique []n.ApplicationGatewayBackendAddress
+		for _, address := range addresses {
+			key := to.String(address.IPAddress) + "/" + to.String(address.Fqdn)
+			if _, exists := seen[key]; !exists {
+				seen[key] = nil
+				unique = append(unique, address)
+			}
+		}
+		if len(unique) == 0 {
+			notes = append(notes, fmt.Sprintf("the endpoints of destination %s belong to other destinations", destination.destinationID.name()))
+			continue
+		}
+		candidates = append(candidates, destination)
+		available = append(available, unique)
+	}
+
+	if len(candidates) == 0 {
+		// None of the destinations can take traffic; The route is served like a single destination without endpoints.
+		pool, found := poolByDestination[destinations[0].destinationID]
+		if !found {
+			return istioRouteBackend{}, false
+		}
+		return istioRouteBackend{
+			pool:     pool,
+			settings: settingsByDestination[destinations[0].destinationID],
+		}, true
+	}
+
+	weights := make([]int, len(candidates))
+	limits := make([]int, len(candidates))
+	for idx := range candidates {
+		weights[idx] = candidates[idx].weight
+		limits[idx] = len(available[idx])
+	}
+	counts := approximateIstioWeights(weights, limits)
+
+	var addresses []n.ApplicationGatewayBackendAddress
+	for idx, count := range counts {
+		addresses = append(addresses, available[idx][:count]...)
+	}
+	sort.Sort(sorter.ByIPFQDN(addresses))
+
+	poolName := generateWeightedAddressPoolName(virtualService.Namespace, virtualService.Name, ruleIdx)
+	pool := &n.ApplicationGatewayBackendAddressPool{
+		Etag: to.StringPtr("*"),
+		Name: to.StringPtr(poolName),
+		ID:   to.StringPtr(c.appGwIdentifier.AddressPoolID(poolName)),
+		ApplicationGatewayBackendAddressPoolPropertiesFormat: &n.ApplicationGatewayBackendAddressPoolPropertiesFormat{
+			BackendAddresses: &addresses,
+		},
+	}
+
+	if len(notes) > 0 || !weightsMatch(weights, counts) {
+		notes = append(notes, fmt.Sprintf("weights %s are served as %s", formatWeights(destinations, weightsOf(destinations)), formatWeights(candidates, counts)))
+	}
+
+	backend := istioRouteBackend{
+		pool:     pool,
+		settings: settings,
+	}
+	if len(notes) > 0 {
+		backend.approximation = fmt.Sprintf("HTTP route %d of VirtualService %s/%s: %s", ruleIdx, virtualService.Namespace, virtualService.Name, strings.Join(notes, "; "))
+	}
+	return backend, true
+}
+
+// getIstioRoutePools lists the pools the HTTP routes of all VirtualServices send requests to, including the pools
+// composed for routes with several destinations.
+func (c *appGwConfigBuilder) getIstioRoutePools(cbCtx *ConfigBuilderContext) []*n.ApplicationGatewayBackendAddressPool {
+	_, settingsByDestination, _, _ := c.getIstioDestinationsAndSettingsMap(cbCtx)
+	poolByDestination := c.newIstioBackendPoolMap(cbCtx)
+
+	var pools []*n.ApplicationGatewayBackendAddressPool
+	for _, virtualService := range cbCtx.IstioVirtualServices {
+		for ruleIdx := range virtualService.Spec.HTTP {
+			if backend, found := c.getIstioRouteBackend(virtualService, ruleIdx, &virtualService.Spec.HTTP[ruleIdx], poolByDestination, settingsByDestination); found {
+				pools = append(pools, backend.pool)
+			}
+		}
+	}
+	return pools
+}
+
+// approximateIstioWeights picks how many addresses to take from each destination, at least one and at most the given
+// limit, so that the share of addresses is closest to the share of weight. Among equally close picks the one with the
+// most addresses wins.
+func approximateIstioWeights(weights []int, limits []int) []int {
+	totalWeight := 0
+	maxTotal := 0
+	for idx := range weights {
+		totalWeight += weights[idx]
+		maxTotal += limits[idx]
+	}
+
+	var best []int
+	bestDeviation := math.Inf(1)
+	for total := len(weights); total <= maxTotal; total++ {
+		counts := make([]int, len(weights))
+		sum := 0
+		for idx, weight := range weights {
+			count := int(math.Round(float64(weight*total) / float64(totalWeight)))
+			if count < 1 {
+				count = 1
+			}
+			if count > limits[idx] {
+				count = limits[idx]
+			}
+			counts[idx] = count
+			sum += count
+		}
+
+		deviation := 0.0
+		for idx, weight := range weights {
+			deviation += math.Abs(float64(counts[idx])/float64(sum) - float64(weight)/float64(totalWeight))
+		}
+		if deviation < bestDeviation+weightTolerance {
+			best = counts
+			bestDeviation = math.Min(deviation, bestDeviation)
+		}
+	}
+	return best
+}
+
+// weightsMatch tells whether the given counts split traffic in the same proportions as the given weights.
+func weightsMatch(weights []int, counts []int) bool {
+	totalWeight, totalCount := 0, 0
+	for idx := range weights {
+		totalWeight += weights[idx]
+		totalCount += counts[idx]
+	}
+	for idx := range weights {
+		if math.Abs(float64(counts[idx])/float64(totalCount)-float64(weights[idx])/float64(totalWeight)) > weightTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func backendPortOf(settings *n.ApplicationGatewayBackendHTTPSettings) int32 {
+	if settings == nil || settings.ApplicationGatewayBackendHTTPSettingsPropertiesFormat == nil {
+		return 0
+	}
+	return to.Int32(settings.Port)
+}
+
+func weightsOf(destinations []istioWeightedDestination) []int {
+	weights := make([]int, len(destinations))
+	for idx, destination := range destinations {
+		weights[idx] = destination.weight
+	}
+	return weights
+}
+
+// formatWeights lists the share of traffic of each destination in percent, e.g. "[reviews-v1:75% reviews-v2:25%]".
+func formatWeights(destinations []istioWeightedDestination, weights []int) string {
+	total := 0
+	for _, weight := range weights {
+		total += weight
+	}
+	shares := make([]string, len(destinations))
+	for idx, destination := range destinations {
+		share := strings.TrimSuffix(fmt.Sprintf("%.1f", 100*float64(weights[idx])/float64(total)), ".0")
+		shares[idx] = fmt.Sprintf("%s:%s%%", destination.destinationID.name(), share)
+	}
+	return fmt.Sprintf("[%s]", strings.Join(shares, " "))
+}
@@ -0,0 +1,157 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/knative/pkg/apis/istio/common/v1alpha1"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("Test Istio weighted routing", func() {
+
+	Context("Test approximateIstioWeights()", func() {
+		It("should honor weights the endpoints allow", func() {
+			Expect(approximateIstioWeights([]int{75, 25}, []int{3, 1})).To(Equal([]int{3, 1}))
+			Expect(approximateIstioWeights([]int{50, 50}, []int{3, 5})).To(Equal([]int{3, 3}))
+			Expect(approximateIstioWeights([]int{80, 20}, []int{10, 10})).To(Equal([]int{8, 2}))
+		})
+
+		It("should keep at least one address of each destination", func() {
+			Expect(approximateIstioWeights([]int{90, 10}, []int{2, 2})).To(Equal([]int{2, 1}))
+			Expect(approximateIstioWeights([]int{75, 25}, []int{2, 2})).To(Equal([]int{2, 1}))
+		})
+	})
+
+	Context("Test getIstioPathMaps() with weighted destinations", func() {
+		newService := func(name string) *v1.Service {
+			return &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: tests.Namespace},
+				Spec: v1.ServiceSpec{
+					Ports: []v1.ServicePort{{Protocol: v1.ProtocolTCP, Port: 80, TargetPort: intstr.FromInt(9080)}},
+				},
+			}
+		}
+		newEndpoints := func(name string, ips ...string) *v1.Endpoints {
+			subset := v1.EndpointSubset{Ports: []v1.EndpointPort{{Protocol: v1.ProtocolTCP, Port: 9080}}}
+			for _, ip := range ips {
+				subset.Addresses = append(subset.Addresses, v1.EndpointAddress{IP: ip})
+			}
+			return &v1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: tests.Namespace},
+				Subsets:    []v1.EndpointSubset{subset},
+			}
+		}
+		newVirtualService := func(weightV1, weightV2 int) *v1alpha3.VirtualService {
+			return &v1alpha3.VirtualService{
+				ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: tests.Namespace},
+				Spec: v1alpha3.VirtualServiceSpec{
+					Hosts: []string{tests.Host},
+					HTTP: []v1alpha3.HTTPRoute{{
+						Match: []v1alpha3.HTTPMatchRequest{{URI: &v1alpha1.StringMatch{Prefix: "/reviews"}}},
+						Route: []v1alpha3.HTTPRouteDestination{
+							{Destination: v1alpha3.Destination{Host: "reviews-v1", Port: v1alpha3.PortSelector{Number: 80}}, Weight: weightV1},
+							{Destination: v1alpha3.Destination{Host: "reviews-v2", Port: v1alpha3.PortSelector{Number: 80}}, Weight: weightV2},
+						},
+					}},
+				},
+			}
+		}
+
+		gateway := &v1alpha3.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: tests.Namespace},
+			Spec: v1alpha3.GatewaySpec{
+				Servers: []v1alpha3.Server{{
+					Port:  v1alpha3.Port{Number: 80, Protocol: v1alpha3.ProtocolHTTP},
+					Hosts: []string{tests.Host},
+				}},
+			},
+		}
+
+		var cb appGwConfigBuilder
+		BeforeEach(func() {
+			cb = newConfigBuilderFixture(nil)
+			_ = cb.k8sContext.Caches.Service.Add(newService("reviews-v1"))
+			_ = cb.k8sContext.Caches.Service.Add(newService("reviews-v2"))
+			_ = cb.k8sContext.Caches.Endpoints.Add(newEndpoints("reviews-v1", "10.0.0.1", "10.0.0.2", "10.0.0.3"))
+			_ = cb.k8sContext.Caches.Endpoints.Add(newEndpoints("reviews-v2", "10.0.1.1", "10.0.1.2"))
+		})
+
+		getPathRulePool := func(cbCtx *ConfigBuilderContext) string {
+			pathMaps := cb.getIstioPathMaps(cbCtx)
+			pathMap := pathMaps[listenerIdentifier{FrontendPort: 80, HostName: tests.Host}]
+			Expect(pathMap).ToNot(BeNil())
+			Expect(*pathMap.PathRules).To(HaveLen(1))
+			return *(*pathMap.PathRules)[0].BackendAddressPool.ID
+		}
+
+		getPool := func(cbCtx *ConfigBuilderContext, poolName string) []string {
+			for _, pool := range cb.getPools(cbCtx) {
+				if *pool.Name == poolName {
+					var addresses []string
+					for _, address := range *pool.BackendAddresses {
+						addresses = append(addresses, to.String(address.IPAddress))
+					}
+					return addresses
+				}
+			}
+			Fail(fmt.Sprintf("pool %s not found", poolName))
+			return nil
+		}
+
+		It("should compose a pool following the weights", func() {
+			cbCtx := &ConfigBuilderContext{IstioGateways: []*v1alpha3.Gateway{gateway}, IstioVirtualServices: []*v1alpha3.VirtualService{newVirtualService(75, 25)}}
+			cbCtx.EnvVariables.EnableIstioIntegration = true
+
+			poolName := generateWeightedAddressPoolName(tests.Namespace, "reviews", 0)
+			Expect(getPathRulePool(cbCtx)).To(Equal(cb.appGwIdentifier.AddressPoolID(poolName)))
+			Expect(getPool(cbCtx, poolName)).To(Equal([]string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.1.1"}))
+			Expect(cb.recorder.(*record.FakeRecorder).Events).To(BeEmpty())
+		})
+
+		It("should emit an event when the weights are approximated", func() {
+			cbCtx := &ConfigBuilderContext{IstioGateways: []*v1alpha3.Gateway{gateway}, IstioVirtualServices: []*v1alpha3.VirtualService{newVirtualService(95, 5)}}
+			cbCtx.EnvVariables.EnableIstioIntegration = true
+
+			poolName := generateWeightedAddressPoolName(tests.Namespace, "reviews", 0)
+			Expect(getPathRulePool(cbCtx)).To(Equal(cb.appGwIdentifier.AddressPoolID(poolName)))
+			Expect(getPool(cbCtx, poolName)).To(Equal([]string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.1.1"}))
+
+			recorder := cb.recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(HaveLen(1))
+			event := <-recorder.Events
+			Expect(event).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonWeightsApproximated))
+			Expect(event).To(ContainSubstring("[reviews-v1:95% reviews-v2:5%] are served as [reviews-v1:75% reviews-v2:25%]"))
+		})
+
+		It("should send a route with one destination to the pool of the destination", func() {
+			virtualService := newVirtualService(100, 0)
+			cbCtx := &ConfigBuilderContext{
+				IstioGateways:        []*v1alpha3.Gateway{gateway},
+				IstioVirtualServices: []*v1alpha3.VirtualService{virtualService},
+			}
+			cbCtx.EnvVariables.EnableIstioIntegration = true
+
+			poolName := generateAddressPoolName(tests.Namespace+"-reviews-v1", "80", 9080)
+			Expect(getPathRulePool(cbCtx)).To(Equal(cb.appGwIdentifier.AddressPoolID(poolName)))
+			for _, pool := range cb.getPools(cbCtx) {
+				Expect(strings.Contains(*pool.Name, "weighted")).To(BeFalse())
+			}
+		})
+	})
+})
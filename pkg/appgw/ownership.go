@@ -0,0 +1,61 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"k8s.io/api/extensions/v1beta1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+)
+
+// addIngressOwnership records in the ownership report which ingresses each generated sub-resource originates from. It
+// uses the listeners, settings and probes memoized during the build, so that the ingresses are not processed again.
+func (c *appGwConfigBuilder) addIngressOwnership(cbCtx *ConfigBuilderContext) {
+	if c.ownership == nil {
+		return
+	}
+
+	listenerConfigsByIngress := c.getListenerConfigsByIngress(cbCtx)
+	for _, ingress := range cbCtx.IngressList {
+		ingressName := getIngressName(ingress)
+		for listenerID, config := range listenerConfigsByIngress[ingress] {
+			c.ownership.AddIngress(brownfield.KindListener, generateListenerName(listenerID), ingressName)
+			c.ownership.AddIngress(brownfield.KindRoutingRule, generateRequestRoutingRuleName(listenerID), ingressName)
+			c.ownership.AddIngress(brownfield.KindURLPathMap, generateURLPathMapName(listenerID), ingressName)
+			c.ownership.AddIngress(brownfield.KindFrontendPort, generateFrontendPortName(listenerID.FrontendPort), ingressName)
+			if config.SslRedirectConfigurationName != "" {
+				c.ownership.AddIngress(brownfield.KindRedirect, config.SslRedirectConfigurationName, ingressName)
+			}
+			if config.Protocol == n.HTTPS {
+				c.ownership.AddIngress(brownfield.KindSslCertificate, c.getSslCertificateName(config.Secret), ingressName)
+			}
+		}
+	}
+
+	if c.mem.settingsByBackend != nil {
+		for backendID, settings := range *c.mem.settingsByBackend {
+			c.ownership.AddIngress(brownfield.KindBackendHTTPSettings, *settings.Name, getIngressName(backendID.Ingress))
+		}
+	}
+	if c.mem.serviceBackendPairsByBackend != nil {
+		for backendID, serviceBackendPair := range *c.mem.serviceBackendPairsByBackend {
+			poolName := generateAddressPoolName(backendID.serviceFullName(), backendID.Backend.ServicePort.String(), serviceBackendPair.BackendPort)
+			c.ownership.AddIngress(brownfield.KindBackendPool, poolName, getIngressName(backendID.Ingress))
+		}
+	}
+	if c.mem.probesByBackend != nil {
+		for backendID, probe := range *c.mem.probesByBackend {
+			c.ownership.AddIngress(brownfield.KindProbe, *probe.Name, getIngressName(backendID.Ingress))
+		}
+	}
+}
+
+func getIngressName(ingress *v1beta1.Ingress) string {
+	return fmt.Sprintf("%s/%s", ingress.Namespace, ingress.Name)
+}
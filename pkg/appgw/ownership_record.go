@@ -0,0 +1,492 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"fmt"
+	"sort"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure/tags"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// ownershipTracker holds the App Gateway config as it was before the build, along with the records of the
+// sub-resources AGIC created in earlier builds, for this cluster and for the other clusters sharing the App Gateway.
+type ownershipTracker struct {
+	cluster       string
+	existing      brownfield.ExistingResources
+	existingNames brownfield.OwnershipRecord
+	previous      *brownfield.ClusterRecord
+
+	// others are the sub-resources recorded by the other clusters.
+	others brownfield.OwnershipRecord
+
+	// ownsAll is set when there is no record, but AGIC has already been managing the App Gateway; All existing
+	// config, which is not prohibited or recorded by another cluster, was AGIC's to change.
+	ownsAll bool
+}
+
+// newOwnershipTracker must be called before the build changes the App Gateway config.
+func (c *appGwConfigBuilder) newOwnershipTracker(cluster string) *ownershipTracker {
+	tracker := &ownershipTracker{
+		cluster:       cluster,
+		existing:      brownfield.NewExistingResources(c.appGw, nil, nil, nil),
+		existingNames: brownfield.NewOwnershipRecord(c.appGw),
+		others:        make(brownfield.OwnershipRecord),
+	}
+	records, err := brownfield.GetClusterRecords(c.appGw)
+	for recordCluster, record := range records {
+		if recordCluster == cluster {
+			tracker.previous = record
+			continue
+		}
+		for kind, names := range record.Resources {
+			tracker.others.Add(kind, names...)
+		}
+	}
+	switch {
+	case err != nil:
+		glog.Errorf("[ownership] %s; Existing config, which AGIC does not generate, will be retained", err)
+	case tracker.previous == nil:
+		_, tracker.ownsAll = c.appGw.Tags[tags.ManagedByK8sIngress]
+	}
+	return tracker
+}
+
+// owns tells whether AGIC created the named existing sub-resource. Without a record, and on an App Gateway AGIC
+// has not managed before, only the sub-resources AGIC generates again are considered its own.
+// Sub-resources recorded by another cluster are shared for as long as this cluster generates them too.
+func (t *ownershipTracker) owns(kind brownfield.ResourceKind, name string, generated brownfield.OwnershipRecord) bool {
+	if t.others.Has(kind, name) {
+		return generated.Has(kind, name)
+	}
+	if t.previous != nil {
+		return t.previous.Resources.Has(kind, name)
+	}
+	if t.ownsAll && kind == brownfield.KindRewriteRuleSet {
+		// Without the record AGIC kept the rewrite rule sets not named like the ones it generates.
+		return isGeneratedRewriteRuleSetName(name) || generated.Has(kind, name)
+	}
+	return t.ownsAll || generated.Has(kind, name)
+}
+
+// retainForeignResources puts the existing sub-resources AGIC did not create back into the App Gateway config and
+// records the ones it did create. Foreign sub-resources take precedence over generated ones with the same name.
+func (c *appGwConfigBuilder) retainForeignResources(cbCtx *ConfigBuilderContext, tracker *ownershipTracker) {
+	foreign := make(brownfield.OwnershipRecord)
+	generated := brownfield.NewOwnershipRecord(c.appGw)
+	for kind, names := range tracker.existingNames {
+		for _, name := range names {
+			if tracker.owns(kind, name, generated) {
+				continue
+			}
+			if generated.Has(kind, name) {
+				glog.Warningf("[ownership] AGIC generated %s %s, which exists already and was not created by AGIC; Retaining the existing one", kind, name)
+			}
+			foreign.Add(kind, name)
+		}
+	}
+
+	c.resolveListenerConflicts(cbCtx, tracker, foreign)
+
+	// The addresses this cluster contributes are recorded before the pools are combined with those of other clusters.
+	poolAddresses := getPoolAddresses(c.appGw)
+	if tracker.cluster != "" {
+		c.combineSharedPools(tracker)
+	}
+
+	c.mergeForeignResources(tracker.existing, foreign)
+	for kind, names := range foreign {
+		c.ownership.AddForeign(kind, names)
+	}
+
+	record := brownfield.ClusterRecord{
+		Cluster:   tracker.cluster,
+		Resources: brownfield.NewOwnershipRecord(c.appGw),
+	}
+	for kind, names := range foreign {
+		record.Resources.Remove(kind, names...)
+	}
+	for kind := range record.Resources {
+		for _, name := range c.ownership.Names(kind, brownfield.OwnershipProhibited) {
+			// Prohibited config is retained as is; It stays AGIC's only when AGIC created it in the first place.
+			if tracker.previous == nil || !tracker.previous.Resources.Has(kind, name) {
+				record.Resources.Remove(kind, name)
+			}
+		}
+	}
+	if tracker.cluster != "" {
+		record.PoolAddresses = make(map[string][]string)
+		for _, poolName := range record.Resources[brownfield.KindBackendPool] {
+			if addresses := poolAddresses[poolName]; len(addresses) > 0 {
+				record.PoolAddresses[poolName] = addresses
+			}
+		}
+	}
+	if err := brownfield.SetOwnershipRecord(&c.appGw, record); err != nil {
+		glog.Errorf("[ownership] %s; The previous ownership record is kept and sub-resources created since will be retained", err)
+	}
+}
+
+// resolveListenerConflicts looks for generated listeners, which clash with existing listeners AGIC does not own:
+// A listener of the same name shared with another cluster, which configured a different frontend, hostname,
+// protocol or certificate, or a foreign listener of another name on the same frontend, port and hostname.
+// The existing listener always wins. In the first case it replaces the generated one; In the second the generated
+// listener is removed along with its routing rule.
+func (c *appGwConfigBuilder) resolveListenerConflicts(cbCtx *ConfigBuilderContext, tracker *ownershipTracker, foreign brownfield.OwnershipRecord) {
+	current := brownfield.NewExistingResources(c.appGw, nil, nil, nil)
+	existingByName := make(map[string]n.ApplicationGatewayHTTPListener)
+	for _, listener := range tracker.existing.Listeners {
+		existingByName[*listener.Name] = listener
+	}
+
+	replacements := make(map[string]n.ApplicationGatewayHTTPListener)
+	for _, listener := range current.Listeners {
+		target := current.GetListenerTarget(listener)
+		if existing, exists := existingByName[*listener.Name]; exists {
+			if foreign.Has(brownfield.KindListener, *listener.Name) || !tracker.others.Has(brownfield.KindListener, *listener.Name) {
+				continue
+			}
+			if tracker.existing.GetListenerTarget(existing) == target && sameListenerSettings(existing, listener) {
+				continue
+			}
+			foreign.Add(brownfield.KindListener, *listener.Name)
+			c.reportListenerConflict(cbCtx, tracker, *listener.Name, *existing.Name)
+			continue
+		}
+		for _, existing := range tracker.existing.Listeners {
+			if !foreign.Has(brownfield.KindListener, *existing.Name) || tracker.existing.GetListenerTarget(existing) != target {
+				continue
+			}
+			replacements[*listener.Name] = existing
+			c.reportListenerConflict(cbCtx, tracker, *listener.Name, *existing.Name)
+			break
+		}
+	}
+	if len(replacements) > 0 {
+		c.removeListeners(replacements)
+	}
+}
+
+// reportListenerConflict logs the conflict and emits a warning event on the ingresses the listener was generated from.
+func (c *appGwConfigBuilder) reportListenerConflict(cbCtx *ConfigBuilderContext, tracker *ownershipTracker, listenerName string, existingName string) {
+	owner := "not created by AGIC"
+	if tracker.others.Has(brownfield.KindListener, existingName) {
+		owner = "managed by the Ingress Controller of another cluster"
+	}
+	message := fmt.Sprintf("Listener %s conflicts with existing listener %s, which is %s; The existing listener is retained", listenerName, existingName, owner)
+	glog.Warning("[ownership] ", message)
+	for _, ingress := range c.getIngressesByListener(cbCtx)[listenerName] {
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonListenerConflict, message)
+	}
+}
+
+// removeListeners drops the given generated listeners along with their routing rules and URL path maps. Redirects
+// to a dropped listener are pointed to its replacement, which serves the same frontend, port and hostname.
+func (c *appGwConfigBuilder) removeListeners(replacements map[string]n.ApplicationGatewayHTTPListener) {
+	var listeners []n.ApplicationGatewayHTTPListener
+	for _, listener := range *c.appGw.HTTPListeners {
+		if _, isDropped := replacements[*listener.Name]; !isDropped {
+			listeners = append(listeners, listener)
+		}
+	}
+	c.appGw.HTTPListeners = &listeners
+
+	droppedPathMaps := make(map[string]interface{})
+	var rules []n.ApplicationGatewayRequestRoutingRule
+	for _, rule := range *c.appGw.RequestRoutingRules {
+		if rule.ApplicationGatewayRequestRoutingRulePropertiesFormat != nil && rule.HTTPListener != nil && rule.HTTPListener.ID != nil {
+			if _, isDropped := replacements[utils.GetLastChunkOfSlashed(*rule.HTTPListener.ID)]; isDropped {
+				if rule.URLPathMap != nil && rule.URLPathMap.ID != nil {
+					droppedPathMaps[utils.GetLastChunkOfSlashed(*rule.URLPathMap.ID)] = nil
+				}
+				continue
+			}
+		}
+		rules = append(rules, rule)
+	}
+	c.appGw.RequestRoutingRules = &rules
+
+	if c.appGw.URLPathMaps != nil {
+		var pathMaps []n.ApplicationGatewayURLPathMap
+		for _, pathMap := range *c.appGw.URLPathMaps {
+			if _, isDropped := droppedPathMaps[*pathMap.Name]; !isDropped {
+				pathMaps = append(pathMaps, pathMap)
+			}
+		}
+		c.appGw.URLPathMaps = &pathMaps
+	}
+
+	if c.appGw.RedirectConfigurations != nil {
+		for idx := range *c.appGw.RedirectConfigurations {
+			redirect := &(*c.appGw.RedirectConfigurations)[idx]
+			if redirect.ApplicationGatewayRedirectConfigurationPropertiesFormat == nil || redirect.TargetListener == nil || redirect.TargetListener.ID == nil {
+				continue
+			}
+			if replacement, isDropped := replacements[utils.GetLastChunkOfSlashed(*redirect.TargetListener.ID)]; isDropped {
+				redirect.TargetListener = &n.SubResource{ID: to.StringPtr(c.appGwIdentifier.listenerID(*replacement.Name))}
+			}
+		}
+	}
+}
+
+// combineSharedPools adds the backend addresses other clusters contributed to the pools this cluster shares with them.
+// The addresses this cluster contributed in the previous build are left out of the existing pool, so that the
+// addresses of removed pods do not linger.
+func (c *appGwConfigBuilder) combineSharedPools(tracker *ownershipTracker) {
+	existingPools := make(map[string]n.ApplicationGatewayBackendAddressPool)
+	for _, pool := range tracker.existing.BackendPools {
+		existingPools[*pool.Name] = pool
+	}
+
+	for idx := range *c.appGw.BackendAddressPools {
+		pool := &(*c.appGw.BackendAddressPools)[idx]
+		existing, exists := existingPools[*pool.Name]
+		if !exists || !tracker.others.Has(brownfield.KindBackendPool, *pool.Name) {
+			continue
+		}
+
+		contributed := make(map[string]interface{})
+		if tracker.previous != nil {
+			for _, address := range tracker.previous.PoolAddresses[*pool.Name] {
+				contributed[address] = nil
+			}
+		}
+
+		addresses := append([]n.ApplicationGatewayBackendAddress{}, backendAddresses(*pool)...)
+		seen := make(map[string]interface{})
+		for _, address := range addresses {
+			seen[backendAddressKey(address)] = nil
+		}
+		for _, address := range backendAddresses(existing) {
+			key := backendAddressKey(address)
+			_, isDuplicate := seen[key]
+			_, isContributed := contributed[key]
+			if isDuplicate || isContributed {
+				continue
+			}
+			seen[key] = nil
+			addresses = append(addresses, address)
+		}
+		sort.Sort(sorter.ByIPFQDN(addresses))
+		if pool.ApplicationGatewayBackendAddressPoolPropertiesFormat == nil {
+			pool.ApplicationGatewayBackendAddressPoolPropertiesFormat = &n.ApplicationGatewayBackendAddressPoolPropertiesFormat{}
+		}
+		pool.BackendAddresses = &addresses
+	}
+}
+
+// getIngressesByListener lists the ingresses each generated listener originates from, by listener name.
+func (c *appGwConfigBuilder) getIngressesByListener(cbCtx *ConfigBuilderContext) map[string][]*v1beta1.Ingress {
+	ingressesByListener := make(map[string][]*v1beta1.Ingress)
+	listenerConfigsByIngress := c.getListenerConfigsByIngress(cbCtx)
+	for _, ingress := range cbCtx.IngressList {
+		for listenerID := range listenerConfigsByIngress[ingress] {
+			listenerName := generateListenerName(listenerID)
+			ingressesByListener[listenerName] = append(ingressesByListener[listenerName], ingress)
+		}
+	}
+	return ingressesByListener
+}
+
+// sameListenerSettings compares the protocol and certificate of two listeners; Frontend, port and hostname are
+// compared through their targets.
+func sameListenerSettings(first n.ApplicationGatewayHTTPListener, second n.ApplicationGatewayHTTPListener) bool {
+	if first.ApplicationGatewayHTTPListenerPropertiesFormat == nil || second.ApplicationGatewayHTTPListenerPropertiesFormat == nil {
+		return first.ApplicationGatewayHTTPListenerPropertiesFormat == second.ApplicationGatewayHTTPListenerPropertiesFormat
+	}
+	certName := func(listener n.ApplicationGatewayHTTPListener) string {
+		if listener.SslCertificate == nil || listener.SslCertificate.ID == nil {
+			return ""
+		}
+		return utils.GetLastChunkOfSlashed(*listener.SslCertificate.ID)
+	}
+	return first.Protocol == second.Protocol && certName(first) == certName(second)
+}
+
+// getPoolAddresses lists the backend addresses of each pool of the given App Gateway, by pool name.
+func getPoolAddresses(appGw n.ApplicationGateway) map[string][]string {
+	poolAddresses := make(map[string][]string)
+	if appGw.BackendAddressPools == nil {
+		return poolAddresses
+	}
+	for _, pool := range *appGw.BackendAddressPools {
+		for _, address := range backendAddresses(pool) {
+			poolAddresses[*pool.Name] = append(poolAddresses[*pool.Name], backendAddressKey(address))
+		}
+	}
+	return poolAddresses
+}
+
+func backendAddresses(pool n.ApplicationGatewayBackendAddressPool) []n.ApplicationGatewayBackendAddress {
+	if pool.ApplicationGatewayBackendAddressPoolPropertiesFormat == nil || pool.BackendAddresses == nil {
+		return nil
+	}
+	return *pool.BackendAddresses
+}
+
+func backendAddressKey(address n.ApplicationGatewayBackendAddress) string {
+	if address.IPAddress != nil {
+		return *address.IPAddress
+	}
+	if address.Fqdn != nil {
+		return *address.Fqdn
+	}
+	return ""
+}
+
+// mergeForeignResources adds the foreign sub-resources to the App Gateway config, replacing generated ones with the same name.
+func (c *appGwConfigBuilder) mergeForeignResources(existing brownfield.ExistingResources, foreign brownfield.OwnershipRecord) {
+	current := brownfield.NewExistingResources(c.appGw, nil, nil, nil)
+
+	// Listeners and rules are matched by name; brownfield.MergeListeners and brownfield.MergeRules combine them by
+	// frontend and by listener, which would let generated config take the place of foreign config.
+	var listeners []n.ApplicationGatewayHTTPListener
+	for _, listener := range current.Listeners {
+		if !foreign.Has(brownfield.KindListener, *listener.Name) {
+			listeners = append(listeners, listener)
+		}
+	}
+	for _, listener := range existing.Listeners {
+		if foreign.Has(brownfield.KindListener, *listener.Name) {
+			listeners = append(listeners, listener)
+		}
+	}
+	sort.Sort(sorter.ByListenerName(listeners))
+	c.appGw.HTTPListeners = &listeners
+
+	var rules []n.ApplicationGatewayRequestRoutingRule
+	for _, rule := range current.RoutingRules {
+		if !foreign.Has(brownfield.KindRoutingRule, *rule.Name) {
+			rules = append(rules, rule)
+		}
+	}
+	for _, rule := range existing.RoutingRules {
+		if foreign.Has(brownfield.KindRoutingRule, *rule.Name) {
+			rules = append(rules, rule)
+		}
+	}
+	sort.Sort(sorter.ByRequestRoutingRuleName(rules))
+	c.appGw.RequestRoutingRules = &rules
+
+	var pathMaps []n.ApplicationGatewayURLPathMap
+	for _, pathMap := range existing.URLPathMaps {
+		if foreign.Has(brownfield.KindURLPathMap, *pathMap.Name) {
+			pathMaps = append(pathMaps, pathMap)
+		}
+	}
+	mergedPathMaps := brownfield.MergePathMaps(current.URLPathMaps, pathMaps)
+	sort.Sort(sorter.ByPathMap(mergedPathMaps))
+	c.appGw.URLPathMaps = &mergedPathMaps
+
+	var pools []n.ApplicationGatewayBackendAddressPool
+	for _, pool := range existing.BackendPools {
+		if foreign.Has(brownfield.KindBackendPool, *pool.Name) {
+			pools = append(pools, pool)
+		}
+	}
+	mergedPools := brownfield.MergePools(current.BackendPools, pools)
+	sort.Sort(sorter.ByBackendPoolName(mergedPools))
+	c.appGw.BackendAddressPools = &mergedPools
+
+	var settings []n.ApplicationGatewayBackendHTTPSettings
+	for _, setting := range existing.HTTPSettings {
+		if foreign.Has(brownfield.KindBackendHTTPSettings, *setting.Name) {
+			settings = append(settings, setting)
+		}
+	}
+	mergedSettings := brownfield.MergeHTTPSettings(current.HTTPSettings, settings)
+	sort.Sort(sorter.BySettingsName(mergedSettings))
+	c.appGw.BackendHTTPSettingsCollection = &mergedSettings
+
+	var probes []n.ApplicationGatewayProbe
+	for _, probe := range existing.Probes {
+		if foreign.Has(brownfield.KindProbe, *probe.Name) {
+			probes = append(probes, probe)
+		}
+	}
+	mergedProbes := brownfield.MergeProbes(current.Probes, probes)
+	sort.Sort(sorter.ByHealthProbeName(mergedProbes))
+	c.appGw.Probes = &mergedProbes
+
+	var ports []n.ApplicationGatewayFrontendPort
+	for _, port := range existing.Ports {
+		if foreign.Has(brownfield.KindFrontendPort, *port.Name) {
+			ports = append(ports, port)
+		}
+	}
+	// Ports are merged by port number and the first one wins; A foreign port takes the place of the generated port
+	// with the same number, and generated listeners are pointed to it.
+	mergedPorts := brownfield.MergePorts(ports, current.Ports)
+	sort.Sort(sorter.ByFrontendPortName(mergedPorts))
+	c.appGw.FrontendPorts = &mergedPorts
+	portIDByNumber := make(map[int32]string)
+	for _, port := range mergedPorts {
+		if port.ID != nil {
+			portIDByNumber[*port.Port] = *port.ID
+		}
+	}
+	generatedPortNumbers := make(map[string]int32)
+	for _, port := range current.Ports {
+		if port.ID != nil {
+			generatedPortNumbers[*port.ID] = *port.Port
+		}
+	}
+	for idx := range listeners {
+		listener := &listeners[idx]
+		if listener.ApplicationGatewayHTTPListenerPropertiesFormat == nil || listener.FrontendPort == nil || listener.FrontendPort.ID == nil {
+			continue
+		}
+		if portNumber, isGenerated := generatedPortNumbers[*listener.FrontendPort.ID]; isGenerated {
+			portID, exists := portIDByNumber[portNumber]
+			if !exists || portID == *listener.FrontendPort.ID {
+				continue
+			}
+			listener.FrontendPort = resourceRef(portID)
+		}
+	}
+
+	var redirects []n.ApplicationGatewayRedirectConfiguration
+	for _, redirect := range existing.Redirects {
+		if foreign.Has(brownfield.KindRedirect, *redirect.Name) {
+			redirects = append(redirects, redirect)
+		}
+	}
+	mergedRedirects := brownfield.MergeRedirects(current.Redirects, redirects)
+	sort.Sort(sorter.ByRedirectName(mergedRedirects))
+	c.appGw.RedirectConfigurations = &mergedRedirects
+
+	var certs []n.ApplicationGatewaySslCertificate
+	for _, cert := range existing.Certificates {
+		if foreign.Has(brownfield.KindSslCertificate, *cert.Name) {
+			certs = append(certs, cert)
+		}
+	}
+	mergedCerts := brownfield.MergeCerts(current.Certificates, certs)
+	sort.Sort(sorter.ByCertificateName(mergedCerts))
+	c.appGw.SslCertificates = &mergedCerts
+
+	var rewriteRuleSets []n.ApplicationGatewayRewriteRuleSet
+	for _, rewriteRuleSet := range existing.RewriteRuleSets {
+		if foreign.Has(brownfield.KindRewriteRuleSet, *rewriteRuleSet.Name) {
+			rewriteRuleSets = append(rewriteRuleSets, rewriteRuleSet)
+		}
+	}
+	if c.appGw.RewriteRuleSets != nil || len(rewriteRuleSets) > 0 {
+		mergedRewriteRuleSets := brownfield.MergeRewriteRuleSets(current.RewriteRuleSets, rewriteRuleSets)
+		sort.Sort(sorter.ByRewriteRuleSetName(mergedRewriteRuleSets))
+		c.appGw.RewriteRuleSets = &mergedRewriteRuleSets
+	}
+}
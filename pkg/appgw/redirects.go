@@ -34,13 +34,26 @@ func (c *appGwConfigBuilder) getRedirectConfigurations(cbCtx *ConfigBuilderConte
 		}
 	}
 
+	if cbCtx.EnvVariables.EnableIstioIntegration {
+		// The HTTPS listeners of Istio Gateway servers carry the name of the redirect of HTTP servers with httpsRedirect.
+		for listenerID, listenerConfig := range c.getListenerConfigsFromIstio(cbCtx.IstioGateways, cbCtx.IstioVirtualServices) {
+			if listenerConfig.Protocol == n.HTTPS && listenerConfig.SslRedirectConfigurationName != "" {
+				targetListener := resourceRef(c.appGwIdentifier.listenerID(generateListenerName(listenerID)))
+				redirectConfigs = append(redirectConfigs, c.newSSLRedirectConfig(listenerConfig, targetListener))
+			}
+		}
+
+		// HTTP routes with a redirect have one for each listener they take requests of.
+		redirectConfigs = append(redirectConfigs, c.getIstioRouteRedirects(cbCtx)...)
+	}
+
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		er := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, cbCtx.ManagedTargets, nil)
 
 		// Listeners we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
 		existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedRedirects()
 
-		brownfield.LogRedirects(existingBlacklisted, existingNonBlacklisted, redirectConfigs)
+		c.ownership.AddRedirects(existingBlacklisted, existingNonBlacklisted, redirectConfigs)
 
 		// MergeRedirects would produce unique list of redirects based on Name. Blacklisted redirects,
 		// which have the same name as a managed redirects would be overwritten.
@@ -23,12 +23,12 @@ func (c *appGwConfigBuilder) RequestRoutingRules(cbCtx *ConfigBuilderContext) er
 	requestRoutingRules, pathMaps := c.getRules(cbCtx)
 
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, cbCtx.ManagedTargets, nil)
 		{
 			// PathMaps we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
 			existingBlacklisted, existingNonBlacklisted := rCtx.GetBlacklistedPathMaps()
 
-			brownfield.LogPathMaps(existingBlacklisted, existingNonBlacklisted, pathMaps)
+			c.ownership.AddPathMaps(existingBlacklisted, existingNonBlacklisted, pathMaps)
 
 			// MergePathMaps would produce unique list of routing rules based on Name. Routing rules, which have the same name
 			// as a managed rule would be overwritten.
@@ -40,12 +40,12 @@ func (c *appGwConfigBuilder) RequestRoutingRules(cbCtx *ConfigBuilderContext) er
 	c.appGw.URLPathMaps = &pathMaps
 
 	if cbCtx.EnvVariables.EnableBrownfieldDeployment {
-		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, nil)
+		rCtx := brownfield.NewExistingResources(c.appGw, cbCtx.ProhibitedTargets, cbCtx.ManagedTargets, nil)
 		{
 			// RoutingRules we obtained from App Gateway - we segment them into ones AGIC is and is not allowed to change.
 			existingBlacklisted, existingNonBlacklisted := rCtx.GetBlacklistedRoutingRules()
 
-			brownfield.LogRules(existingBlacklisted, existingNonBlacklisted, requestRoutingRules)
+			c.ownership.AddRules(existingBlacklisted, existingNonBlacklisted, requestRoutingRules)
 
 			// MergeRules would produce unique list of routing rules based on Name. Routing rules, which have the same name
 			// as a managed rule would be overwritten.
@@ -87,6 +87,7 @@ func (c *appGwConfigBuilder) getRules(cbCtx *ConfigBuilderContext) ([]n.Applicat
 			if rule.RedirectConfiguration == nil {
 				rule.BackendAddressPool = urlPathMap.DefaultBackendAddressPool
 				rule.BackendHTTPSettings = urlPathMap.DefaultBackendHTTPSettings
+				rule.RewriteRuleSet = urlPathMap.DefaultRewriteRuleSet
 			}
 		} else {
 			// Path-based Rule
@@ -0,0 +1,54 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package appgw
+
+import (
+	"sort"
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
+)
+
+// RewriteRuleSets configures the rewrite rule sets of the Istio HTTP routes, which manipulate headers. Rewrite rule
+// sets AGIC did not create are kept as they are; With the ownership record they are told apart by the record,
+// otherwise by name.
+func (c *appGwConfigBuilder) RewriteRuleSets(cbCtx *ConfigBuilderContext) {
+	var rewriteRuleSets []n.ApplicationGatewayRewriteRuleSet
+	if cbCtx.EnvVariables.EnableIstioIntegration {
+		rewriteRuleSets = c.getIstioRewriteRuleSets(cbCtx)
+	}
+
+	generated := make(map[string]interface{})
+	for _, rewriteRuleSet := range rewriteRuleSets {
+		generated[*rewriteRuleSet.Name] = nil
+	}
+	if c.appGw.RewriteRuleSets == nil && len(rewriteRuleSets) == 0 {
+		return
+	}
+	recordsOwnership := cbCtx.EnvVariables.EnableOwnershipRecord || cbCtx.EnvVariables.ClusterID != ""
+	if c.appGw.RewriteRuleSets != nil && !recordsOwnership {
+		for _, rewriteRuleSet := range *c.appGw.RewriteRuleSets {
+			if rewriteRuleSet.Name == nil || isGeneratedRewriteRuleSetName(*rewriteRuleSet.Name) {
+				continue
+			}
+			if _, exists := generated[*rewriteRuleSet.Name]; exists {
+				continue
+			}
+			rewriteRuleSets = append(rewriteRuleSets, rewriteRuleSet)
+		}
+	}
+
+	sort.Sort(sorter.ByRewriteRuleSetName(rewriteRuleSets))
+	c.appGw.RewriteRuleSets = &rewriteRuleSets
+}
+
+// isGeneratedRewriteRuleSetName tells whether a rewrite rule set with the given name was created by AGIC; Those no
+// longer generated are removed.
+func isGeneratedRewriteRuleSetName(name string) bool {
+	return strings.HasPrefix(name, agPrefix+prefixRewriteRuleSet+"-")
+}
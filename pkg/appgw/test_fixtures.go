@@ -80,6 +80,12 @@ func keyFunc(obj interface{}) (string, error) {
 	if ingress, ok := obj.(*v1beta1.Ingress); ok {
 		return fmt.Sprintf("%s/%s", ingress.Namespace, ingress.Name), nil
 	}
+	if pod, ok := obj.(*v1.Pod); ok {
+		return fmt.Sprintf("%s/%s", pod.Namespace, pod.Name), nil
+	}
+	if secret, ok := obj.(*v1.Secret); ok {
+		return fmt.Sprintf("%s/%s", secret.Namespace, secret.Name), nil
+	}
 	return fmt.Sprintf("%s/%s", tests.Namespace, tests.ServiceName), nil
 }
 
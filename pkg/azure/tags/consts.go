@@ -9,4 +9,10 @@ package tags
 const(
 	ManagedByK8sIngress = "managed-by-k8s-ingress"
 	IngressForAKSClusterID = "ingress-for-aks-cluster-id"
+
+	// ConfirmMassDeletion is set by an operator to the token of a deployment blocked by the deletion guard to confirm it; AGIC removes it on every update.
+	ConfirmMassDeletion = "agic-confirm-mass-deletion"
+
+	// OwnershipRecord is the prefix of the tags holding the names of the sub-resources AGIC created, split in numbered chunks.
+	OwnershipRecord = "agic-ownership-record"
 )
\ No newline at end of file
@@ -7,12 +7,38 @@ package brownfield
 
 import (
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
 type certName string
 type certsByName map[certName]n.ApplicationGatewaySslCertificate
 
+// GetBlacklistedCertificates splits the existing certificates into the ones used by blacklisted listeners and the rest.
+func (er ExistingResources) GetBlacklistedCertificates() ([]n.ApplicationGatewaySslCertificate, []n.ApplicationGatewaySslCertificate) {
+	blacklistedCertSet := make(map[certName]interface{})
+	blacklistedListeners, _ := er.GetBlacklistedListeners()
+	for _, listener := range blacklistedListeners {
+		if listener.ApplicationGatewayHTTPListenerPropertiesFormat == nil || listener.SslCertificate == nil || listener.SslCertificate.ID == nil {
+			continue
+		}
+		blacklistedCertSet[certName(utils.GetLastChunkOfSlashed(*listener.SslCertificate.ID))] = nil
+	}
+
+	var blacklistedCerts []n.ApplicationGatewaySslCertificate
+	var nonBlacklistedCerts []n.ApplicationGatewaySslCertificate
+	for _, cert := range er.Certificates {
+		if _, exists := blacklistedCertSet[certName(*cert.Name)]; exists {
+			blacklistedCerts = append(blacklistedCerts, cert)
+			continue
+		}
+		nonBlacklistedCerts = append(nonBlacklistedCerts, cert)
+	}
+	return blacklistedCerts, nonBlacklistedCerts
+}
+
 // MergeCerts merges list of lists of certs into a single list, maintaining uniqueness.
+// When certs in different buckets share a name, the one from the later bucket is kept.
 func MergeCerts(certBuckets ...[]n.ApplicationGatewaySslCertificate) []n.ApplicationGatewaySslCertificate {
 	uniq := make(certsByName)
 	for _, bucket := range certBuckets {
@@ -26,3 +52,16 @@ func MergeCerts(certBuckets ...[]n.ApplicationGatewaySslCertificate) []n.Applica
 	}
 	return merged
 }
+
+// AddCertificates classifies the existing and AGIC created certificates in the ownership report.
+func (r *OwnershipReport) AddCertificates(existingBlacklisted []n.ApplicationGatewaySslCertificate, existingNonBlacklisted []n.ApplicationGatewaySslCertificate, managedCerts []n.ApplicationGatewaySslCertificate) {
+	r.add(KindSslCertificate, certNames(existingBlacklisted), certNames(existingNonBlacklisted), certNames(managedCerts))
+}
+
+func certNames(certs []n.ApplicationGatewaySslCertificate) []string {
+	var names []string
+	for _, cert := range certs {
+		names = append(names, *cert.Name)
+	}
+	return names
+}
@@ -6,11 +6,15 @@
 package brownfield
 
 import (
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 )
 
 var _ = Describe("Test MergeCerts", func() {
@@ -29,5 +33,39 @@ var _ = Describe("Test MergeCerts", func() {
 			Expect(actual).To(ContainElement(fixtures.GetCertificate2()))
 			Expect(actual).To(ContainElement(fixtures.GetCertificate3()))
 		})
+
+		It("should keep the cert from the later bucket when names collide", func() {
+			managed := fixtures.GetCertificate1()
+			managed.ApplicationGatewaySslCertificatePropertiesFormat = &n.ApplicationGatewaySslCertificatePropertiesFormat{
+				Data: to.StringPtr("managed"),
+			}
+			existing := fixtures.GetCertificate1()
+			existing.ApplicationGatewaySslCertificatePropertiesFormat = &n.ApplicationGatewaySslCertificatePropertiesFormat{
+				PublicCertData: to.StringPtr("existing"),
+			}
+			actual := MergeCerts([]n.ApplicationGatewaySslCertificate{managed}, []n.ApplicationGatewaySslCertificate{existing})
+			Expect(actual).To(ConsistOf(existing))
+		})
+	})
+
+	Context("Test GetBlacklistedCertificates()", func() {
+		It("should blacklist the certificates of blacklisted listeners", func() {
+			appGw := fixtures.GetAppGateway()
+			listener := fixtures.GetListenerPathBased1()
+			listener.SslCertificate = &n.SubResource{ID: to.StringPtr("x/y/z/" + fixtures.CertificateName1)}
+			appGw.HTTPListeners = &[]n.ApplicationGatewayHTTPListener{*listener}
+			appGw.SslCertificates = &[]n.ApplicationGatewaySslCertificate{
+				fixtures.GetCertificate1(),
+				fixtures.GetCertificate2(),
+			}
+			prohibitedTargets := []*ptv1.AzureIngressProhibitedTarget{
+				{Spec: ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host}},
+			}
+
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
+			blacklisted, nonBlacklisted := er.GetBlacklistedCertificates()
+			Expect(blacklisted).To(ConsistOf(fixtures.GetCertificate1()))
+			Expect(nonBlacklisted).To(ConsistOf(fixtures.GetCertificate2()))
+		})
 	})
 })
@@ -9,4 +9,10 @@ import "errors"
 
 var (
 	ErrListenerLookup = errors.New("failed looking up listener")
+	ErrInvalidPath    = errors.New("path must begin with / and end with /*")
+	ErrInvalidIP      = errors.New("ip must be public, private or an IP address")
+	ErrInvalidPort    = errors.New("port must be between 1 and 65535")
+
+	ErrOwnershipRecordCorrupt  = errors.New("ownership record tags of the App Gateway could not be decoded")
+	ErrOwnershipRecordTooLarge = errors.New("ownership record does not fit in the tags of the App Gateway")
 )
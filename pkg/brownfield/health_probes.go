@@ -6,8 +6,6 @@
 package brownfield
 
 import (
-	"strings"
-
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/golang/glog"
 
@@ -49,24 +47,9 @@ func MergeProbes(probesBuckets ...[]n.ApplicationGatewayProbe) []n.ApplicationGa
 	return managedProbes
 }
 
-// LogProbes emits a few log lines detailing what probes are created, blacklisted, and removed from ARM.
-func LogProbes(logger Logger, existingBlacklisted []n.ApplicationGatewayProbe, existingNonBlacklisted []n.ApplicationGatewayProbe, managedProbes []n.ApplicationGatewayProbe) {
-	var garbage []n.ApplicationGatewayProbe
-
-	blacklistedSet := indexProbesByName(existingBlacklisted)
-	managedSet := indexProbesByName(managedProbes)
-
-	for probeName, probe := range indexProbesByName(existingNonBlacklisted) {
-		_, existsInBlacklist := blacklistedSet[probeName]
-		_, existsInNewProbes := managedSet[probeName]
-		if !existsInBlacklist && !existsInNewProbes {
-			garbage = append(garbage, probe)
-		}
-	}
-
-	logger.Info("[brownfield] Probes AGIC created: ", getProbeNames(managedProbes))
-	logger.Info("[brownfield] Existing Blacklisted Probes AGIC will retain: ", getProbeNames(existingBlacklisted))
-	logger.Info("[brownfield] Existing Probes AGIC will remove: ", getProbeNames(garbage))
+// AddProbes classifies the existing and AGIC created probes in the ownership report.
+func (r *OwnershipReport) AddProbes(existingBlacklisted []n.ApplicationGatewayProbe, existingNonBlacklisted []n.ApplicationGatewayProbe, managedProbes []n.ApplicationGatewayProbe) {
+	r.add(KindProbe, probeNames(existingBlacklisted), probeNames(existingNonBlacklisted), probeNames(managedProbes))
 }
 
 func indexProbesByName(probes []n.ApplicationGatewayProbe) probesByName {
@@ -77,12 +60,12 @@ func indexProbesByName(probes []n.ApplicationGatewayProbe) probesByName {
 	return probesByName
 }
 
-func getProbeNames(Probe []n.ApplicationGatewayProbe) string {
+func probeNames(probes []n.ApplicationGatewayProbe) []string {
 	var names []string
-	for _, p := range Probe {
-		names = append(names, *p.Name)
+	for _, probe := range probes {
+		names = append(names, *probe.Name)
 	}
-	return strings.Join(names, ", ")
+	return names
 }
 
 func (er ExistingResources) getBlacklistedProbesSet() map[probeName]interface{} {
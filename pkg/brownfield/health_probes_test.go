@@ -29,7 +29,7 @@ var _ = Describe("Test blacklist health probes", func() {
 
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets() // /fox  /bar
 
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 
 			blacklisted, nonBlacklisted := er.GetBlacklistedProbes()
 
@@ -52,7 +52,7 @@ var _ = Describe("Test blacklist health probes", func() {
 			}
 			prohibitedTargets := append(fixtures.GetAzureIngressProhibitedTargets(), wildcard)
 
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 
 			// Everything is blacklisted
 			blacklisted, nonBlacklisted := er.GetBlacklistedProbes()
@@ -96,7 +96,7 @@ var _ = Describe("Test blacklist health probes", func() {
 	Context("Test getBlacklistedProbesSet()", func() {
 		It("should create a set of blacklisted probes", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets()
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			set := er.getBlacklistedProbesSet()
 			Expect(len(set)).To(Equal(2))
 			_, exists := set[fixtures.ProbeName1]
@@ -106,20 +106,25 @@ var _ = Describe("Test blacklist health probes", func() {
 		})
 	})
 
-	Context("Test LogHTTPSettings()", func() {
-		It("should log settings", func() {
+	Context("Test AddProbes()", func() {
+		It("should classify and log probes", func() {
 			probes := []n.ApplicationGatewayProbe{
 				fixtures.GetApplicationGatewayProbe(to.StringPtr("x"), to.StringPtr("y")),
 			}
 			logger := &mocks.MockLogger{}
 
-			LogProbes(logger, probes, probes, probes)
+			report := NewOwnershipReport(n.ApplicationGateway{}, nil, nil)
+			report.AddProbes(probes, nil, probes)
+			report.Complete(logger)
 
-			expected1 := "[brownfield] Probes AGIC created: _probe-name-eA-eQ"
-			Expect(logger.LogLines).To(ContainElement(expected1))
+			Expect(report.Resources).To(Equal([]ResourceOwnership{{
+				Kind:      KindProbe,
+				Name:      "probe-name-eA-eQ",
+				Ownership: OwnershipProhibited,
+			}}))
 
-			expected2 := "[brownfield] Existing Blacklisted Probes AGIC will retain: _probe-name-eA-eQ"
-			Expect(logger.LogLines).To(ContainElement(expected2))
+			expected := "[brownfield] probe: managed []; prohibited [probe-name-eA-eQ]; orphaned []"
+			Expect(logger.LogLines).To(ContainElement(expected))
 		})
 	})
 })
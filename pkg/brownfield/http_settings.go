@@ -6,8 +6,6 @@
 package brownfield
 
 import (
-	"strings"
-
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/golang/glog"
 
@@ -50,24 +48,9 @@ func MergeHTTPSettings(settingBuckets ...[]n.ApplicationGatewayBackendHTTPSettin
 	return merged
 }
 
-// LogHTTPSettings emits a few log lines detailing what settings are created, blacklisted, and removed from ARM.
-func LogHTTPSettings(logger Logger, existingBlacklisted []n.ApplicationGatewayBackendHTTPSettings, existingNonBlacklisted []n.ApplicationGatewayBackendHTTPSettings, managedSettings []n.ApplicationGatewayBackendHTTPSettings) {
-	var garbage []n.ApplicationGatewayBackendHTTPSettings
-
-	blacklistedSet := indexSettingsByName(existingBlacklisted)
-	managedSet := indexSettingsByName(managedSettings)
-
-	for settingName, setting := range indexSettingsByName(existingNonBlacklisted) {
-		_, existsInBlacklist := blacklistedSet[settingName]
-		_, existsInNewSettings := managedSet[settingName]
-		if !existsInBlacklist && !existsInNewSettings {
-			garbage = append(garbage, setting)
-		}
-	}
-
-	logger.Info("[brownfield] HTTP Settings AGIC created: ", getSettingNames(managedSettings))
-	logger.Info("[brownfield] Existing Blacklisted HTTP Settings AGIC will retain: ", getSettingNames(existingBlacklisted))
-	logger.Info("[brownfield] Existing HTTP Settings AGIC will remove: ", getSettingNames(garbage))
+// AddHTTPSettings classifies the existing and AGIC created HTTP settings in the ownership report.
+func (r *OwnershipReport) AddHTTPSettings(existingBlacklisted []n.ApplicationGatewayBackendHTTPSettings, existingNonBlacklisted []n.ApplicationGatewayBackendHTTPSettings, managedSettings []n.ApplicationGatewayBackendHTTPSettings) {
+	r.add(KindBackendHTTPSettings, settingNames(existingBlacklisted), settingNames(existingNonBlacklisted), settingNames(managedSettings))
 }
 
 func indexSettingsByName(settings []n.ApplicationGatewayBackendHTTPSettings) settingsByName {
@@ -78,12 +61,12 @@ func indexSettingsByName(settings []n.ApplicationGatewayBackendHTTPSettings) set
 	return settingsByName
 }
 
-func getSettingNames(settings []n.ApplicationGatewayBackendHTTPSettings) string {
+func settingNames(settings []n.ApplicationGatewayBackendHTTPSettings) []string {
 	var names []string
 	for _, setting := range settings {
 		names = append(names, *setting.Name)
 	}
-	return strings.Join(names, ", ")
+	return names
 }
 
 func (er ExistingResources) getBlacklistedSettingsSet() map[settingName]interface{} {
@@ -26,7 +26,7 @@ var _ = Describe("Test blacklisting HTTP settings", func() {
 	Context("Test GetBlacklistedHTTPSettings() with a blacklist", func() {
 		It("should create a list of blacklisted and non blacklisted settings", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets() // Host: "bye.com", Paths: [/fox, /bar]
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 
 			blacklisted, nonBlacklisted := er.GetBlacklistedHTTPSettings()
 			Expect(len(blacklisted)).To(Equal(2))
@@ -46,7 +46,7 @@ var _ = Describe("Test blacklisting HTTP settings", func() {
 			}
 			prohibitedTargets := append(fixtures.GetAzureIngressProhibitedTargets(), wildcard)
 
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			blacklisted, nonBlacklisted := er.GetBlacklistedHTTPSettings()
 			Expect(len(blacklisted)).To(Equal(2))
 
@@ -58,7 +58,7 @@ var _ = Describe("Test blacklisting HTTP settings", func() {
 	Context("Test getBlacklistedSettingsSet()", func() {
 		It("should create a set of blacklisted settings", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets()
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			set := er.getBlacklistedSettingsSet()
 			Expect(len(set)).To(Equal(2))
 			_, exists := set[fixtures.BackendHTTPSettingsName1]
@@ -85,26 +85,31 @@ var _ = Describe("Test blacklisting HTTP settings", func() {
 		})
 	})
 
-	Context("Test LogHTTPSettings()", func() {
-		It("should log settings", func() {
+	Context("Test AddHTTPSettings()", func() {
+		It("should classify and log settings", func() {
 			sett1 := []n.ApplicationGatewayBackendHTTPSettings{
 				fixtures.GetHTTPSettings1(),
-				fixtures.GetHTTPSettings2(),
 			}
 			sett2 := []n.ApplicationGatewayBackendHTTPSettings{
-				fixtures.GetHTTPSettings1(),
+				fixtures.GetHTTPSettings2(),
+			}
+			sett3 := []n.ApplicationGatewayBackendHTTPSettings{
 				fixtures.GetHTTPSettings3(),
 			}
 			logger := &mocks.MockLogger{}
 
-			LogHTTPSettings(logger, sett1, sett2, sett2)
+			report := NewOwnershipReport(n.ApplicationGateway{}, nil, nil)
+			report.AddHTTPSettings(sett1, sett2, sett3)
+			report.Complete(logger)
 
-			expected1 := "[brownfield] Existing Blacklisted HTTP Settings AGIC will retain:" +
-				" _BackendHTTPSettings-1, BackendHTTPSettings-2"
-			Expect(logger.LogLines).To(ContainElement(expected1))
+			Expect(report.Resources).To(HaveLen(3))
+			Expect(report.Resources[0].Ownership).To(Equal(OwnershipProhibited))
+			Expect(report.Resources[1].Ownership).To(Equal(OwnershipOrphaned))
+			Expect(report.Resources[2].Ownership).To(Equal(OwnershipManaged))
 
-			expected2 := "[brownfield] HTTP Settings AGIC created: _BackendHTTPSettings-1, BackendHTTPSettings-3"
-			Expect(logger.LogLines).To(ContainElement(expected2))
+			expected := "[brownfield] backendHttpSettings: managed [BackendHTTPSettings-3]; " +
+				"prohibited [BackendHTTPSettings-1]; orphaned [BackendHTTPSettings-2]"
+			Expect(logger.LogLines).To(ContainElement(expected))
 		})
 	})
 })
@@ -0,0 +1,161 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// invalidNameChars matches the runs of characters not allowed in the name of a Kubernetes resource.
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// GetProhibitedTargetsForExistingConfig creates the prohibited targets, which cover all listeners, routing rules and
+// path maps of the given App Gateway. Applying these protects the existing config from AGIC.
+// There is one prohibited target per listener; The paths of a path based routing rule are listed explicitly,
+// while a listener with a basic routing rule (or no rule at all) is prohibited for all paths. So is a listener whose
+// URL path map has a default backend, redirect or rewrite, which serves all other paths.
+// Listeners without a hostname get a prohibited target of their frontend IP and port, which keeps AGIC from
+// configuring any hostname on that frontend IP and port. Those whose frontend IP or port is unknown are skipped and
+// their names returned: a prohibited target without hostname, IP and port would keep AGIC from the App Gateway.
+func GetProhibitedTargetsForExistingConfig(appGw n.ApplicationGateway) ([]*ptv1.AzureIngressProhibitedTarget, []string) {
+	er := NewExistingResources(appGw, nil, nil, nil)
+
+	// Collect the paths of the rules attached to each listener; A nil slice stands for all paths.
+	listenerPaths := make(map[listenerName][]string)
+	allPaths := make(map[listenerName]bool)
+	for _, rule := range er.RoutingRules {
+		if rule.HTTPListener == nil || rule.HTTPListener.ID == nil {
+			continue
+		}
+		listenerName := listenerName(utils.GetLastChunkOfSlashed(*rule.HTTPListener.ID))
+		if rule.URLPathMap == nil || rule.URLPathMap.ID == nil {
+			allPaths[listenerName] = true
+			continue
+		}
+		pathMapName, pathRules := er.getPathRules(rule)
+		if pathMap, exists := er.getURLPathMapsByName()[pathMapName]; exists && hasDefaultTarget(pathMap) {
+			allPaths[listenerName] = true
+			continue
+		}
+		for _, pathRule := range pathRules {
+			if pathRule.ApplicationGatewayPathRulePropertiesFormat == nil || pathRule.Paths == nil {
+				continue
+			}
+			for _, path := range *pathRule.Paths {
+				listenerPaths[listenerName] = append(listenerPaths[listenerName], toProhibitedPath(path))
+			}
+		}
+	}
+
+	var prohibitedTargets []*ptv1.AzureIngressProhibitedTarget
+	var skippedListeners []string
+	names := make(map[string]interface{})
+	for _, listener := range er.Listeners {
+		if listener.Name == nil {
+			continue
+		}
+		target := er.GetListenerTarget(listener)
+		if target.Hostname == "" && (target.IP == "" || target.Port == 0) {
+			skippedListeners = append(skippedListeners, *listener.Name)
+			continue
+		}
+		var paths []string
+		if !allPaths[listenerName(*listener.Name)] {
+			paths = uniqueSorted(listenerPaths[listenerName(*listener.Name)])
+		}
+		prohibitedTargets = append(prohibitedTargets, &ptv1.AzureIngressProhibitedTarget{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: ptv1.SchemeGroupVersion.String(),
+				Kind:       "AzureIngressProhibitedTarget",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: uniqueName(getProhibitedTargetName(target), names),
+			},
+			Spec: ptv1.AzureIngressProhibitedTargetSpec{
+				Hostname: target.Hostname,
+				IP:       target.IP,
+				Port:     target.Port,
+				Paths:    paths,
+			},
+		})
+	}
+	return prohibitedTargets, skippedListeners
+}
+
+// hasDefaultTarget tells whether the URL path map serves the paths none of its path rules match.
+func hasDefaultTarget(pathMap n.ApplicationGatewayURLPathMap) bool {
+	if pathMap.ApplicationGatewayURLPathMapPropertiesFormat == nil {
+		return false
+	}
+	return pathMap.DefaultBackendAddressPool != nil || pathMap.DefaultRedirectConfiguration != nil || pathMap.DefaultRewriteRuleSet != nil
+}
+
+// toProhibitedPath turns a path of a path rule into a path accepted by the prohibited target CRD, which covers the
+// original path. An exact path "/a/b" becomes "/a/b/*", which matches "/a/b" as well. A wildcard "/a/b*" matches
+// "/a/bc" too, so it is widened to the wildcard of its parent, "/a/*".
+func toProhibitedPath(path string) string {
+	if validPath.MatchString(path) {
+		return path
+	}
+	if !strings.HasSuffix(path, "*") {
+		return strings.TrimRight(path, "/") + "/*"
+	}
+	prefix := strings.TrimSuffix(path, "*")
+	parent := prefix[:strings.LastIndex(prefix, "/")+1]
+	if !strings.HasPrefix(parent, "/") {
+		return "/*"
+	}
+	return parent + "*"
+}
+
+// getProhibitedTargetName derives a resource name from the hostname, frontend IP and port of a target.
+func getProhibitedTargetName(target Target) string {
+	parts := []string{target.Hostname}
+	if target.IP != "" {
+		parts = append(parts, target.IP)
+	}
+	if target.Port != 0 {
+		parts = append(parts, fmt.Sprint(target.Port))
+	}
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(strings.Join(parts, "-")), "-"), "-")
+	if name == "" {
+		return "prohibited-target"
+	}
+	return name
+}
+
+func uniqueName(name string, taken map[string]interface{}) string {
+	unique := name
+	for idx := 2; ; idx++ {
+		if _, exists := taken[unique]; !exists {
+			taken[unique] = nil
+			return unique
+		}
+		unique = fmt.Sprintf("%s-%d", name, idx)
+	}
+}
+
+func uniqueSorted(items []string) []string {
+	var unique []string
+	seen := make(map[string]interface{})
+	for _, item := range items {
+		if _, exists := seen[item]; !exists {
+			seen[item] = nil
+			unique = append(unique, item)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
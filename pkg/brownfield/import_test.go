@@ -0,0 +1,141 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("Test brownfield import", func() {
+
+	Context("Test GetProhibitedTargetsForExistingConfig()", func() {
+		appGw := fixtures.GetAppGateway()
+		prohibitedTargets, skippedListeners := GetProhibitedTargetsForExistingConfig(appGw)
+
+		byName := make(map[string]ptv1.AzureIngressProhibitedTargetSpec)
+		for _, prohibitedTarget := range prohibitedTargets {
+			byName[prohibitedTarget.Name] = prohibitedTarget.Spec
+		}
+
+		It("should create one prohibited target per listener with a hostname or a known frontend", func() {
+			for _, prohibitedTarget := range prohibitedTargets {
+				Expect(prohibitedTarget.Kind).To(Equal("AzureIngressProhibitedTarget"))
+				Expect(prohibitedTarget.APIVersion).To(Equal("appgw.ingress.k8s.io/v1"))
+			}
+			Expect(byName).To(HaveLen(4))
+			Expect(skippedListeners).To(ConsistOf(fixtures.DefaultHTTPListenerName))
+		})
+
+		It("should prohibit the frontend IP and port of listeners without a hostname", func() {
+			catchAllAppGw := fixtures.GetAppGateway()
+			catchAllAppGw.FrontendPorts = &[]n.ApplicationGatewayFrontendPort{
+				{
+					Name: to.StringPtr(fixtures.DefaultPortName),
+					ApplicationGatewayFrontendPortPropertiesFormat: &n.ApplicationGatewayFrontendPortPropertiesFormat{
+						Port: to.Int32Ptr(80),
+					},
+				},
+			}
+			for idx := range *catchAllAppGw.HTTPListeners {
+				listener := &(*catchAllAppGw.HTTPListeners)[idx]
+				if *listener.Name == fixtures.DefaultHTTPListenerName {
+					listener.FrontendIPConfiguration = &n.SubResource{ID: to.StringPtr(fixtures.PublicIPName)}
+				}
+			}
+
+			catchAllTargets, skipped := GetProhibitedTargetsForExistingConfig(catchAllAppGw)
+			Expect(skipped).To(BeEmpty())
+			Expect(catchAllTargets).To(HaveLen(5))
+			var catchAll []ptv1.AzureIngressProhibitedTargetSpec
+			for _, prohibitedTarget := range catchAllTargets {
+				if prohibitedTarget.Spec.Hostname == "" {
+					Expect(prohibitedTarget.Name).To(Equal("public-80"))
+					catchAll = append(catchAll, prohibitedTarget.Spec)
+				}
+			}
+			Expect(catchAll).To(Equal([]ptv1.AzureIngressProhibitedTargetSpec{{IP: FrontendIPPublic, Port: 80}}))
+
+			er := NewExistingResources(catchAllAppGw, catchAllTargets, nil, nil)
+			_, nonBlacklistedListeners := er.GetBlacklistedListeners()
+			Expect(nonBlacklistedListeners).To(BeEmpty())
+		})
+
+		It("should list the paths of path based rules", func() {
+			noDefaultsAppGw := fixtures.GetAppGateway()
+			for idx := range *noDefaultsAppGw.URLPathMaps {
+				pathMap := &(*noDefaultsAppGw.URLPathMaps)[idx]
+				pathMap.DefaultBackendAddressPool = nil
+				pathMap.DefaultBackendHTTPSettings = nil
+				pathMap.DefaultRedirectConfiguration = nil
+				pathMap.DefaultRewriteRuleSet = nil
+			}
+			noDefaultsTargets, _ := GetProhibitedTargetsForExistingConfig(noDefaultsAppGw)
+			noDefaultsByName := make(map[string]ptv1.AzureIngressProhibitedTargetSpec)
+			for _, prohibitedTarget := range noDefaultsTargets {
+				noDefaultsByName[prohibitedTarget.Name] = prohibitedTarget.Spec
+			}
+			Expect(noDefaultsByName["bye-com"].Hostname).To(Equal(tests.Host))
+			Expect(noDefaultsByName["bye-com"].Paths).To(Equal([]string{"/bar/*", "/baz/*", "/foo/*"}))
+			Expect(noDefaultsByName["some-other-hostname-2"].Paths).To(Equal([]string{"/fox/*"}))
+		})
+
+		It("should prohibit all paths of listeners whose path map has a default backend or redirect", func() {
+			Expect(byName["bye-com"].Hostname).To(Equal(tests.Host))
+			Expect(byName["bye-com"].Paths).To(BeNil())
+			Expect(byName["some-other-hostname-2"].Paths).To(BeNil())
+		})
+
+		It("should prohibit all paths of listeners with a basic rule or without rules", func() {
+			Expect(byName["some-other-hostname"].Hostname).To(Equal(tests.OtherHost))
+			Expect(byName["some-other-hostname"].Paths).To(BeNil())
+			Expect(byName["some-host-without-routing-rules"].Paths).To(BeNil())
+		})
+
+		It("should create prohibited targets, which protect all existing listeners and rules with a hostname", func() {
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
+			_, nonBlacklistedRules := er.GetBlacklistedRoutingRules()
+			Expect(nonBlacklistedRules).To(HaveLen(1))
+			Expect(*nonBlacklistedRules[0].HTTPListener.ID).To(HaveSuffix(fixtures.DefaultHTTPListenerName))
+			blacklistedListeners, _ := er.GetBlacklistedListeners()
+			Expect(blacklistedListeners).To(HaveLen(4))
+		})
+	})
+
+	Context("Test toProhibitedPath()", func() {
+		It("should keep wildcard paths", func() {
+			Expect(toProhibitedPath("/a/*")).To(Equal("/a/*"))
+			Expect(toProhibitedPath("/*")).To(Equal("/*"))
+		})
+
+		It("should widen exact paths to the wildcard of the same prefix", func() {
+			Expect(toProhibitedPath("/a/b")).To(Equal("/a/b/*"))
+			Expect(toProhibitedPath("/a/b/")).To(Equal("/a/b/*"))
+			Expect(toProhibitedPath("/")).To(Equal("/*"))
+			Expect(TargetPath(toProhibitedPath("/a/b")).contains(TargetPath("/a/b"))).To(BeTrue())
+		})
+
+		It("should widen other wildcards to the wildcard of their parent", func() {
+			Expect(toProhibitedPath("/a*")).To(Equal("/*"))
+			Expect(toProhibitedPath("/a/b*")).To(Equal("/a/*"))
+			Expect(toProhibitedPath("a*")).To(Equal("/*"))
+			Expect(TargetPath(toProhibitedPath("/a*")).contains(TargetPath("/abc"))).To(BeTrue())
+			Expect(TargetPath(toProhibitedPath("/a/b*")).contains(TargetPath("/a/bc/*"))).To(BeTrue())
+		})
+	})
+
+	Context("Test getProhibitedTargetName()", func() {
+		It("should create a valid resource name", func() {
+			Expect(getProhibitedTargetName(Target{Hostname: "www.Contoso.com", IP: FrontendIPPublic, Port: 443})).To(Equal("www-contoso-com-public-443"))
+		})
+	})
+})
@@ -6,35 +6,60 @@
 package brownfield
 
 import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"k8s.io/api/extensions/v1beta1"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	mtv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
 	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 )
 
+// PrunedPath is an Ingress target, which was removed because it overlaps with a prohibited target,
+// or because it is not within the managed targets.
+type PrunedPath struct {
+	Target       Target
+	ProhibitedBy Target
+	NotManaged   bool
+}
+
+// IsPartialOverlap returns true when only some of the URL paths of the pruned target are prohibited.
+// App Gateway cannot express the difference of two paths, so the whole target is dropped.
+func (pp PrunedPath) IsPartialOverlap() bool {
+	return !pp.NotManaged && !pp.ProhibitedBy.Path.contains(pp.Target.Path)
+}
+
 // PruneIngressRules transforms the given ingress struct to remove targets, which AGIC should not create configuration for.
-func PruneIngressRules(ing *v1beta1.Ingress, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget) []v1beta1.IngressRule {
+// The frontend IP configurations of the App Gateway and whether the Ingress uses the private IP determine which
+// frontends the Ingress would be exposed on; useDefaultCertificate tells whether the default certificate is available
+// to the Ingress. When there are managed targets, only the paths within them are kept.
+// The removed targets are returned along with the remaining rules.
+func PruneIngressRules(ing *v1beta1.Ingress, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget, managedTargets []*mtv1.AzureIngressManagedTarget, frontendIPs []n.ApplicationGatewayFrontendIPConfiguration, usePrivateIP bool, useDefaultCertificate bool) ([]v1beta1.IngressRule, []PrunedPath) {
 
 	if ing.Spec.Rules == nil || len(ing.Spec.Rules) == 0 {
-		return ing.Spec.Rules
+		return ing.Spec.Rules, nil
 	}
 
-	blacklist := GetTargetBlacklist(prohibitedTargets)
+	blacklist := NormalizeTargetBlacklist(GetTargetBlacklist(prohibitedTargets), frontendIPs)
+	var whitelist TargetWhitelist
+	if len(managedTargets) > 0 {
+		whitelist = NormalizeTargetWhitelist(GetTargetWhitelist(managedTargets), frontendIPs)
+	}
 
-	if blacklist == nil || len(*blacklist) == 0 {
-		return ing.Spec.Rules
+	if (blacklist == nil || len(*blacklist) == 0) && whitelist == nil {
+		return ing.Spec.Rules, nil
 	}
 
 	var rules []v1beta1.IngressRule
+	var pruned []PrunedPath
 
 	for _, rule := range ing.Spec.Rules {
 		if rule.HTTP == nil {
 			continue
 		}
-		target := Target{
-			Hostname: rule.Host,
-		}
+		targets := getIngressRuleTargets(ing, rule.Host, usePrivateIP, useDefaultCertificate)
 		if rule.HTTP.Paths == nil {
-			if target.IsBlacklisted(blacklist) {
+			if prunedPath, isBlacklisted := getBlacklisting(targets, "", blacklist, whitelist); isBlacklisted {
+				pruned = append(pruned, prunedPath)
 				continue
 			}
 			rules = append(rules, rule)
@@ -50,8 +75,8 @@ func PruneIngressRules(ing *v1beta1.Ingress, prohibitedTargets []*ptv1.AzureIngr
 			},
 		}
 		for _, path := range rule.HTTP.Paths {
-			target.Path = TargetPath(path.Path)
-			if target.IsBlacklisted(blacklist) {
+			if prunedPath, isBlacklisted := getBlacklisting(targets, TargetPath(path.Path), blacklist, whitelist); isBlacklisted {
+				pruned = append(pruned, prunedPath)
 				continue
 			}
 			newRule.HTTP.Paths = append(newRule.HTTP.Paths, path)
@@ -61,5 +86,63 @@ func PruneIngressRules(ing *v1beta1.Ingress, prohibitedTargets []*ptv1.AzureIngr
 		}
 	}
 
-	return rules
+	return rules, pruned
+}
+
+// getIngressRuleTargets creates the Targets (without a path) for the listeners AGIC would create for the given
+// hostname of the Ingress: HTTPS on 443 when there is a TLS secret, or the default certificate serves the hostname, and
+// HTTP on 80 without TLS or with ssl-redirect. As in the config builder, the default certificate serves hostnames of
+// Ingresses without TLS sections, and hostnames listed by a TLS section without a secret.
+func getIngressRuleTargets(ing *v1beta1.Ingress, hostname string, usePrivateIP bool, useDefaultCertificate bool) []Target {
+	ip := FrontendIPPublic
+	if usePrivateIP {
+		ip = FrontendIPPrivate
+	}
+
+	hasSecret := false
+	withoutSecret := len(ing.Spec.TLS) == 0
+	for _, tls := range ing.Spec.TLS {
+		listed := len(tls.Hosts) == 0
+		for _, host := range tls.Hosts {
+			listed = listed || host == hostname
+		}
+		if !listed {
+			continue
+		}
+		if tls.SecretName != "" {
+			hasSecret = true
+		} else {
+			withoutSecret = true
+		}
+	}
+	hasTLS := hasSecret || (useDefaultCertificate && withoutSecret)
+	sslRedirect, _ := annotations.IsSslRedirect(ing)
+
+	var targets []Target
+	if hasTLS {
+		targets = append(targets, Target{Hostname: hostname, IP: ip, Port: 443})
+	}
+	if sslRedirect || !hasTLS {
+		targets = append(targets, Target{Hostname: hostname, IP: ip, Port: 80})
+	}
+	return targets
+}
+
+// getBlacklisting figures out whether the given path is blacklisted, or not whitelisted, on any of the given Targets.
+func getBlacklisting(targets []Target, path TargetPath, blacklist TargetBlacklist, whitelist TargetWhitelist) (PrunedPath, bool) {
+	for _, target := range targets {
+		target.Path = path
+		if blTarget, isBlacklisted := target.getBlacklisting(blacklist); isBlacklisted {
+			return PrunedPath{Target: target, ProhibitedBy: blTarget}, true
+		}
+		// An Ingress rule without paths would be applied to all paths of its host.
+		allPaths := target
+		if allPaths.Path == "" {
+			allPaths.Path = "/*"
+		}
+		if whitelist != nil && !allPaths.IsWhitelisted(whitelist) {
+			return PrunedPath{Target: target, NotManaged: true}, true
+		}
+	}
+	return PrunedPath{}, false
 }
@@ -11,6 +11,9 @@ import (
 	"k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	mtv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
 )
@@ -64,7 +67,7 @@ var _ = Describe("test pruning Ingress based on white/white lists", func() {
 			},
 		}
 
-		actualRules := PruneIngressRules(&ingress, prohibited)
+		actualRules, _ := PruneIngressRules(&ingress, prohibited, nil, nil, false, false)
 
 		expected := v1beta1.Ingress{
 			Spec: v1beta1.IngressSpec{
@@ -98,4 +101,154 @@ var _ = Describe("test pruning Ingress based on white/white lists", func() {
 		})
 	})
 
+	Context("Test PruneIngressRules() with a prohibited frontend IP and port", func() {
+		newIngress := func() *v1beta1.Ingress {
+			return &v1beta1.Ingress{
+				Spec: v1beta1.IngressSpec{
+					TLS: []v1beta1.IngressTLS{
+						{
+							Hosts:      []string{tests.Host},
+							SecretName: "secret",
+						},
+					},
+					Rules: []v1beta1.IngressRule{
+						{
+							Host: tests.Host,
+							IngressRuleValue: v1beta1.IngressRuleValue{
+								HTTP: &v1beta1.HTTPIngressRuleValue{
+									Paths: []v1beta1.HTTPIngressPath{
+										{
+											Path: fixtures.PathFoo,
+											Backend: v1beta1.IngressBackend{
+												ServiceName: tests.ServiceName,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("should keep the rules exposed on a different IP", func() {
+			prohibited := []*ptv1.AzureIngressProhibitedTarget{
+				{Spec: ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host, IP: "private"}},
+			}
+			ingress := newIngress()
+			rules, _ := PruneIngressRules(ingress, prohibited, nil, nil, false, false)
+			Expect(rules).To(Equal(ingress.Spec.Rules))
+			rules, _ = PruneIngressRules(ingress, prohibited, nil, nil, true, false)
+			Expect(rules).To(BeEmpty())
+		})
+
+		It("should prune the rules exposed on a prohibited port", func() {
+			prohibited := []*ptv1.AzureIngressProhibitedTarget{
+				{Spec: ptv1.AzureIngressProhibitedTargetSpec{Port: 80}},
+			}
+			ingress := newIngress()
+			// HTTPS only; Nothing on port 80
+			rules, _ := PruneIngressRules(ingress, prohibited, nil, nil, false, false)
+			Expect(rules).To(Equal(ingress.Spec.Rules))
+
+			// With ssl-redirect AGIC would also create a listener on port 80
+			ingress.Annotations = map[string]string{annotations.SslRedirectKey: "true"}
+			rules, _ = PruneIngressRules(ingress, prohibited, nil, nil, false, false)
+			Expect(rules).To(BeEmpty())
+		})
+
+		It("should prune the rules served with the default certificate on a prohibited port", func() {
+			prohibited := []*ptv1.AzureIngressProhibitedTarget{
+				{Spec: ptv1.AzureIngressProhibitedTargetSpec{Port: 443}},
+			}
+			ingress := newIngress()
+			ingress.Spec.TLS[0].SecretName = ""
+			// Without the default certificate AGIC would create a listener on port 80 only
+			rules, _ := PruneIngressRules(ingress, prohibited, nil, nil, false, false)
+			Expect(rules).To(Equal(ingress.Spec.Rules))
+
+			rules, _ = PruneIngressRules(ingress, prohibited, nil, nil, false, true)
+			Expect(rules).To(BeEmpty())
+		})
+	})
+
+	Context("Test PruneIngressRules() with overlapping paths", func() {
+		ingress := &v1beta1.Ingress{
+			Spec: v1beta1.IngressSpec{
+				Rules: []v1beta1.IngressRule{
+					{
+						Host: tests.Host,
+						IngressRuleValue: v1beta1.IngressRuleValue{
+							HTTP: &v1beta1.HTTPIngressRuleValue{
+								Paths: []v1beta1.HTTPIngressPath{
+									{Path: "/*"},
+									{Path: "/API/v1/"},
+									{Path: "/static/*"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		prohibited := []*ptv1.AzureIngressProhibitedTarget{
+			{Spec: ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host, Paths: []string{"/api/*"}}},
+		}
+
+		It("should drop the paths which overlap in either direction and report partial overlaps", func() {
+			rules, pruned := PruneIngressRules(ingress, prohibited, nil, nil, false, false)
+			Expect(rules).To(HaveLen(1))
+			Expect(rules[0].HTTP.Paths).To(Equal([]v1beta1.HTTPIngressPath{{Path: "/static/*"}}))
+
+			Expect(pruned).To(HaveLen(2))
+			Expect(pruned[0].Target.Path).To(Equal(TargetPath("/*")))
+			Expect(pruned[0].ProhibitedBy.Path).To(Equal(TargetPath("/api/*")))
+			Expect(pruned[0].IsPartialOverlap()).To(BeTrue())
+			Expect(pruned[1].Target.Path).To(Equal(TargetPath("/API/v1/")))
+			Expect(pruned[1].IsPartialOverlap()).To(BeFalse())
+		})
+	})
+
+	Context("Test PruneIngressRules() with managed targets", func() {
+		ingress := &v1beta1.Ingress{
+			Spec: v1beta1.IngressSpec{
+				Rules: []v1beta1.IngressRule{
+					{
+						Host: tests.Host,
+						IngressRuleValue: v1beta1.IngressRuleValue{
+							HTTP: &v1beta1.HTTPIngressRuleValue{
+								Paths: []v1beta1.HTTPIngressPath{
+									{Path: "/api/v1/*"},
+									{Path: "/static/*"},
+								},
+							},
+						},
+					},
+					{
+						Host: tests.OtherHost,
+						IngressRuleValue: v1beta1.IngressRuleValue{
+							HTTP: &v1beta1.HTTPIngressRuleValue{},
+						},
+					},
+				},
+			},
+		}
+		managed := []*mtv1.AzureIngressManagedTarget{
+			{Spec: mtv1.AzureIngressManagedTargetSpec{Hostname: tests.Host, Paths: []string{"/api/*"}}},
+		}
+
+		It("should keep only the paths within the managed targets", func() {
+			rules, pruned := PruneIngressRules(ingress, nil, managed, nil, false, false)
+			Expect(rules).To(HaveLen(1))
+			Expect(rules[0].HTTP.Paths).To(Equal([]v1beta1.HTTPIngressPath{{Path: "/api/v1/*"}}))
+
+			Expect(pruned).To(HaveLen(2))
+			for _, prunedPath := range pruned {
+				Expect(prunedPath.NotManaged).To(BeTrue())
+				Expect(prunedPath.IsPartialOverlap()).To(BeFalse())
+			}
+		})
+	})
+
 })
@@ -64,35 +64,17 @@ func MergeListeners(listenerBuckets ...[]n.ApplicationGatewayHTTPListener) []n.A
 	return merged
 }
 
-// LogListeners emits a few log lines detailing what Listeners are created, blacklisted, and removed from ARM.
-func LogListeners(existingBlacklisted []n.ApplicationGatewayHTTPListener, existingNonBlacklisted []n.ApplicationGatewayHTTPListener, managedListeners []n.ApplicationGatewayHTTPListener) {
-	var garbage []n.ApplicationGatewayHTTPListener
-
-	blacklistedSet := indexListenersByName(existingBlacklisted)
-	managedSet := indexListenersByName(managedListeners)
-
-	for listenerName, listener := range indexListenersByName(existingNonBlacklisted) {
-		_, existsInBlacklist := blacklistedSet[listenerName]
-		_, existsInNewListeners := managedSet[listenerName]
-		if !existsInBlacklist && !existsInNewListeners {
-			garbage = append(garbage, listener)
-		}
-	}
-
-	glog.V(3).Info("[brownfield] Listeners AGIC created: ", getListenerNames(managedListeners))
-	glog.V(3).Info("[brownfield] Existing Blacklisted Listeners AGIC will retain: ", getListenerNames(existingBlacklisted))
-	glog.V(3).Info("[brownfield] Existing Listeners AGIC will remove: ", getListenerNames(garbage))
+// AddListeners classifies the existing and AGIC created listeners in the ownership report.
+func (r *OwnershipReport) AddListeners(existingBlacklisted []n.ApplicationGatewayHTTPListener, existingNonBlacklisted []n.ApplicationGatewayHTTPListener, managedListeners []n.ApplicationGatewayHTTPListener) {
+	r.add(KindListener, listenerNames(existingBlacklisted), listenerNames(existingNonBlacklisted), listenerNames(managedListeners))
 }
 
-func getListenerNames(listeners []n.ApplicationGatewayHTTPListener) string {
+func listenerNames(listeners []n.ApplicationGatewayHTTPListener) []string {
 	var names []string
-	for _, p := range listeners {
-		names = append(names, *p.Name)
-	}
-	if len(names) == 0 {
-		return "n/a"
+	for _, listener := range listeners {
+		names = append(names, *listener.Name)
 	}
-	return strings.Join(names, ", ")
+	return names
 }
 
 func indexListenersByName(listeners []n.ApplicationGatewayHTTPListener) listenersByName {
@@ -116,16 +98,58 @@ func (er *ExistingResources) getListenersByName() listenersByName {
 	return listenersByName
 }
 
+// GetListenerTarget creates a Target (without a path) for the hostname, frontend IP and port of the given listener.
+func (er ExistingResources) GetListenerTarget(listener n.ApplicationGatewayHTTPListener) Target {
+	var target Target
+	if listener.ApplicationGatewayHTTPListenerPropertiesFormat == nil {
+		return target
+	}
+	if listener.HostName != nil {
+		target.Hostname = *listener.HostName
+	}
+	if listener.FrontendIPConfiguration != nil && listener.FrontendIPConfiguration.ID != nil {
+		for _, frontendIP := range er.FrontendIPs {
+			if frontendIP.ID == nil || *frontendIP.ID != *listener.FrontendIPConfiguration.ID || frontendIP.ApplicationGatewayFrontendIPConfigurationPropertiesFormat == nil {
+				continue
+			}
+			target.IP = FrontendIPPublic
+			if frontendIP.PrivateIPAddress != nil {
+				target.IP = FrontendIPPrivate
+			}
+		}
+	}
+	if listener.FrontendPort != nil && listener.FrontendPort.ID != nil {
+		name := portName(utils.GetLastChunkOfSlashed(*listener.FrontendPort.ID))
+		for _, port := range er.Ports {
+			if port.Name != nil && portName(*port.Name) == name && port.ApplicationGatewayFrontendPortPropertiesFormat != nil && port.Port != nil {
+				target.Port = *port.Port
+			}
+		}
+	}
+	return target
+}
+
 func (er ExistingResources) getBlacklistedListenersSet() map[listenerName]interface{} {
-	// Determine the list of prohibited listeners from the hostnames
+	// Determine the list of prohibited listeners from the hostnames, frontend IPs and ports; With managed
+	// targets in place, every listener outside of them is prohibited too.
 	blacklistedListenersSet := make(map[listenerName]interface{})
-	prohibitedHostnames := er.getProhibitedHostnames()
+	blacklist := er.getTargetBlacklist()
+	whitelist := er.getTargetWhitelist()
 	for _, listener := range er.Listeners {
-		if listener.HostName == nil {
+		listenerTarget := er.GetListenerTarget(listener)
+		if whitelist != nil && !listenerTarget.IsWhitelisted(whitelist) {
+			blacklistedListenersSet[listenerName(*listener.Name)] = nil
 			continue
 		}
-		if _, exists := prohibitedHostnames[*listener.HostName]; exists {
-			blacklistedListenersSet[listenerName(*listener.Name)] = nil
+		for _, blTarget := range *blacklist {
+			// A listener is blacklisted by its hostname, or - when the blacklisted target has neither hostname
+			// nor paths - by the frontend IP and port alone.
+			hostIsBlacklisted := blTarget.Hostname != "" && strings.EqualFold(blTarget.Hostname, listenerTarget.Hostname)
+			frontendOnly := blTarget.Hostname == "" && blTarget.Path == "" && (blTarget.IP != "" || blTarget.Port != 0)
+			if (hostIsBlacklisted || frontendOnly) && listenerTarget.frontendIsBlacklisted(blTarget) {
+				blacklistedListenersSet[listenerName(*listener.Name)] = nil
+				break
+			}
 		}
 	}
 
@@ -6,6 +6,8 @@
 package brownfield
 
 import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -26,7 +28,7 @@ var _ = Describe("Test blacklist listeners", func() {
 	Context("Test GetBlacklistedListeners() with a blacklist", func() {
 		It("should create a list of blacklisted and non blacklisted listeners", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets() // Host: "bye.com", Paths: [/fox, /bar]
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			blacklisted, nonBlacklisted := er.GetBlacklistedListeners()
 
 			Expect(len(blacklisted)).To(Equal(3))
@@ -49,7 +51,7 @@ var _ = Describe("Test blacklist listeners", func() {
 					},
 				},
 			}
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 
 			blacklisted, nonBlacklisted := er.GetBlacklistedListeners()
 
@@ -67,7 +69,7 @@ var _ = Describe("Test blacklist listeners", func() {
 		It("should create a list of blacklisted and non blacklisted listeners", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets()                    // Host: "bye.com", Paths: [/fox, /bar]
 			prohibitedTargets = append(prohibitedTargets, &ptv1.AzureIngressProhibitedTarget{}) // Host: '', Path: []
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			blacklisted, nonBlacklisted := er.GetBlacklistedListeners()
 
 			Expect(len(blacklisted)).To(Equal(4))
@@ -89,7 +91,7 @@ var _ = Describe("Test blacklist listeners", func() {
 				},
 			})
 
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			set := er.getBlacklistedListenersSet()
 
 			Expect(len(set)).To(Equal(4))
@@ -107,7 +109,7 @@ var _ = Describe("Test blacklist listeners", func() {
 	Context("Test getListenersByName()", func() {
 		It("should create a set of listeners by name and memoize it", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets()
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			er.listenersByName = nil
 			listenersByName := er.getListenersByName()
 			Expect(er.listenersByName).ToNot(BeNil())
@@ -128,4 +130,41 @@ var _ = Describe("Test blacklist listeners", func() {
 		})
 	})
 
+	Context("Test GetBlacklistedListeners() with a blacklisted frontend IP and port", func() {
+		newListener := func(name, host, frontendIP, port string) n.ApplicationGatewayHTTPListener {
+			return n.ApplicationGatewayHTTPListener{
+				Name: to.StringPtr(name),
+				ApplicationGatewayHTTPListenerPropertiesFormat: &n.ApplicationGatewayHTTPListenerPropertiesFormat{
+					FrontendIPConfiguration: &n.SubResource{ID: to.StringPtr(frontendIP)},
+					FrontendPort:            &n.SubResource{ID: to.StringPtr("/x/y/z/" + port)},
+					HostName:                to.StringPtr(host),
+				},
+			}
+		}
+		publicIP := fixtures.GetPublicIPConfiguration()
+		privateIP := fixtures.GetPrivateIPConfiguration()
+		publicListener := newListener("public-80", tests.Host, fixtures.PublicIPName, "fp-80")
+		privateListener := newListener("private-80", tests.Host, fixtures.PrivateIPName, "fp-80")
+		listener8443 := newListener("public-8443", tests.OtherHost, fixtures.PublicIPName, "fp-8443")
+
+		er := ExistingResources{
+			Listeners:   []n.ApplicationGatewayHTTPListener{publicListener, privateListener, listener8443},
+			FrontendIPs: []n.ApplicationGatewayFrontendIPConfiguration{publicIP, privateIP},
+			Ports: []n.ApplicationGatewayFrontendPort{
+				{Name: to.StringPtr("fp-80"), ApplicationGatewayFrontendPortPropertiesFormat: &n.ApplicationGatewayFrontendPortPropertiesFormat{Port: to.Int32Ptr(80)}},
+				{Name: to.StringPtr("fp-8443"), ApplicationGatewayFrontendPortPropertiesFormat: &n.ApplicationGatewayFrontendPortPropertiesFormat{Port: to.Int32Ptr(8443)}},
+			},
+			ProhibitedTargets: []*ptv1.AzureIngressProhibitedTarget{
+				{Spec: ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host, IP: "private"}},
+				{Spec: ptv1.AzureIngressProhibitedTargetSpec{Port: 8443}},
+			},
+		}
+
+		It("should blacklist only the listeners on the prohibited frontends", func() {
+			blacklisted, nonBlacklisted := er.GetBlacklistedListeners()
+			Expect(blacklisted).To(ConsistOf(privateListener, listener8443))
+			Expect(nonBlacklisted).To(ConsistOf(publicListener))
+		})
+	})
+
 })
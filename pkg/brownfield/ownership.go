@@ -0,0 +1,252 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+
+	mtv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+)
+
+// ResourceKind is the kind of App Gateway sub-resource listed in an ownership report.
+type ResourceKind string
+
+const (
+	// KindListener is an HTTP listener.
+	KindListener ResourceKind = "listener"
+
+	// KindRoutingRule is a request routing rule.
+	KindRoutingRule ResourceKind = "routingRule"
+
+	// KindURLPathMap is a URL path map.
+	KindURLPathMap ResourceKind = "urlPathMap"
+
+	// KindBackendPool is a backend address pool.
+	KindBackendPool ResourceKind = "backendPool"
+
+	// KindBackendHTTPSettings is a backend HTTP settings object.
+	KindBackendHTTPSettings ResourceKind = "backendHttpSettings"
+
+	// KindProbe is a health probe.
+	KindProbe ResourceKind = "probe"
+
+	// KindFrontendPort is a frontend port.
+	KindFrontendPort ResourceKind = "frontendPort"
+
+	// KindRedirect is a redirect configuration.
+	KindRedirect ResourceKind = "redirect"
+
+	// KindSslCertificate is an SSL certificate.
+	KindSslCertificate ResourceKind = "sslCertificate"
+
+	// KindRewriteRuleSet is a rewrite rule set.
+	KindRewriteRuleSet ResourceKind = "rewriteRuleSet"
+)
+
+// resourceKinds is the order in which kinds appear in a report.
+var resourceKinds = []ResourceKind{
+	KindListener,
+	KindRoutingRule,
+	KindURLPathMap,
+	KindBackendPool,
+	KindBackendHTTPSettings,
+	KindProbe,
+	KindFrontendPort,
+	KindRedirect,
+	KindSslCertificate,
+	KindRewriteRuleSet,
+}
+
+// Ownership tells who owns an App Gateway sub-resource.
+type Ownership string
+
+const (
+	// OwnershipManaged is config AGIC generated from Kubernetes resources.
+	OwnershipManaged Ownership = "managed"
+
+	// OwnershipProhibited is existing config AGIC retains as is.
+	OwnershipProhibited Ownership = "prohibited"
+
+	// OwnershipOrphaned is existing config, which is neither prohibited nor generated by AGIC.
+	OwnershipOrphaned Ownership = "orphaned"
+
+	// OwnershipForeign is existing config missing from the ownership record of AGIC, which AGIC retains as is.
+	OwnershipForeign Ownership = "foreign"
+)
+
+// ResourceOwnership is the entry of a single App Gateway sub-resource in an ownership report.
+type ResourceOwnership struct {
+	Kind      ResourceKind `json:"kind"`
+	Name      string       `json:"name"`
+	Ownership Ownership    `json:"ownership"`
+
+	// ProhibitedBy lists the prohibited targets (namespace/name) matching prohibited config.
+	ProhibitedBy []string `json:"prohibitedBy,omitempty"`
+
+	// NotManaged is true for prohibited config, which is outside of all managed targets.
+	NotManaged bool `json:"notManaged,omitempty"`
+
+	// Ingresses lists the ingresses (namespace/name) AGIC generated managed config from.
+	Ingresses []string `json:"ingresses,omitempty"`
+}
+
+// OwnershipReport lists the sub-resources of an App Gateway, classified by who owns them.
+type OwnershipReport struct {
+	AppGateway string              `json:"appGateway,omitempty"`
+	Resources  []ResourceOwnership `json:"resources"`
+
+	hasManagedTargets bool
+	prohibitedBy      map[ResourceKind]map[string][]string
+	ingresses         map[ResourceKind]map[string][]string
+	byKind            map[ResourceKind]map[string]ResourceOwnership
+}
+
+// NewOwnershipReport creates an empty report for the given existing App Gateway config.
+// The prohibited targets each sub-resource is protected by are determined up front, from the config as it is
+// before AGIC changes it.
+func NewOwnershipReport(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget, managedTargets []*mtv1.AzureIngressManagedTarget) *OwnershipReport {
+	report := &OwnershipReport{
+		hasManagedTargets: len(managedTargets) > 0,
+		prohibitedBy:      make(map[ResourceKind]map[string][]string),
+		ingresses:         make(map[ResourceKind]map[string][]string),
+		byKind:            make(map[ResourceKind]map[string]ResourceOwnership),
+	}
+	for _, prohibitedTarget := range prohibitedTargets {
+		targetName := fmt.Sprintf("%s/%s", prohibitedTarget.Namespace, prohibitedTarget.Name)
+		matches := GetProhibitedTargetMatches(appGw, prohibitedTarget)
+		for kind, names := range map[ResourceKind][]string{
+			KindListener:            matches.Listeners,
+			KindRoutingRule:         matches.RoutingRules,
+			KindURLPathMap:          matches.URLPathMaps,
+			KindBackendPool:         matches.BackendPools,
+			KindBackendHTTPSettings: matches.BackendHTTPSettings,
+			KindProbe:               matches.Probes,
+			KindFrontendPort:        matches.FrontendPorts,
+			KindRedirect:            matches.Redirects,
+			KindSslCertificate:      matches.SslCertificates,
+		} {
+			for _, name := range names {
+				report.prohibitedBy[kind] = appendTo(report.prohibitedBy[kind], name, targetName)
+			}
+		}
+	}
+	return report
+}
+
+// AddIngress records that AGIC generated the named sub-resource from the given ingress (namespace/name).
+func (r *OwnershipReport) AddIngress(kind ResourceKind, name string, ingress string) {
+	if r == nil {
+		return
+	}
+	for _, existing := range r.ingresses[kind][name] {
+		if existing == ingress {
+			return
+		}
+	}
+	r.ingresses[kind] = appendTo(r.ingresses[kind], name, ingress)
+}
+
+// Complete sorts the classified sub-resources into the list of resources of the report and logs a summary per kind.
+func (r *OwnershipReport) Complete(logger Logger) {
+	if r == nil {
+		return
+	}
+	r.Resources = nil
+	for _, kind := range resourceKinds {
+		var names []string
+		for name := range r.byKind[kind] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		namesByOwnership := make(map[Ownership][]string)
+		for _, name := range names {
+			resource := r.byKind[kind][name]
+			resource.Ingresses = r.ingresses[kind][name]
+			sort.Strings(resource.Ingresses)
+			r.Resources = append(r.Resources, resource)
+			namesByOwnership[resource.Ownership] = append(namesByOwnership[resource.Ownership], name)
+		}
+
+		if len(names) > 0 {
+			summary := fmt.Sprintf("[brownfield] %s: managed [%s]; prohibited [%s]; orphaned [%s]", kind,
+				strings.Join(namesByOwnership[OwnershipManaged], ", "),
+				strings.Join(namesByOwnership[OwnershipProhibited], ", "),
+				strings.Join(namesByOwnership[OwnershipOrphaned], ", "))
+			if foreign := namesByOwnership[OwnershipForeign]; len(foreign) > 0 {
+				summary += fmt.Sprintf("; foreign [%s]", strings.Join(foreign, ", "))
+			}
+			logger.Info(summary)
+		}
+	}
+}
+
+// Names lists the names of the sub-resources of the given kind and ownership in the report.
+func (r *OwnershipReport) Names(kind ResourceKind, ownership Ownership) []string {
+	if r == nil {
+		return nil
+	}
+	var names []string
+	for name, resource := range r.byKind[kind] {
+		if resource.Ownership == ownership {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddForeign reclassifies the named existing sub-resources as created by someone other than AGIC.
+func (r *OwnershipReport) AddForeign(kind ResourceKind, names []string) {
+	if r == nil {
+		return
+	}
+	if r.byKind[kind] == nil {
+		r.byKind[kind] = make(map[string]ResourceOwnership)
+	}
+	for _, name := range names {
+		r.byKind[kind][name] = ResourceOwnership{Kind: kind, Name: name, Ownership: OwnershipForeign}
+	}
+}
+
+// add classifies the sub-resources of one kind. Prohibited config takes precedence over config AGIC generated
+// with the same name, since it is the existing config which is retained.
+func (r *OwnershipReport) add(kind ResourceKind, existingBlacklisted []string, existingNonBlacklisted []string, managed []string) {
+	if r == nil {
+		return
+	}
+	resources := make(map[string]ResourceOwnership)
+	for _, name := range existingNonBlacklisted {
+		resources[name] = ResourceOwnership{Kind: kind, Name: name, Ownership: OwnershipOrphaned}
+	}
+	for _, name := range managed {
+		resources[name] = ResourceOwnership{Kind: kind, Name: name, Ownership: OwnershipManaged}
+	}
+	for _, name := range existingBlacklisted {
+		prohibitedBy := r.prohibitedBy[kind][name]
+		resources[name] = ResourceOwnership{
+			Kind:         kind,
+			Name:         name,
+			Ownership:    OwnershipProhibited,
+			ProhibitedBy: prohibitedBy,
+			NotManaged:   len(prohibitedBy) == 0 && r.hasManagedTargets,
+		}
+	}
+	r.byKind[kind] = resources
+}
+
+func appendTo(index map[string][]string, key string, value string) map[string][]string {
+	if index == nil {
+		index = make(map[string][]string)
+	}
+	index[key] = append(index[key], value)
+	return index
+}
@@ -0,0 +1,273 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure/tags"
+)
+
+const (
+	ownershipRecordVersion = 1
+
+	// maxTagValueLength is the longest value Azure accepts for a tag.
+	maxTagValueLength = 256
+
+	// maxOwnershipRecordTags caps the number of tags the records of all clusters are split into; Azure allows 50 tags
+	// per resource and some are needed by AGIC and the users of the App Gateway.
+	maxOwnershipRecordTags = 40
+)
+
+// OwnershipRecord lists the names of the App Gateway sub-resources AGIC created, by kind.
+type OwnershipRecord map[ResourceKind][]string
+
+// NewOwnershipRecord lists the names of all sub-resources of the given App Gateway.
+func NewOwnershipRecord(appGw n.ApplicationGateway) OwnershipRecord {
+	er := NewExistingResources(appGw, nil, nil, nil)
+	record := make(OwnershipRecord)
+	record.Add(KindListener, listenerNames(er.Listeners)...)
+	record.Add(KindRoutingRule, ruleNames(er.RoutingRules)...)
+	record.Add(KindURLPathMap, pathMapNames(er.URLPathMaps)...)
+	record.Add(KindBackendPool, poolNames(er.BackendPools)...)
+	record.Add(KindBackendHTTPSettings, settingNames(er.HTTPSettings)...)
+	record.Add(KindProbe, probeNames(er.Probes)...)
+	record.Add(KindFrontendPort, portNames(er.Ports)...)
+	record.Add(KindRedirect, redirectNames(er.Redirects)...)
+	record.Add(KindSslCertificate, certNames(er.Certificates)...)
+	record.Add(KindRewriteRuleSet, rewriteRuleSetNames(er.RewriteRuleSets)...)
+	return record
+}
+
+// Has tells whether the named sub-resource is in the record.
+func (r OwnershipRecord) Has(kind ResourceKind, name string) bool {
+	for _, recorded := range r[kind] {
+		if recorded == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Add puts the given names in the record, keeping the names of each kind sorted and unique.
+func (r OwnershipRecord) Add(kind ResourceKind, names ...string) {
+	if len(names) == 0 {
+		return
+	}
+	r[kind] = uniqueSorted(append(r[kind], names...))
+}
+
+// Remove takes the given names out of the record.
+func (r OwnershipRecord) Remove(kind ResourceKind, names ...string) {
+	removed := make(map[string]interface{})
+	for _, name := range names {
+		removed[name] = nil
+	}
+	var kept []string
+	for _, name := range r[kind] {
+		if _, exists := removed[name]; !exists {
+			kept = append(kept, name)
+		}
+	}
+	if len(kept) == 0 {
+		delete(r, kind)
+		return
+	}
+	r[kind] = kept
+}
+
+// ClusterRecord is the ownership record kept by the Ingress Controller of one cluster. Cluster is blank unless
+// several clusters share the App Gateway.
+type ClusterRecord struct {
+	Cluster   string          `json:"cluster,omitempty"`
+	Resources OwnershipRecord `json:"resources"`
+
+	// PoolAddresses lists the backend addresses the cluster contributed to each of its pools; It is kept only for
+	// clusters sharing the App Gateway, which combine the addresses of pools they have in common.
+	PoolAddresses map[string][]string `json:"poolAddresses,omitempty"`
+}
+
+type clusterRecordJSON struct {
+	Version int `json:"version"`
+	ClusterRecord
+}
+
+// GetOwnershipRecord reads the ownership record of the given cluster from the tags of the given App Gateway.
+// It returns a nil record when the App Gateway has none.
+func GetOwnershipRecord(appGw n.ApplicationGateway, cluster string) (*ClusterRecord, error) {
+	chunks := getOwnershipRecordTags(appGw)[clusterTagKey(cluster)]
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+	record, err := decodeClusterRecord(chunks)
+	if err != nil || record.Cluster != cluster {
+		return nil, ErrOwnershipRecordCorrupt
+	}
+	return record, nil
+}
+
+// GetClusterRecords reads the ownership records of all clusters from the tags of the given App Gateway, by cluster.
+// Records, which cannot be decoded, are left out and ErrOwnershipRecordCorrupt is returned along with the others.
+func GetClusterRecords(appGw n.ApplicationGateway) (map[string]*ClusterRecord, error) {
+	var err error
+	records := make(map[string]*ClusterRecord)
+	for _, chunks := range getOwnershipRecordTags(appGw) {
+		record, decodeErr := decodeClusterRecord(chunks)
+		if decodeErr != nil {
+			err = decodeErr
+			continue
+		}
+		records[record.Cluster] = record
+	}
+	return records, err
+}
+
+// SetOwnershipRecord writes the ownership record into the tags of the given App Gateway, replacing the previous record
+// of the same cluster. The record is compressed and split across as many tags as needed. When it does not fit, the
+// tags are left as they are and ErrOwnershipRecordTooLarge is returned.
+func SetOwnershipRecord(appGw *n.ApplicationGateway, record ClusterRecord) error {
+	if record.Resources == nil {
+		record.Resources = make(OwnershipRecord)
+	}
+	recordJSON, err := json.Marshal(clusterRecordJSON{Version: ownershipRecordVersion, ClusterRecord: record})
+	if err != nil {
+		return err
+	}
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(recordJSON); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	encoded := base64.StdEncoding.EncodeToString(compressed.Bytes())
+
+	var chunks []string
+	for len(encoded) > maxTagValueLength {
+		chunks = append(chunks, encoded[:maxTagValueLength])
+		encoded = encoded[maxTagValueLength:]
+	}
+	chunks = append(chunks, encoded)
+
+	// The records of all clusters sharing the App Gateway share the tag budget.
+	clusterKey := clusterTagKey(record.Cluster)
+	otherTags := 0
+	for otherKey, otherChunks := range getOwnershipRecordTags(*appGw) {
+		if otherKey != clusterKey {
+			otherTags += len(otherChunks)
+		}
+	}
+	if otherTags+len(chunks) > maxOwnershipRecordTags {
+		return ErrOwnershipRecordTooLarge
+	}
+
+	if appGw.Tags == nil {
+		appGw.Tags = make(map[string]*string)
+	}
+	for key := range appGw.Tags {
+		if tagClusterKey, _, isRecordTag := parseOwnershipRecordTag(key); isRecordTag && tagClusterKey == clusterKey {
+			delete(appGw.Tags, key)
+		}
+	}
+	tagPrefix := tags.OwnershipRecord
+	if clusterKey != "" {
+		tagPrefix = fmt.Sprintf("%s-%s", tags.OwnershipRecord, clusterKey)
+	}
+	for idx, chunk := range chunks {
+		appGw.Tags[fmt.Sprintf("%s-%d", tagPrefix, idx)] = to.StringPtr(chunk)
+	}
+	return nil
+}
+
+// getOwnershipRecordTags groups the values of the ownership record tags by cluster key, in the order of the chunks.
+func getOwnershipRecordTags(appGw n.ApplicationGateway) map[string]map[int]string {
+	chunksByCluster := make(map[string]map[int]string)
+	for key, value := range appGw.Tags {
+		clusterKey, idx, isRecordTag := parseOwnershipRecordTag(key)
+		if !isRecordTag || value == nil {
+			continue
+		}
+		if chunksByCluster[clusterKey] == nil {
+			chunksByCluster[clusterKey] = make(map[int]string)
+		}
+		chunksByCluster[clusterKey][idx] = *value
+	}
+	return chunksByCluster
+}
+
+func decodeClusterRecord(chunks map[int]string) (*ClusterRecord, error) {
+	var encoded strings.Builder
+	for idx := 0; idx < len(chunks); idx++ {
+		chunk, exists := chunks[idx]
+		if !exists {
+			return nil, ErrOwnershipRecordCorrupt
+		}
+		encoded.WriteString(chunk)
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(encoded.String())
+	if err != nil {
+		return nil, ErrOwnershipRecordCorrupt
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, ErrOwnershipRecordCorrupt
+	}
+	recordJSON, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, ErrOwnershipRecordCorrupt
+	}
+
+	var decoded clusterRecordJSON
+	if err := json.Unmarshal(recordJSON, &decoded); err != nil || decoded.Version != ownershipRecordVersion {
+		return nil, ErrOwnershipRecordCorrupt
+	}
+	if decoded.Resources == nil {
+		decoded.Resources = make(OwnershipRecord)
+	}
+	return &decoded.ClusterRecord, nil
+}
+
+// clusterTagKey shortens the cluster ID into the part of the tag keys identifying the record of the cluster;
+// Cluster IDs may be long and contain characters not allowed in tag keys.
+func clusterTagKey(cluster string) string {
+	if cluster == "" {
+		return ""
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(cluster))
+	return fmt.Sprintf("%08x", hash.Sum32())
+}
+
+// parseOwnershipRecordTag splits the key of an ownership record tag into the cluster key and the position of the chunk
+// of the record held by the tag.
+func parseOwnershipRecordTag(key string) (string, int, bool) {
+	if !strings.HasPrefix(key, tags.OwnershipRecord+"-") {
+		return "", 0, false
+	}
+	suffix := strings.TrimPrefix(key, tags.OwnershipRecord+"-")
+	var clusterKey string
+	if sep := strings.LastIndex(suffix, "-"); sep >= 0 {
+		clusterKey, suffix = suffix[:sep], suffix[sep+1:]
+	}
+	idx, err := strconv.Atoi(suffix)
+	if err != nil || idx < 0 {
+		return "", 0, false
+	}
+	return clusterKey, idx, true
+}
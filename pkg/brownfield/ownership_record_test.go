@@ -0,0 +1,126 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	"fmt"
+	"math/rand"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure/tags"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("test ownership record", func() {
+
+	Context("Test NewOwnershipRecord()", func() {
+		It("should list the names of all sub-resources", func() {
+			record := NewOwnershipRecord(fixtures.GetAppGateway())
+			Expect(record[KindListener]).To(ContainElement(fixtures.DefaultHTTPListenerName))
+			Expect(record[KindSslCertificate]).To(ConsistOf(fixtures.CertificateName1, fixtures.CertificateName2, fixtures.CertificateName3))
+			Expect(record).ToNot(HaveKey(KindBackendPool))
+		})
+	})
+
+	Context("Test Add(), Remove() and Has()", func() {
+		It("should keep names sorted and unique", func() {
+			record := make(OwnershipRecord)
+			record.Add(KindProbe, "b", "a", "b")
+			Expect(record[KindProbe]).To(Equal([]string{"a", "b"}))
+			Expect(record.Has(KindProbe, "a")).To(BeTrue())
+			Expect(record.Has(KindListener, "a")).To(BeFalse())
+
+			record.Remove(KindProbe, "a")
+			Expect(record[KindProbe]).To(Equal([]string{"b"}))
+			record.Remove(KindProbe, "b")
+			Expect(record).ToNot(HaveKey(KindProbe))
+		})
+	})
+
+	Context("Test SetOwnershipRecord() and GetOwnershipRecord()", func() {
+		It("should return a nil record for an App Gateway without one", func() {
+			record, err := GetOwnershipRecord(fixtures.GetAppGateway(), "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record).To(BeNil())
+		})
+
+		It("should round trip a record split across several tags", func() {
+			record := make(OwnershipRecord)
+			for idx := 0; idx < 300; idx++ {
+				record.Add(KindListener, fmt.Sprintf("fl-%x-%d", rand.Int63(), idx))
+			}
+			appGw := fixtures.GetAppGateway()
+			appGw.Tags = map[string]*string{
+				"unrelated":                  to.StringPtr("value"),
+				tags.OwnershipRecord + "-99": to.StringPtr("stale"),
+			}
+			Expect(SetOwnershipRecord(&appGw, ClusterRecord{Resources: record})).ToNot(HaveOccurred())
+			Expect(appGw.Tags).To(HaveKey("unrelated"))
+			Expect(appGw.Tags).ToNot(HaveKey(tags.OwnershipRecord + "-99"))
+			Expect(appGw.Tags).To(HaveKey(tags.OwnershipRecord + "-1"))
+			for _, value := range appGw.Tags {
+				Expect(len(*value)).To(BeNumerically("<=", maxTagValueLength))
+			}
+
+			actual, err := GetOwnershipRecord(appGw, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(actual.Resources).To(Equal(record))
+		})
+
+		It("should leave the tags alone when the record does not fit", func() {
+			record := make(OwnershipRecord)
+			for idx := 0; idx < 2000; idx++ {
+				record.Add(KindBackendPool, fmt.Sprintf("pool-%x-%x", rand.Int63(), rand.Int63()))
+			}
+			appGw := n.ApplicationGateway{Tags: map[string]*string{tags.OwnershipRecord + "-0": to.StringPtr("previous")}}
+			Expect(SetOwnershipRecord(&appGw, ClusterRecord{Resources: record})).To(Equal(ErrOwnershipRecordTooLarge))
+			Expect(*appGw.Tags[tags.OwnershipRecord+"-0"]).To(Equal("previous"))
+		})
+
+		It("should keep the records of several clusters apart", func() {
+			appGw := n.ApplicationGateway{}
+			blue := ClusterRecord{
+				Cluster:       "/subscriptions/x/resourceGroups/y/providers/Microsoft.ContainerService/managedClusters/blue",
+				Resources:     OwnershipRecord{KindListener: {"fl-shared"}},
+				PoolAddresses: map[string][]string{"pool-shared": {"10.0.0.1"}},
+			}
+			green := ClusterRecord{
+				Cluster:   "green",
+				Resources: OwnershipRecord{KindListener: {"fl-green", "fl-shared"}},
+			}
+			Expect(SetOwnershipRecord(&appGw, blue)).ToNot(HaveOccurred())
+			Expect(SetOwnershipRecord(&appGw, green)).ToNot(HaveOccurred())
+			Expect(SetOwnershipRecord(&appGw, blue)).ToNot(HaveOccurred())
+			for key := range appGw.Tags {
+				Expect(key).ToNot(ContainSubstring("/"))
+			}
+
+			records, err := GetClusterRecords(appGw)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(records).To(HaveLen(2))
+			Expect(*records[blue.Cluster]).To(Equal(blue))
+			Expect(*records[green.Cluster]).To(Equal(green))
+
+			record, err := GetOwnershipRecord(appGw, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(record).To(BeNil())
+		})
+
+		It("should fail on a corrupt record", func() {
+			appGw := n.ApplicationGateway{Tags: map[string]*string{tags.OwnershipRecord + "-0": to.StringPtr("not base64!")}}
+			_, err := GetOwnershipRecord(appGw, "")
+			Expect(err).To(Equal(ErrOwnershipRecordCorrupt))
+
+			appGw = n.ApplicationGateway{Tags: map[string]*string{tags.OwnershipRecord + "-1": to.StringPtr("missing first chunk")}}
+			_, err = GetOwnershipRecord(appGw, "")
+			Expect(err).To(Equal(ErrOwnershipRecordCorrupt))
+		})
+	})
+})
@@ -0,0 +1,88 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mtv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/mocks"
+)
+
+var _ = Describe("Test ownership report", func() {
+
+	appGw := fixtures.GetAppGateway()
+	prohibitedTargets := []*ptv1.AzureIngressProhibitedTarget{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "prohibit-bye-com"},
+			Spec:       ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host},
+		},
+	}
+
+	Context("Test NewOwnershipReport()", func() {
+		It("should attribute prohibited config to the prohibited target, which matched it", func() {
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
+			existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedListeners()
+			managed := []n.ApplicationGatewayHTTPListener{*fixtures.GetDefaultListener()}
+
+			report := NewOwnershipReport(appGw, prohibitedTargets, nil)
+			report.AddListeners(existingBlacklisted, existingNonBlacklisted, managed)
+			report.AddIngress(KindListener, fixtures.DefaultHTTPListenerName, "default/ingress")
+			report.Complete(&mocks.MockLogger{})
+
+			Expect(report.Resources).To(ContainElement(ResourceOwnership{
+				Kind:         KindListener,
+				Name:         fixtures.HTTPListenerPathBased1,
+				Ownership:    OwnershipProhibited,
+				ProhibitedBy: []string{"default/prohibit-bye-com"},
+			}))
+			Expect(report.Resources).To(ContainElement(ResourceOwnership{
+				Kind:      KindListener,
+				Name:      fixtures.DefaultHTTPListenerName,
+				Ownership: OwnershipManaged,
+				Ingresses: []string{"default/ingress"},
+			}))
+			Expect(report.Resources).To(ContainElement(ResourceOwnership{
+				Kind:      KindListener,
+				Name:      fixtures.HTTPListenerNameBasic,
+				Ownership: OwnershipOrphaned,
+			}))
+		})
+
+		It("should flag config outside of the managed targets", func() {
+			managedTargets := []*mtv1.AzureIngressManagedTarget{
+				{Spec: mtv1.AzureIngressManagedTargetSpec{Hostname: tests.OtherHost}},
+			}
+			er := NewExistingResources(appGw, nil, managedTargets, nil)
+			existingBlacklisted, existingNonBlacklisted := er.GetBlacklistedListeners()
+
+			report := NewOwnershipReport(appGw, nil, managedTargets)
+			report.AddListeners(existingBlacklisted, existingNonBlacklisted, nil)
+			report.Complete(&mocks.MockLogger{})
+
+			Expect(report.Resources).To(ContainElement(ResourceOwnership{
+				Kind:       KindListener,
+				Name:       fixtures.HTTPListenerPathBased1,
+				Ownership:  OwnershipProhibited,
+				NotManaged: true,
+			}))
+		})
+
+		It("should be safe to use without brownfield deployment", func() {
+			var report *OwnershipReport
+			report.AddListeners(nil, nil, nil)
+			report.AddIngress(KindListener, "x", "y")
+			report.Complete(&mocks.MockLogger{})
+			Expect(report).To(BeNil())
+		})
+	})
+})
@@ -6,8 +6,6 @@
 package brownfield
 
 import (
-	"strings"
-
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/golang/glog"
 )
@@ -21,7 +19,8 @@ type pathRulesByName map[pathRuleName]n.ApplicationGatewayPathRule
 // GetBlacklistedPathMaps filters the given list of routing pathMaps to the list pathMaps that AGIC is allowed to manage.
 func (er ExistingResources) GetBlacklistedPathMaps() ([]n.ApplicationGatewayURLPathMap, []n.ApplicationGatewayURLPathMap) {
 
-	blacklist := GetTargetBlacklist(er.ProhibitedTargets)
+	blacklist := er.getTargetBlacklist()
+	whitelist := er.getTargetWhitelist()
 	if blacklist == nil {
 		return nil, er.URLPathMaps
 	}
@@ -29,11 +28,12 @@ func (er ExistingResources) GetBlacklistedPathMaps() ([]n.ApplicationGatewayURLP
 	glog.V(5).Infof("[brownfield] PathMap to Targets map: %+v", pathMapToTargets)
 
 	// Figure out if the given BackendAddressPathMap is blacklisted. It will be if it has a host/path that
-	// has been referenced in a AzureIngressProhibitedTarget CRD (even if it has some other paths that are not)
+	// has been referenced in a AzureIngressProhibitedTarget CRD (even if it has some other paths that are not),
+	// or when AzureIngressManagedTarget CRDs exist and one of its host/paths is not referenced in them.
 	isBlacklisted := func(pathMap n.ApplicationGatewayURLPathMap) bool {
 		targetsForPathMap := pathMapToTargets[urlPathMapName(*pathMap.Name)]
 		for _, target := range targetsForPathMap {
-			if target.IsBlacklisted(blacklist) {
+			if target.isUntouchable(blacklist, whitelist) {
 				glog.V(5).Infof("[brownfield] Routing PathMap %s is blacklisted", *pathMap.Name)
 				return true
 			}
@@ -75,24 +75,9 @@ func MergePathMaps(pathMapBuckets ...[]n.ApplicationGatewayURLPathMap) []n.Appli
 	return merged
 }
 
-// LogPathMaps emits a few log lines detailing what pathMaps are created, blacklisted, and removed from ARM.
-func LogPathMaps(existingBlacklisted []n.ApplicationGatewayURLPathMap, existingNonBlacklisted []n.ApplicationGatewayURLPathMap, managedPathMaps []n.ApplicationGatewayURLPathMap) {
-	var garbage []n.ApplicationGatewayURLPathMap
-
-	blacklistedSet := indexPathMapsByName(existingBlacklisted)
-	managedSet := indexPathMapsByName(managedPathMaps)
-
-	for pathMapName, pathMap := range indexPathMapsByName(existingNonBlacklisted) {
-		_, existsInBlacklist := blacklistedSet[pathMapName]
-		_, existsInNewPathMaps := managedSet[pathMapName]
-		if !existsInBlacklist && !existsInNewPathMaps {
-			garbage = append(garbage, pathMap)
-		}
-	}
-
-	glog.V(3).Info("[brownfield] PathMaps AGIC created: ", getPathMapNames(managedPathMaps))
-	glog.V(3).Info("[brownfield] Existing Blacklisted PathMaps AGIC will retain: ", getPathMapNames(existingBlacklisted))
-	glog.V(3).Info("[brownfield] Existing PathMaps AGIC will remove: ", getPathMapNames(garbage))
+// AddPathMaps classifies the existing and AGIC created URL path maps in the ownership report.
+func (r *OwnershipReport) AddPathMaps(existingBlacklisted []n.ApplicationGatewayURLPathMap, existingNonBlacklisted []n.ApplicationGatewayURLPathMap, managedPathMaps []n.ApplicationGatewayURLPathMap) {
+	r.add(KindURLPathMap, pathMapNames(existingBlacklisted), pathMapNames(existingNonBlacklisted), pathMapNames(managedPathMaps))
 }
 
 // mergePathMapsWithBasicRule merges a Url pathmap with a basic routing rule
@@ -150,15 +135,12 @@ func deletePathMap(pathMapsPtr *[]n.ApplicationGatewayURLPathMap, resourceID *st
 	return pathMapsPtr
 }
 
-func getPathMapNames(pathMaps []n.ApplicationGatewayURLPathMap) string {
+func pathMapNames(pathMaps []n.ApplicationGatewayURLPathMap) []string {
 	var names []string
 	for _, pathMap := range pathMaps {
 		names = append(names, *pathMap.Name)
 	}
-	if len(names) == 0 {
-		return "n/a"
-	}
-	return strings.Join(names, ", ")
+	return names
 }
 
 func indexPathMapsByName(pathMaps []n.ApplicationGatewayURLPathMap) pathMapsByName {
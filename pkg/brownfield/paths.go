@@ -0,0 +1,85 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	"strings"
+)
+
+func (p TargetPath) lower() string {
+	return strings.ToLower(string(p))
+}
+
+// isAll returns true when the path matches every URL path.
+func (p TargetPath) isAll() bool {
+	return p == "" || p == "*" || p == "/*"
+}
+
+// isWildcard returns true when the path matches all URL paths, which begin with its prefix.
+func (p TargetPath) isWildcard() bool {
+	return strings.HasSuffix(string(p), "*")
+}
+
+// prefix is the wildcard path without the trailing *; "/x/*" has prefix "/x/".
+func (p TargetPath) prefix() string {
+	return strings.TrimSuffix(p.lower(), "*")
+}
+
+// exact normalizes a non-wildcard path for comparison; "/X/" becomes "/x".
+func (p TargetPath) exact() string {
+	lower := p.lower()
+	if lower == "/" {
+		return lower
+	}
+	return strings.TrimRight(lower, "/")
+}
+
+// matches figures out whether the wildcard path matches the given exact URL path.
+func (p TargetPath) matches(exactPath string) bool {
+	prefix := p.prefix()
+	return strings.HasPrefix(exactPath, prefix) || exactPath == strings.TrimRight(prefix, "/")
+}
+
+// contains figures out whether every URL path matched by the other path is also matched by this one.
+func (p TargetPath) contains(other TargetPath) bool {
+	if p.isAll() {
+		return true
+	}
+	if other.isAll() {
+		return false
+	}
+
+	if !p.isWildcard() {
+		return !other.isWildcard() && p.exact() == other.exact()
+	}
+
+	if !other.isWildcard() {
+		return p.matches(other.exact())
+	}
+
+	// Both are wildcards: "/x/*" contains "/x/y/*"
+	return strings.HasPrefix(other.prefix(), p.prefix())
+}
+
+// overlaps figures out whether there is at least one URL path matched by both paths.
+func (p TargetPath) overlaps(other TargetPath) bool {
+	if p.isAll() || other.isAll() {
+		return true
+	}
+
+	switch {
+	case !p.isWildcard() && !other.isWildcard():
+		return p.exact() == other.exact()
+	case !other.isWildcard():
+		return p.matches(other.exact())
+	case !p.isWildcard():
+		return other.matches(p.exact())
+	}
+
+	// Both are wildcards: One prefix must begin with the other
+	thisPrefix, otherPrefix := p.prefix(), other.prefix()
+	return strings.HasPrefix(thisPrefix, otherPrefix) || strings.HasPrefix(otherPrefix, thisPrefix)
+}
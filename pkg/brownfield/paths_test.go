@@ -0,0 +1,57 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Test URL path sets", func() {
+
+	Context("test TargetPath.overlaps(TargetPath)", func() {
+		It("should match all paths for a blank path and root wildcards", func() {
+			for _, all := range []TargetPath{"", "*", "/*"} {
+				Expect(all.overlaps("/x")).To(BeTrue())
+				Expect(TargetPath("/x/y/*").overlaps(all)).To(BeTrue())
+			}
+		})
+
+		It("should compare exact paths ignoring case and trailing slashes", func() {
+			Expect(TargetPath("/x").overlaps("/X/")).To(BeTrue())
+			Expect(TargetPath("/x").overlaps("/y")).To(BeFalse())
+			Expect(TargetPath("/").overlaps("/x")).To(BeFalse())
+		})
+
+		It("should compare wildcards with exact paths in both directions", func() {
+			Expect(TargetPath("/x/*").overlaps("/x")).To(BeTrue())
+			Expect(TargetPath("/x/*").overlaps("/x/y/z")).To(BeTrue())
+			Expect(TargetPath("/x/y").overlaps("/X/*")).To(BeTrue())
+			Expect(TargetPath("/x/*").overlaps("/xy")).To(BeFalse())
+			Expect(TargetPath("/xy").overlaps("/x/*")).To(BeFalse())
+		})
+
+		It("should compare two wildcards", func() {
+			Expect(TargetPath("/x/*").overlaps("/x/y/*")).To(BeTrue())
+			Expect(TargetPath("/x/y/*").overlaps("/x/*")).To(BeTrue())
+			Expect(TargetPath("/x/y/*").overlaps("/x/z/*")).To(BeFalse())
+		})
+	})
+
+	Context("test TargetPath.contains(TargetPath) with overlapping sets", func() {
+		It("should not contain a larger set", func() {
+			Expect(TargetPath("/x/*").contains("/*")).To(BeFalse())
+			Expect(TargetPath("/x/y/*").contains("/x/*")).To(BeFalse())
+			Expect(TargetPath("/x").contains("/x/*")).To(BeFalse())
+		})
+
+		It("should contain a smaller set", func() {
+			Expect(TargetPath("/x/*").contains("/x/y/*")).To(BeTrue())
+			Expect(TargetPath("/x/*").contains("/X/Y/")).To(BeTrue())
+			Expect(TargetPath("/x/").contains("/X")).To(BeTrue())
+		})
+	})
+})
@@ -6,8 +6,6 @@
 package brownfield
 
 import (
-	"strings"
-
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/golang/glog"
 
@@ -49,24 +47,9 @@ func MergePools(pools ...[]n.ApplicationGatewayBackendAddressPool) []n.Applicati
 	return merged
 }
 
-// LogPools emits a few log lines detailing what pools are created, blacklisted, and removed from ARM.
-func LogPools(existingBlacklisted []n.ApplicationGatewayBackendAddressPool, existingNonBlacklisted []n.ApplicationGatewayBackendAddressPool, managedPools []n.ApplicationGatewayBackendAddressPool) {
-	var garbage []n.ApplicationGatewayBackendAddressPool
-
-	blacklistedSet := indexPoolsByName(existingBlacklisted)
-	managedSet := indexPoolsByName(managedPools)
-
-	for poolName, pool := range indexPoolsByName(existingNonBlacklisted) {
-		_, existsInBlacklist := blacklistedSet[poolName]
-		_, existsInNewPools := managedSet[poolName]
-		if !existsInBlacklist && !existsInNewPools {
-			garbage = append(garbage, pool)
-		}
-	}
-
-	glog.V(3).Info("[brownfield] Pools AGIC created: ", getPoolNames(managedPools))
-	glog.V(3).Info("[brownfield] Existing Blacklisted Pools AGIC will retain: ", getPoolNames(existingBlacklisted))
-	glog.V(3).Info("[brownfield] Existing Pools AGIC will remove: ", getPoolNames(garbage))
+// AddPools classifies the existing and AGIC created backend pools in the ownership report.
+func (r *OwnershipReport) AddPools(existingBlacklisted []n.ApplicationGatewayBackendAddressPool, existingNonBlacklisted []n.ApplicationGatewayBackendAddressPool, managedPools []n.ApplicationGatewayBackendAddressPool) {
+	r.add(KindBackendPool, poolNames(existingBlacklisted), poolNames(existingNonBlacklisted), poolNames(managedPools))
 }
 
 func indexPoolsByName(pools []n.ApplicationGatewayBackendAddressPool) poolsByName {
@@ -77,15 +60,12 @@ func indexPoolsByName(pools []n.ApplicationGatewayBackendAddressPool) poolsByNam
 	return indexed
 }
 
-func getPoolNames(pool []n.ApplicationGatewayBackendAddressPool) string {
+func poolNames(pools []n.ApplicationGatewayBackendAddressPool) []string {
 	var names []string
-	for _, p := range pool {
-		names = append(names, *p.Name)
-	}
-	if len(names) == 0 {
-		return "n/a"
+	for _, pool := range pools {
+		names = append(names, *pool.Name)
 	}
-	return strings.Join(names, ", ")
+	return names
 }
 
 func (er ExistingResources) getBlacklistedPoolsSet() map[backendPoolName]interface{} {
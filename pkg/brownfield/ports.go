@@ -6,8 +6,6 @@
 package brownfield
 
 import (
-	"strings"
-
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
@@ -56,35 +54,17 @@ func MergePorts(portBuckets ...[]n.ApplicationGatewayFrontendPort) []n.Applicati
 	return merged
 }
 
-// LogPorts emits a few log lines detailing what Ports are created, blacklisted, and removed from ARM.
-func LogPorts(existingBlacklisted []n.ApplicationGatewayFrontendPort, existingNonBlacklisted []n.ApplicationGatewayFrontendPort, managedPorts []n.ApplicationGatewayFrontendPort) {
-	var garbage []n.ApplicationGatewayFrontendPort
-
-	blacklistedSet := indexPortsByPortNumber(existingBlacklisted)
-	managedSet := indexPortsByPortNumber(managedPorts)
-
-	for portNumber, port := range indexPortsByPortNumber(existingNonBlacklisted) {
-		_, existsInBlacklist := blacklistedSet[portNumber]
-		_, existsInNewPorts := managedSet[portNumber]
-		if !existsInBlacklist && !existsInNewPorts {
-			garbage = append(garbage, port)
-		}
-	}
-
-	glog.V(3).Info("[brownfield] Ports AGIC created: ", getPortNames(managedPorts))
-	glog.V(3).Info("[brownfield] Existing Blacklisted Ports AGIC will retain: ", getPortNames(existingBlacklisted))
-	glog.V(3).Info("[brownfield] Existing Ports AGIC will remove: ", getPortNames(garbage))
+// AddPorts classifies the existing and AGIC created frontend ports in the ownership report.
+func (r *OwnershipReport) AddPorts(existingBlacklisted []n.ApplicationGatewayFrontendPort, existingNonBlacklisted []n.ApplicationGatewayFrontendPort, managedPorts []n.ApplicationGatewayFrontendPort) {
+	r.add(KindFrontendPort, portNames(existingBlacklisted), portNames(existingNonBlacklisted), portNames(managedPorts))
 }
 
-func getPortNames(port []n.ApplicationGatewayFrontendPort) string {
+func portNames(ports []n.ApplicationGatewayFrontendPort) []string {
 	var names []string
-	for _, p := range port {
-		names = append(names, *p.Name)
-	}
-	if len(names) == 0 {
-		return "n/a"
+	for _, port := range ports {
+		names = append(names, *port.Name)
 	}
-	return strings.Join(names, ", ")
+	return names
 }
 
 func indexPortsByPortNumber(ports []n.ApplicationGatewayFrontendPort) portsByPortNumber {
@@ -0,0 +1,108 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/pkg/errors"
+
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+)
+
+// validPath is the path pattern from the config ownership proposal; "/*" is allowed as well.
+var validPath = regexp.MustCompile(`^\/(.*\/)?\*$`)
+
+// ValidateProhibitedTarget checks the spec of a prohibited target and returns an error describing the first problem found.
+func ValidateProhibitedTarget(prohibitedTarget *ptv1.AzureIngressProhibitedTarget) error {
+	spec := prohibitedTarget.Spec
+	for _, path := range spec.Paths {
+		if !validPath.MatchString(path) {
+			return errors.Wrapf(ErrInvalidPath, "invalid path %q", path)
+		}
+	}
+
+	ip := strings.ToLower(strings.TrimSpace(spec.IP))
+	if ip != "" && ip != FrontendIPPublic && ip != FrontendIPPrivate && net.ParseIP(ip) == nil {
+		return errors.Wrapf(ErrInvalidIP, "invalid ip %q", spec.IP)
+	}
+
+	if spec.Port < 0 || spec.Port > 65535 {
+		return errors.Wrapf(ErrInvalidPort, "invalid port %d", spec.Port)
+	}
+	return nil
+}
+
+// ProhibitedTargetMatches holds the names of the App Gateway sub-resources a single prohibited target protects.
+type ProhibitedTargetMatches struct {
+	Listeners           []string
+	RoutingRules        []string
+	BackendPools        []string
+	BackendHTTPSettings []string
+	Probes              []string
+	URLPathMaps         []string
+	FrontendPorts       []string
+	Redirects           []string
+	SslCertificates     []string
+}
+
+// GetProhibitedTargetMatches determines which sub-resources of the given App Gateway config are protected by the
+// prohibited target on its own, regardless of any other prohibited or managed targets.
+func GetProhibitedTargetMatches(appGw n.ApplicationGateway, prohibitedTarget *ptv1.AzureIngressProhibitedTarget) ProhibitedTargetMatches {
+	er := NewExistingResources(appGw, []*ptv1.AzureIngressProhibitedTarget{prohibitedTarget}, nil, nil)
+
+	var matches ProhibitedTargetMatches
+
+	listeners, _ := er.GetBlacklistedListeners()
+	for _, listener := range listeners {
+		matches.Listeners = append(matches.Listeners, *listener.Name)
+	}
+
+	rules, _ := er.GetBlacklistedRoutingRules()
+	for _, rule := range rules {
+		matches.RoutingRules = append(matches.RoutingRules, *rule.Name)
+	}
+
+	pools, _ := er.GetBlacklistedPools()
+	for _, pool := range pools {
+		matches.BackendPools = append(matches.BackendPools, *pool.Name)
+	}
+
+	settings, _ := er.GetBlacklistedHTTPSettings()
+	for _, setting := range settings {
+		matches.BackendHTTPSettings = append(matches.BackendHTTPSettings, *setting.Name)
+	}
+
+	probes, _ := er.GetBlacklistedProbes()
+	for _, probe := range probes {
+		matches.Probes = append(matches.Probes, *probe.Name)
+	}
+
+	pathMaps, _ := er.GetBlacklistedPathMaps()
+	for _, pathMap := range pathMaps {
+		matches.URLPathMaps = append(matches.URLPathMaps, *pathMap.Name)
+	}
+
+	ports, _ := er.GetBlacklistedPorts()
+	for _, port := range ports {
+		matches.FrontendPorts = append(matches.FrontendPorts, *port.Name)
+	}
+
+	redirects, _ := er.GetBlacklistedRedirects()
+	for _, redirect := range redirects {
+		matches.Redirects = append(matches.Redirects, *redirect.Name)
+	}
+
+	certs, _ := er.GetBlacklistedCertificates()
+	for _, cert := range certs {
+		matches.SslCertificates = append(matches.SslCertificates, *cert.Name)
+	}
+
+	return matches
+}
@@ -0,0 +1,57 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("Test prohibited target status", func() {
+
+	Context("Test ValidateProhibitedTarget()", func() {
+		validate := func(spec ptv1.AzureIngressProhibitedTargetSpec) error {
+			return ValidateProhibitedTarget(&ptv1.AzureIngressProhibitedTarget{Spec: spec})
+		}
+
+		It("should accept valid targets", func() {
+			Expect(validate(ptv1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host})).To(Succeed())
+			Expect(validate(ptv1.AzureIngressProhibitedTargetSpec{Paths: []string{"/*", "/a/b/*"}})).To(Succeed())
+			Expect(validate(ptv1.AzureIngressProhibitedTargetSpec{IP: "Private", Port: 443})).To(Succeed())
+			Expect(validate(ptv1.AzureIngressProhibitedTargetSpec{IP: "10.1.2.3"})).To(Succeed())
+		})
+
+		It("should reject invalid targets", func() {
+			err := validate(ptv1.AzureIngressProhibitedTargetSpec{Paths: []string{"/a/*", "/b"}})
+			Expect(errors.Cause(err)).To(Equal(ErrInvalidPath))
+			Expect(err.Error()).To(ContainSubstring(`"/b"`))
+
+			err = validate(ptv1.AzureIngressProhibitedTargetSpec{IP: "10.1.2"})
+			Expect(errors.Cause(err)).To(Equal(ErrInvalidIP))
+
+			err = validate(ptv1.AzureIngressProhibitedTargetSpec{Port: 70000})
+			Expect(errors.Cause(err)).To(Equal(ErrInvalidPort))
+		})
+	})
+
+	Context("Test GetProhibitedTargetMatches()", func() {
+		It("should list the resources protected by a single prohibited target", func() {
+			target := &ptv1.AzureIngressProhibitedTarget{
+				Spec: ptv1.AzureIngressProhibitedTargetSpec{
+					Hostname: tests.Host,
+				},
+			}
+			matches := GetProhibitedTargetMatches(fixtures.GetAppGateway(), target)
+			Expect(matches.Listeners).To(ConsistOf(fixtures.HTTPListenerPathBased1))
+			Expect(matches.RoutingRules).To(ConsistOf(fixtures.RequestRoutingRuleName1))
+		})
+	})
+})
@@ -6,8 +6,6 @@
 package brownfield
 
 import (
-	"strings"
-
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/golang/glog"
 
@@ -34,24 +32,9 @@ func (er ExistingResources) GetBlacklistedRedirects() ([]n.ApplicationGatewayRed
 	return blacklistedRedirects, nonBlacklistedRedirects
 }
 
-// LogRedirects emits a few log lines detailing what Redirects are created, blacklisted, and removed from ARM.
-func LogRedirects(existingBlacklisted []n.ApplicationGatewayRedirectConfiguration, existingNonBlacklisted []n.ApplicationGatewayRedirectConfiguration, managedRedirects []n.ApplicationGatewayRedirectConfiguration) {
-	var garbage []n.ApplicationGatewayRedirectConfiguration
-
-	blacklistedSet := indexRedirectsByName(existingBlacklisted)
-	managedSet := indexRedirectsByName(managedRedirects)
-
-	for redirectName, redirect := range indexRedirectsByName(existingNonBlacklisted) {
-		_, existsInBlacklist := blacklistedSet[redirectName]
-		_, existsInNewRedirects := managedSet[redirectName]
-		if !existsInBlacklist && !existsInNewRedirects {
-			garbage = append(garbage, redirect)
-		}
-	}
-
-	glog.V(3).Info("[brownfield] Redirects AGIC created: ", getRedirectNames(managedRedirects))
-	glog.V(3).Info("[brownfield] Existing Blacklisted Redirects AGIC will retain: ", getRedirectNames(existingBlacklisted))
-	glog.V(3).Info("[brownfield] Existing Redirects AGIC will remove: ", getRedirectNames(garbage))
+// AddRedirects classifies the existing and AGIC created redirects in the ownership report.
+func (r *OwnershipReport) AddRedirects(existingBlacklisted []n.ApplicationGatewayRedirectConfiguration, existingNonBlacklisted []n.ApplicationGatewayRedirectConfiguration, managedRedirects []n.ApplicationGatewayRedirectConfiguration) {
+	r.add(KindRedirect, redirectNames(existingBlacklisted), redirectNames(existingNonBlacklisted), redirectNames(managedRedirects))
 }
 
 // MergeRedirects merges list of lists of redirects into a single list, maintaining uniqueness.
@@ -69,15 +52,12 @@ func MergeRedirects(redirectBuckets ...[]n.ApplicationGatewayRedirectConfigurati
 	return merged
 }
 
-func getRedirectNames(redirects []n.ApplicationGatewayRedirectConfiguration) string {
+func redirectNames(redirects []n.ApplicationGatewayRedirectConfiguration) []string {
 	var names []string
 	for _, redirect := range redirects {
 		names = append(names, *redirect.Name)
 	}
-	if len(names) == 0 {
-		return "n/a"
-	}
-	return strings.Join(names, ", ")
+	return names
 }
 
 func indexRedirectsByName(redirects []n.ApplicationGatewayRedirectConfiguration) redirectsByName {
@@ -0,0 +1,36 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+)
+
+type rewriteRuleSetName string
+type rewriteRuleSetsByName map[rewriteRuleSetName]n.ApplicationGatewayRewriteRuleSet
+
+// MergeRewriteRuleSets merges list of lists of rewrite rule sets into a single list, maintaining uniqueness.
+func MergeRewriteRuleSets(rewriteRuleSetBuckets ...[]n.ApplicationGatewayRewriteRuleSet) []n.ApplicationGatewayRewriteRuleSet {
+	uniqRewriteRuleSets := make(rewriteRuleSetsByName)
+	for _, bucket := range rewriteRuleSetBuckets {
+		for _, rewriteRuleSet := range bucket {
+			uniqRewriteRuleSets[rewriteRuleSetName(*rewriteRuleSet.Name)] = rewriteRuleSet
+		}
+	}
+	var merged []n.ApplicationGatewayRewriteRuleSet
+	for _, rewriteRuleSet := range uniqRewriteRuleSets {
+		merged = append(merged, rewriteRuleSet)
+	}
+	return merged
+}
+
+func rewriteRuleSetNames(rewriteRuleSets []n.ApplicationGatewayRewriteRuleSet) []string {
+	var names []string
+	for _, rewriteRuleSet := range rewriteRuleSets {
+		names = append(names, *rewriteRuleSet.Name)
+	}
+	return names
+}
@@ -0,0 +1,27 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package brownfield
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+)
+
+var _ = Describe("Test MergeRewriteRuleSets", func() {
+	Context("Test MergeRewriteRuleSets()", func() {
+		It("should keep the rewrite rule set from the later bucket when names collide", func() {
+			managed := n.ApplicationGatewayRewriteRuleSet{Name: to.StringPtr("rw-a"), Etag: to.StringPtr("managed")}
+			existing := n.ApplicationGatewayRewriteRuleSet{Name: to.StringPtr("rw-a"), Etag: to.StringPtr("existing")}
+			other := n.ApplicationGatewayRewriteRuleSet{Name: to.StringPtr("rw-b")}
+
+			actual := MergeRewriteRuleSets([]n.ApplicationGatewayRewriteRuleSet{managed, other}, []n.ApplicationGatewayRewriteRuleSet{existing})
+			Expect(actual).To(ConsistOf(existing, other))
+		})
+	})
+})
@@ -6,8 +6,6 @@
 package brownfield
 
 import (
-	"strings"
-
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/golang/glog"
 
@@ -21,8 +19,8 @@ type ruleToTargets map[ruleName][]Target
 // GetBlacklistedRoutingRules filters the given list of routing rules to the list rules that AGIC is allowed to manage.
 func (er ExistingResources) GetBlacklistedRoutingRules() ([]n.ApplicationGatewayRequestRoutingRule, []n.ApplicationGatewayRequestRoutingRule) {
 
-	// TODO(draychev): make a method of ExistingResources
-	blacklist := GetTargetBlacklist(er.ProhibitedTargets)
+	blacklist := er.getTargetBlacklist()
+	whitelist := er.getTargetWhitelist()
 	if blacklist == nil {
 		return nil, er.RoutingRules
 	}
@@ -30,11 +28,12 @@ func (er ExistingResources) GetBlacklistedRoutingRules() ([]n.ApplicationGateway
 	glog.V(5).Infof("[brownfield] Rule to Targets map: %+v", ruleToTargets)
 
 	// Figure out if the given routing rule is blacklisted. It will be if it has a host/path that
-	// has been referenced in a AzureIngressProhibitedTarget CRD (even if it has some other paths that are not)
+	// has been referenced in a AzureIngressProhibitedTarget CRD (even if it has some other paths that are not),
+	// or when AzureIngressManagedTarget CRDs exist and one of its host/paths is not referenced in them.
 	isBlacklisted := func(rule n.ApplicationGatewayRequestRoutingRule) bool {
 		targetsForRule := ruleToTargets[ruleName(*rule.Name)]
 		for _, target := range targetsForRule {
-			if target.IsBlacklisted(blacklist) {
+			if target.isUntouchable(blacklist, whitelist) {
 				glog.V(5).Infof("[brownfield] Routing Rule %s is blacklisted", *rule.Name)
 				return true
 			}
@@ -76,24 +75,9 @@ func MergeRules(appGw *n.ApplicationGateway, ruleBuckets ...[]n.ApplicationGatew
 	return merged
 }
 
-// LogRules emits a few log lines detailing what rules are created, blacklisted, and removed from ARM.
-func LogRules(existingBlacklisted []n.ApplicationGatewayRequestRoutingRule, existingNonBlacklisted []n.ApplicationGatewayRequestRoutingRule, managedRules []n.ApplicationGatewayRequestRoutingRule) {
-	var garbage []n.ApplicationGatewayRequestRoutingRule
-
-	blacklistedSet := indexRulesByName(existingBlacklisted)
-	managedSet := indexRulesByName(managedRules)
-
-	for ruleName, rule := range indexRulesByName(existingNonBlacklisted) {
-		_, existsInBlacklist := blacklistedSet[ruleName]
-		_, existsInNewRules := managedSet[ruleName]
-		if !existsInBlacklist && !existsInNewRules {
-			garbage = append(garbage, rule)
-		}
-	}
-
-	glog.V(3).Info("[brownfield] Rules AGIC created: ", getRuleNames(managedRules))
-	glog.V(3).Info("[brownfield] Existing Blacklisted Rules AGIC will retain: ", getRuleNames(existingBlacklisted))
-	glog.V(3).Info("[brownfield] Existing Rules AGIC will remove: ", getRuleNames(garbage))
+// AddRules classifies the existing and AGIC created routing rules in the ownership report.
+func (r *OwnershipReport) AddRules(existingBlacklisted []n.ApplicationGatewayRequestRoutingRule, existingNonBlacklisted []n.ApplicationGatewayRequestRoutingRule, managedRules []n.ApplicationGatewayRequestRoutingRule) {
+	r.add(KindRoutingRule, ruleNames(existingBlacklisted), ruleNames(existingNonBlacklisted), ruleNames(managedRules))
 }
 
 // mergeRoutingRules merges two routing rules by merging their pathRules
@@ -130,15 +114,12 @@ func mergeRoutingRules(appGw *n.ApplicationGateway, firstRoutingRule *n.Applicat
 	return firstRoutingRule
 }
 
-func getRuleNames(cert []n.ApplicationGatewayRequestRoutingRule) string {
+func ruleNames(rules []n.ApplicationGatewayRequestRoutingRule) []string {
 	var names []string
-	for _, p := range cert {
-		names = append(names, *p.Name)
-	}
-	if len(names) == 0 {
-		return "n/a"
+	for _, rule := range rules {
+		names = append(names, *rule.Name)
 	}
-	return strings.Join(names, ", ")
+	return names
 }
 
 func indexRulesByName(rules []n.ApplicationGatewayRequestRoutingRule) rulesByName {
@@ -149,16 +130,14 @@ func indexRulesByName(rules []n.ApplicationGatewayRequestRoutingRule) rulesByNam
 	return indexed
 }
 
-func (er ExistingResources) getHostNameForRoutingRule(rule n.ApplicationGatewayRequestRoutingRule) (string, error) {
+func (er ExistingResources) getListenerTargetForRoutingRule(rule n.ApplicationGatewayRequestRoutingRule) (Target, error) {
 	listenerName := listenerName(utils.GetLastChunkOfSlashed(*rule.HTTPListener.ID))
-	if listener, found := er.getListenersByName()[listenerName]; !found {
+	listener, found := er.getListenersByName()[listenerName]
+	if !found {
 		glog.Errorf("[brownfield] Could not find listener %s in index", listenerName)
-		// TODO(draychev): move this error into a top-level file
-		return "", ErrListenerLookup
-	} else if listener.HostName != nil {
-		return *listener.HostName, nil
+		return Target{}, ErrListenerLookup
 	}
-	return "", nil
+	return er.GetListenerTarget(listener), nil
 }
 
 // getRuleToTargets creates a map from backend pool to targets this backend pool is responsible for.
@@ -171,17 +150,15 @@ func (er ExistingResources) getRuleToTargets() (ruleToTargets, pathmapToTargets)
 		if rule.HTTPListener == nil || rule.HTTPListener.ID == nil {
 			continue
 		}
-		hostName, err := er.getHostNameForRoutingRule(rule)
+		listenerTarget, err := er.getListenerTargetForRoutingRule(rule)
 		if err != nil {
 			glog.Errorf("[brownfield] Could not obtain hostname for rule %s; Skipping rule", ruleName(*rule.Name))
 			continue
 		}
 
 		// Regardless of whether we have a URL PathMap or not. This matches the default backend pool.
-		ruleToTargets[ruleName(*rule.Name)] = append(ruleToTargets[ruleName(*rule.Name)], Target{
-			Hostname: hostName,
-			// Path deliberately omitted
-		})
+		// Path deliberately omitted
+		ruleToTargets[ruleName(*rule.Name)] = append(ruleToTargets[ruleName(*rule.Name)], listenerTarget)
 
 		// SSL Redirects do not have BackendAddressPool
 		if rule.URLPathMap != nil {
@@ -193,7 +170,8 @@ func (er ExistingResources) getRuleToTargets() (ruleToTargets, pathmapToTargets)
 					continue
 				}
 				for _, path := range *pathRule.Paths {
-					target := Target{hostName, TargetPath(path)}
+					target := listenerTarget
+					target.Path = TargetPath(path)
 					ruleToTargets[ruleName(*rule.Name)] = append(ruleToTargets[ruleName(*rule.Name)], target)
 					pathMapToTargets[pathMapName] = append(pathMapToTargets[pathMapName], target)
 				}
@@ -9,6 +9,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	mtv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
 	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
@@ -25,7 +26,7 @@ var _ = Describe("Test blacklist request routing rules", func() {
 	Context("Test getRoutingRuleToTargetsMap()", func() {
 		It("should create a map of routing rules to targets", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets() // Host: "bye.com", Paths: [/fox, /bar]
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 
 			ruleToTargets, pathMapToTargets := er.getRuleToTargets()
 
@@ -56,7 +57,7 @@ var _ = Describe("Test blacklist request routing rules", func() {
 	Context("Test GetBlacklistedRoutingRules() with a blacklist", func() {
 		It("should create a list of blacklisted and non blacklisted request routing rules", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets() // Host: "bye.com", Paths: [/fox, /bar]
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 			blacklisted, nonBlacklisted := er.GetBlacklistedRoutingRules()
 
 			Expect(len(blacklisted)).To(Equal(3))
@@ -75,7 +76,7 @@ var _ = Describe("Test blacklist request routing rules", func() {
 			prohibitedTargets := fixtures.GetAzureIngressProhibitedTargets() // Host: "bye.com", Paths: [/fox, /bar]
 			wildcard := &ptv1.AzureIngressProhibitedTarget{}
 			prohibitedTargets = append(prohibitedTargets, wildcard)
-			er := NewExistingResources(appGw, prohibitedTargets, nil)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, nil)
 
 			blacklisted, nonBlacklisted := er.GetBlacklistedRoutingRules()
 
@@ -88,4 +89,38 @@ var _ = Describe("Test blacklist request routing rules", func() {
 			Expect(blacklisted).To(ContainElement(ruleDefault))
 		})
 	})
+
+	Context("Test GetBlacklistedRoutingRules() with managed targets", func() {
+		It("should blacklist the request routing rules outside of the managed targets", func() {
+			managedTargets := []*mtv1.AzureIngressManagedTarget{
+				{
+					Spec: mtv1.AzureIngressManagedTargetSpec{
+						Hostname: tests.Host,
+					},
+				},
+			}
+			er := NewExistingResources(appGw, nil, managedTargets, nil)
+
+			blacklisted, nonBlacklisted := er.GetBlacklistedRoutingRules()
+
+			Expect(blacklisted).To(ConsistOf(ruleDefault, ruleBasic, rulePathBased2))
+			Expect(nonBlacklisted).To(ConsistOf(rulePathBased1))
+		})
+
+		It("should blacklist the request routing rules with paths outside of the managed targets", func() {
+			managedTargets := []*mtv1.AzureIngressManagedTarget{
+				{
+					Spec: mtv1.AzureIngressManagedTargetSpec{
+						Hostname: tests.Host,
+						Paths:    []string{fixtures.PathFoo},
+					},
+				},
+			}
+			er := NewExistingResources(appGw, nil, managedTargets, nil)
+
+			_, nonBlacklisted := er.GetBlacklistedRoutingRules()
+
+			Expect(nonBlacklisted).ToNot(ContainElement(rulePathBased1))
+		})
+	})
 })
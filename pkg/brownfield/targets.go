@@ -9,24 +9,49 @@ import (
 	"encoding/json"
 	"strings"
 
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	"github.com/golang/glog"
 
+	mtv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
 	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 )
 
 // TargetBlacklist is a list of Targets, which AGIC is not allowed to apply configuration for.
 type TargetBlacklist *[]Target
 
+// TargetWhitelist is a list of Targets, which AGIC is exclusively allowed to apply configuration for.
+type TargetWhitelist *[]Target
+
+// TargetPath is the set of URL paths matched by an App Gateway path rule. Paths are case insensitive.
+// A path ending with a * is a wildcard and matches every URL path it is a prefix of, as well as the path
+// itself without the trailing slash: "/x/*" matches "/x", "/x/" and "/x/y". Any other path is matched
+// exactly, ignoring a trailing slash. A blank path, "*" and "/*" match all URL paths.
 type TargetPath string
 
+const (
+	// FrontendIPPublic identifies the public frontend IP configuration of an App Gateway.
+	FrontendIPPublic = "public"
+
+	// FrontendIPPrivate identifies the private frontend IP configuration of an App Gateway.
+	FrontendIPPrivate = "private"
+)
+
 // Target uniquely identifies a subset of App Gateway configuration, which AGIC will manage or be prohibited from managing.
 type Target struct {
 	Hostname string     `json:"Hostname,omitempty"`
+	IP       string     `json:"IP,omitempty"`
+	Port     int32      `json:"Port,omitempty"`
 	Path     TargetPath `json:"Path,omitempty"`
 }
 
 // IsBlacklisted figures out whether a given Target objects in a list of blacklisted targets.
 func (t Target) IsBlacklisted(blacklist TargetBlacklist) bool {
+	_, isBlacklisted := t.getBlacklisting(blacklist)
+	return isBlacklisted
+}
+
+// getBlacklisting finds the first blacklisted target, which overlaps with the given Target.
+func (t Target) getBlacklisting(blacklist TargetBlacklist) (Target, bool) {
 	jsonTarget, _ := json.Marshal(t)
 	for _, blTarget := range *blacklist {
 
@@ -35,72 +60,160 @@ func (t Target) IsBlacklisted(blacklist TargetBlacklist) bool {
 		// AGIC is allowed to create and modify App Gwy config for blank host.
 		hostIsBlacklisted := blTarget.Hostname == "" || strings.ToLower(t.Hostname) == strings.ToLower(blTarget.Hostname)
 
-		pathIsBlacklisted := blTarget.Path == "" || blTarget.Path == "/*" || t.Path.lower() == blTarget.Path.lower() || blTarget.Path.contains(t.Path) // TODO(draychev): || t.Path.contains(blTarget.Path)
+		// A target without a path (the default backend of a listener) is blacklisted only when all paths are.
+		// Otherwise any overlap between the two sets of URL paths places the target in the blacklist.
+		pathIsBlacklisted := blTarget.Path.isAll()
+		if t.Path != "" {
+			pathIsBlacklisted = blTarget.Path.overlaps(t.Path)
+		}
 
-		// With this version we keep things as simple as possible: match host and exact path to determine
-		// whether given target is in the blacklist. Ideally this would be URL Path set overlap operation,
-		// which we deliberately leave for a later time.
-		if hostIsBlacklisted && pathIsBlacklisted {
+		if hostIsBlacklisted && pathIsBlacklisted && t.frontendIsBlacklisted(blTarget) {
 			glog.V(5).Infof("[brownfield] Target %s is blacklisted", jsonTarget)
-			return true // Found it
+			return blTarget, true // Found it
 		}
 	}
 	glog.V(5).Infof("[brownfield] Target %s is not blacklisted", jsonTarget)
-	return false // Did not find it
+	return Target{}, false // Did not find it
+}
+
+// IsWhitelisted figures out whether a given Target is within a list of whitelisted targets.
+func (t Target) IsWhitelisted(whitelist TargetWhitelist) bool {
+	jsonTarget, _ := json.Marshal(t)
+	for _, wlTarget := range *whitelist {
+		hostIsWhitelisted := wlTarget.Hostname == "" || strings.ToLower(t.Hostname) == strings.ToLower(wlTarget.Hostname)
+
+		// A target without a path is a listener; AGIC may manage it when it manages any path on the listener.
+		pathIsWhitelisted := t.Path == "" || wlTarget.Path.contains(t.Path)
+
+		// Unlike the blacklist, a target on an unknown frontend is only whitelisted when any frontend is.
+		ipIsWhitelisted := wlTarget.IP == "" || wlTarget.IP == t.IP
+		portIsWhitelisted := wlTarget.Port == 0 || wlTarget.Port == t.Port
+
+		if hostIsWhitelisted && pathIsWhitelisted && ipIsWhitelisted && portIsWhitelisted {
+			glog.V(5).Infof("[brownfield] Target %s is whitelisted", jsonTarget)
+			return true
+		}
+	}
+	glog.V(5).Infof("[brownfield] Target %s is not whitelisted", jsonTarget)
+	return false
+}
+
+// isUntouchable figures out whether AGIC must leave the config for the given Target alone. That is the case when
+// the target is blacklisted, or when there is a whitelist and the target is not in it.
+func (t Target) isUntouchable(blacklist TargetBlacklist, whitelist TargetWhitelist) bool {
+	return t.IsBlacklisted(blacklist) || (whitelist != nil && !t.IsWhitelisted(whitelist))
+}
+
+// frontendIsBlacklisted figures out whether the frontend IP and port of the target match the ones of a blacklisted target.
+func (t Target) frontendIsBlacklisted(blTarget Target) bool {
+	// A blank IP or port in the blacklist matches any frontend. A target for which the frontend could
+	// not be determined is also considered a match - AGIC would rather leave config alone than delete it.
+	ipIsBlacklisted := blTarget.IP == "" || t.IP == "" || blTarget.IP == t.IP
+	portIsBlacklisted := blTarget.Port == 0 || t.Port == 0 || blTarget.Port == t.Port
+	return ipIsBlacklisted && portIsBlacklisted
 }
 
 // GetTargetBlacklist returns the list of Targets given a list ProhibitedTarget CRDs.
+// The IP of each Target is as written in the CRD; Use NormalizeTargetBlacklist to compare it with App Gateway frontends.
 func GetTargetBlacklist(prohibitedTargets []*ptv1.AzureIngressProhibitedTarget) TargetBlacklist {
-	// TODO(draychev): make this a method of ExistingResources and memoize it.
 	var target []Target
 	for _, prohibitedTarget := range prohibitedTargets {
-		if len(prohibitedTarget.Spec.Paths) == 0 {
-			target = append(target, Target{
-				Hostname: prohibitedTarget.Spec.Hostname,
-			})
-		}
-		for _, path := range prohibitedTarget.Spec.Paths {
-			target = append(target, Target{
-				Hostname: prohibitedTarget.Spec.Hostname,
-				Path:     TargetPath(strings.ToLower(path)),
-			})
-		}
+		spec := prohibitedTarget.Spec
+		target = append(target, newTargets(spec.Hostname, spec.IP, spec.Port, spec.Paths)...)
 	}
 	return &target
 }
 
-func (p TargetPath) lower() string {
-	return strings.ToLower(string(p))
+// GetTargetWhitelist returns the list of Targets given a list of ManagedTarget CRDs.
+func GetTargetWhitelist(managedTargets []*mtv1.AzureIngressManagedTarget) TargetWhitelist {
+	var target []Target
+	for _, managedTarget := range managedTargets {
+		spec := managedTarget.Spec
+		target = append(target, newTargets(spec.Hostname, spec.IP, spec.Port, spec.Paths)...)
+	}
+	return &target
 }
 
-func (thisPath TargetPath) contains(otherPath TargetPath) bool {
-	if thisPath == "" || thisPath == "*" || thisPath == "/*" {
-		return true
+// newTargets creates one Target per path of a CRD; A CRD without paths applies to all paths.
+func newTargets(hostname string, ip string, port int32, paths []string) []Target {
+	ip = strings.ToLower(strings.TrimSpace(ip))
+	if len(paths) == 0 {
+		return []Target{{
+			Hostname: hostname,
+			IP:       ip,
+			Port:     port,
+		}}
 	}
-
-	// For strings that do not end with a * - do exact match
-	if !strings.HasSuffix(thisPath.lower(), "*") {
-		return thisPath.lower() == otherPath.lower()
+	var targets []Target
+	for _, path := range paths {
+		targets = append(targets, Target{
+			Hostname: hostname,
+			IP:       ip,
+			Port:     port,
+			Path:     TargetPath(strings.ToLower(path)),
+		})
 	}
+	return targets
+}
 
-	// "/x/*" contains "/x"
-	if strings.TrimRight(thisPath.lower(), "/*") == strings.TrimRight(otherPath.lower(), "/*") {
-		return true
+// NormalizeTargetBlacklist replaces the IP addresses in the blacklist with the type of App Gateway frontend IP
+// configuration they belong to. An App Gateway has at most one private and one public frontend IP; The public
+// frontend references a Public IP resource, so any address other than the private one is assumed to be public.
+func NormalizeTargetBlacklist(blacklist TargetBlacklist, frontendIPs []n.ApplicationGatewayFrontendIPConfiguration) TargetBlacklist {
+	if blacklist == nil {
+		return nil
 	}
+	normalized := normalizeTargetIPs(*blacklist, frontendIPs)
+	return &normalized
+}
 
-	if len(thisPath) > len(otherPath) {
-		return false
+// NormalizeTargetWhitelist replaces the IP addresses in the whitelist with the type of App Gateway frontend IP
+// configuration they belong to, the same way NormalizeTargetBlacklist does.
+func NormalizeTargetWhitelist(whitelist TargetWhitelist, frontendIPs []n.ApplicationGatewayFrontendIPConfiguration) TargetWhitelist {
+	if whitelist == nil {
+		return nil
 	}
+	normalized := normalizeTargetIPs(*whitelist, frontendIPs)
+	return &normalized
+}
 
-	thisPathChunks := strings.Split(thisPath.lower(), "/")
-	otherPathChunks := strings.Split(otherPath.lower(), "/")
-	for idx, _ := range thisPathChunks {
-		if thisPathChunks[idx] == "*" {
-			return true
+func normalizeTargetIPs(targets []Target, frontendIPs []n.ApplicationGatewayFrontendIPConfiguration) []Target {
+	var privateIP string
+	for _, frontendIP := range frontendIPs {
+		if frontendIP.ApplicationGatewayFrontendIPConfigurationPropertiesFormat != nil && frontendIP.PrivateIPAddress != nil {
+			privateIP = *frontendIP.PrivateIPAddress
 		}
-		if thisPathChunks[idx] != otherPathChunks[idx] {
-			return false
+	}
+
+	var normalized []Target
+	for _, target := range targets {
+		switch {
+		case target.IP == "" || target.IP == FrontendIPPublic || target.IP == FrontendIPPrivate:
+			// Nothing to resolve
+		case len(frontendIPs) == 0:
+			// Without the App Gateway frontends the address cannot be resolved; Match any frontend.
+			glog.V(5).Infof("[brownfield] Could not resolve IP %s of target; No frontend IP configurations", target.IP)
+			target.IP = ""
+		case target.IP == privateIP:
+			target.IP = FrontendIPPrivate
+		default:
+			target.IP = FrontendIPPublic
 		}
+		normalized = append(normalized, target)
 	}
-	return false
+	return normalized
+}
+
+// getTargetBlacklist returns the blacklist for the prohibited targets, with IPs resolved against the existing frontends.
+func (er ExistingResources) getTargetBlacklist() TargetBlacklist {
+	return NormalizeTargetBlacklist(GetTargetBlacklist(er.ProhibitedTargets), er.FrontendIPs)
+}
+
+// getTargetWhitelist returns the whitelist for the managed targets, with IPs resolved against the existing frontends.
+// A nil whitelist means there are no managed targets and AGIC is not limited to a subset of the App Gateway.
+func (er ExistingResources) getTargetWhitelist() TargetWhitelist {
+	if len(er.ManagedTargets) == 0 {
+		return nil
+	}
+	return NormalizeTargetWhitelist(GetTargetWhitelist(er.ManagedTargets), er.FrontendIPs)
 }
@@ -6,10 +6,13 @@
 package brownfield
 
 import (
-	v1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	v1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
 )
@@ -86,6 +89,12 @@ var _ = Describe("Test blacklisting targets", func() {
 			Expect(targetNonExistentPath.IsBlacklisted(&blacklist)).To(BeFalse())
 			Expect(targetNoHost.IsBlacklisted(&blacklist)).To(BeFalse())
 		})
+
+		It("Should find a Target whose paths are a superset of a blacklisted Target", func() {
+			Expect(Target{Hostname: tests.Host, Path: "/*"}.IsBlacklisted(&blacklist)).To(BeTrue())
+			Expect(Target{Hostname: tests.Host, Path: "/BAR/"}.IsBlacklisted(&blacklist)).To(BeTrue())
+			Expect(Target{Hostname: tests.Host, Path: "/ba/*"}.IsBlacklisted(&blacklist)).To(BeFalse())
+		})
 	})
 
 	Context("test TargetPath.contains(TargetPath)", func() {
@@ -122,6 +131,64 @@ var _ = Describe("Test blacklisting targets", func() {
 		})
 	})
 
+	Context("Test IsBlacklisted with frontend IP and port", func() {
+		blacklist := []Target{
+			{
+				Hostname: tests.Host,
+				IP:       FrontendIPPrivate,
+			},
+			{
+				Port: 8443,
+			},
+		}
+
+		It("should match the frontend IP and port of the target", func() {
+			Expect(Target{Hostname: tests.Host, IP: FrontendIPPrivate, Port: 80}.IsBlacklisted(&blacklist)).To(BeTrue())
+			Expect(Target{Hostname: tests.Host, IP: FrontendIPPublic, Port: 80}.IsBlacklisted(&blacklist)).To(BeFalse())
+			Expect(Target{Hostname: tests.OtherHost, IP: FrontendIPPublic, Port: 8443}.IsBlacklisted(&blacklist)).To(BeTrue())
+			Expect(Target{Hostname: tests.OtherHost, IP: FrontendIPPrivate, Port: 443}.IsBlacklisted(&blacklist)).To(BeFalse())
+		})
+
+		It("should consider a target with unknown frontend blacklisted", func() {
+			Expect(Target{Hostname: tests.Host, Port: 80}.IsBlacklisted(&blacklist)).To(BeTrue())
+			Expect(Target{Hostname: tests.OtherHost, IP: FrontendIPPublic}.IsBlacklisted(&blacklist)).To(BeTrue())
+		})
+	})
+
+	Context("Test NormalizeTargetBlacklist", func() {
+		prohibitedTargets := []*v1.AzureIngressProhibitedTarget{
+			{Spec: v1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host, IP: "Private"}},
+			{Spec: v1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host, IP: "10.0.0.4"}},
+			{Spec: v1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host, IP: "40.1.2.3", Port: 443}},
+			{Spec: v1.AzureIngressProhibitedTargetSpec{Hostname: tests.Host}},
+		}
+		frontendIPs := []n.ApplicationGatewayFrontendIPConfiguration{
+			fixtures.GetPublicIPConfiguration(),
+			{
+				ApplicationGatewayFrontendIPConfigurationPropertiesFormat: &n.ApplicationGatewayFrontendIPConfigurationPropertiesFormat{
+					PrivateIPAddress: to.StringPtr("10.0.0.4"),
+				},
+			},
+		}
+
+		It("should resolve IP addresses to the type of frontend", func() {
+			normalized := NormalizeTargetBlacklist(GetTargetBlacklist(prohibitedTargets), frontendIPs)
+			Expect(*normalized).To(Equal([]Target{
+				{Hostname: tests.Host, IP: FrontendIPPrivate},
+				{Hostname: tests.Host, IP: FrontendIPPrivate},
+				{Hostname: tests.Host, IP: FrontendIPPublic, Port: 443},
+				{Hostname: tests.Host},
+			}))
+		})
+
+		It("should match any frontend when IP addresses cannot be resolved", func() {
+			normalized := NormalizeTargetBlacklist(GetTargetBlacklist(prohibitedTargets), nil)
+			Expect((*normalized)[0].IP).To(Equal(FrontendIPPrivate))
+			Expect((*normalized)[1].IP).To(Equal(""))
+			Expect((*normalized)[2].IP).To(Equal(""))
+		})
+	})
+
 	Context("Test getProhibitedHostnames()", func() {
 		er := ExistingResources{
 			ProhibitedTargets: []*v1.AzureIngressProhibitedTarget{
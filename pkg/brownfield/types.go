@@ -6,6 +6,7 @@
 package brownfield
 
 import (
+	mtv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
 	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 )
@@ -30,7 +31,10 @@ type ExistingResources struct {
 	Ports              []n.ApplicationGatewayFrontendPort
 	Probes             []n.ApplicationGatewayProbe
 	Redirects          []n.ApplicationGatewayRedirectConfiguration
+	RewriteRuleSets    []n.ApplicationGatewayRewriteRuleSet
+	FrontendIPs        []n.ApplicationGatewayFrontendIPConfiguration
 	ProhibitedTargets  []*ptv1.AzureIngressProhibitedTarget
+	ManagedTargets     []*mtv1.AzureIngressManagedTarget
 	DefaultBackendPool *n.ApplicationGatewayBackendAddressPool
 
 	// Cache helper structs
@@ -39,7 +43,7 @@ type ExistingResources struct {
 }
 
 // NewExistingResources creates a new ExistingResources struct.
-func NewExistingResources(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget, defaultPool *n.ApplicationGatewayBackendAddressPool) ExistingResources {
+func NewExistingResources(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.AzureIngressProhibitedTarget, managedTargets []*mtv1.AzureIngressManagedTarget, defaultPool *n.ApplicationGatewayBackendAddressPool) ExistingResources {
 	var allExistingSettings []n.ApplicationGatewayBackendHTTPSettings
 	if appGw.BackendHTTPSettingsCollection != nil {
 		allExistingSettings = *appGw.BackendHTTPSettingsCollection
@@ -85,6 +89,16 @@ func NewExistingResources(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.
 		allExistingRedirects = *appGw.RedirectConfigurations
 	}
 
+	var allExistingRewriteRuleSets []n.ApplicationGatewayRewriteRuleSet
+	if appGw.RewriteRuleSets != nil {
+		allExistingRewriteRuleSets = *appGw.RewriteRuleSets
+	}
+
+	var allExistingFrontendIPs []n.ApplicationGatewayFrontendIPConfiguration
+	if appGw.FrontendIPConfigurations != nil {
+		allExistingFrontendIPs = *appGw.FrontendIPConfigurations
+	}
+
 	return ExistingResources{
 		BackendPools:       allExistingBackendPools,
 		Certificates:       allExistingCertificates,
@@ -95,7 +109,10 @@ func NewExistingResources(appGw n.ApplicationGateway, prohibitedTargets []*ptv1.
 		Ports:              allExistingPorts,
 		Probes:             allExistingHealthProbes,
 		Redirects:          allExistingRedirects,
+		RewriteRuleSets:    allExistingRewriteRuleSets,
+		FrontendIPs:        allExistingFrontendIPs,
 		ProhibitedTargets:  prohibitedTargets,
+		ManagedTargets:     managedTargets,
 		DefaultBackendPool: defaultPool,
 	}
 }
@@ -23,7 +23,7 @@ var _ = Describe("Test NewExistingResources", func() {
 			}
 			defaultPool := n.ApplicationGatewayBackendAddressPool{}
 
-			actual := NewExistingResources(appGw, prohibitedTargets, &defaultPool)
+			actual := NewExistingResources(appGw, prohibitedTargets, nil, &defaultPool)
 			expected := ExistingResources{
 				ProhibitedTargets:  prohibitedTargets,
 				DefaultBackendPool: &n.ApplicationGatewayBackendAddressPool{},
@@ -38,7 +38,7 @@ var _ = Describe("Test NewExistingResources", func() {
 				ApplicationGatewayPropertiesFormat: &n.ApplicationGatewayPropertiesFormat{},
 			}
 			defaultPool := n.ApplicationGatewayBackendAddressPool{}
-			er := NewExistingResources(appGw, prohibitedTargets, &defaultPool)
+			er := NewExistingResources(appGw, prohibitedTargets, nil, &defaultPool)
 			actual := er.getProhibitedHostnames()
 			expected := map[string]interface{}{
 				"bye.com":                 nil,
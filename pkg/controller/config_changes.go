@@ -0,0 +1,123 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+type changeType string
+
+const (
+	changeAdded    changeType = "added"
+	changeModified changeType = "modified"
+	changeRemoved  changeType = "removed"
+)
+
+// subResourceKeys are the JSON keys of the App Gateway sub-resource lists, which AGIC mutates.
+var subResourceKeys = []string{
+	"sslCertificates",
+	"frontendPorts",
+	"probes",
+	"backendAddressPools",
+	"backendHttpSettingsCollection",
+	"httpListeners",
+	"urlPathMaps",
+	"requestRoutingRules",
+	"redirectConfigurations",
+}
+
+// Keys, which differ between what ARM returns and what AGIC generates, without constituting a change.
+var keysToDeleteForChanges = []string{
+	"etag",
+	"provisioningState",
+	"type",
+}
+
+// configChange is a single App Gateway sub-resource, which a deployment would add, modify or remove.
+type configChange struct {
+	Type     changeType
+	Resource string
+	Name     string
+}
+
+func (cc configChange) String() string {
+	return fmt.Sprintf("%s %s %s", cc.Type, cc.Resource, cc.Name)
+}
+
+// getConfigChanges compares two App Gateway JSON configs and lists the sub-resources, which differ by name or content.
+func getConfigChanges(existingJSON, generatedJSON []byte) ([]configChange, error) {
+	existing, err := getSubResourcesByName(existingJSON)
+	if err != nil {
+		return nil, err
+	}
+	generated, err := getSubResourcesByName(generatedJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []configChange
+	for _, resource := range subResourceKeys {
+		for name, generatedResource := range generated[resource] {
+			existingResource, exists := existing[resource][name]
+			if !exists {
+				changes = append(changes, configChange{Type: changeAdded, Resource: resource, Name: name})
+			} else if !reflect.DeepEqual(existingResource, generatedResource) {
+				changes = append(changes, configChange{Type: changeModified, Resource: resource, Name: name})
+			}
+		}
+		for name := range existing[resource] {
+			if _, exists := generated[resource][name]; !exists {
+				changes = append(changes, configChange{Type: changeRemoved, Resource: resource, Name: name})
+			}
+		}
+	}
+
+	// Map iteration order is random; Sorting keeps the logs and the CRD status stable.
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Resource != changes[j].Resource {
+			return changes[i].Resource < changes[j].Resource
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	return changes, nil
+}
+
+// getSubResourcesByName indexes the sanitized sub-resources of an App Gateway JSON config by list key and name.
+func getSubResourcesByName(appGwJSON []byte) (map[string]map[string]interface{}, error) {
+	sanitized, err := deleteKeyFromJSON(appGwJSON, keysToDeleteForChanges...)
+	if err != nil {
+		return nil, err
+	}
+	var appGw struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(sanitized, &appGw); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]map[string]interface{})
+	for _, resource := range subResourceKeys {
+		byName[resource] = make(map[string]interface{})
+		blob, exists := appGw.Properties[resource]
+		if !exists {
+			continue
+		}
+		var list []map[string]interface{}
+		if err := json.Unmarshal(blob, &list); err != nil {
+			return nil, err
+		}
+		for _, subResource := range list {
+			if name, ok := subResource["name"].(string); ok {
+				byName[resource][name] = subResource
+			}
+		}
+	}
+	return byName, nil
+}
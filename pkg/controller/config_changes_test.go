@@ -0,0 +1,52 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("test getConfigChanges", func() {
+	existing := fixtures.GetAppGateway()
+	existingJSON, _ := existing.MarshalJSON()
+
+	Context("ensure identical configs have no changes", func() {
+		It("should return an empty list", func() {
+			generated := fixtures.GetAppGateway()
+			(*generated.HTTPListeners)[0].Etag = to.StringPtr("W/\"abc\"")
+			generatedJSON, _ := generated.MarshalJSON()
+			changes, err := getConfigChanges(existingJSON, generatedJSON)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(BeEmpty())
+		})
+	})
+
+	Context("ensure added, modified and removed sub-resources are listed", func() {
+		It("should return all three kinds of changes", func() {
+			generated := fixtures.GetAppGateway()
+			listeners := (*generated.HTTPListeners)[1:]
+			generated.HTTPListeners = &listeners
+			pools := []n.ApplicationGatewayBackendAddressPool{fixtures.GetBackendPool1()}
+			generated.BackendAddressPools = &pools
+			(*generated.Probes)[0].Path = to.StringPtr("/changed")
+			generatedJSON, _ := generated.MarshalJSON()
+
+			changes, err := getConfigChanges(existingJSON, generatedJSON)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changes).To(Equal([]configChange{
+				{Type: changeAdded, Resource: "backendAddressPools", Name: fixtures.BackendAddressPoolName1},
+				{Type: changeRemoved, Resource: "httpListeners", Name: fixtures.DefaultHTTPListenerName},
+				{Type: changeModified, Resource: "probes", Name: fixtures.ProbeName1},
+			}))
+			Expect(changes[1].String()).To(Equal("removed httpListeners fl-80"))
+		})
+	})
+})
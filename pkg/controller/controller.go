@@ -23,11 +23,16 @@ type AppGwIngressController struct {
 	appGwIdentifier appgw.Identifier
 	ipAddressMap    map[string]k8scontext.IPAddress
 
+	// isAdditionalAppGw is true when this controller only applies the Ingresses, which select its App Gateway by name.
+	isAdditionalAppGw bool
+
 	k8sContext *k8scontext.Context
 	worker     *worker.Worker
 
 	configCache *[]byte
 
+	ownership *ownershipStore
+
 	recorder record.EventRecorder
 
 	stopChannel chan struct{}
@@ -42,6 +47,7 @@ func NewAppGwIngressController(appGwClient n.ApplicationGatewaysClient, appGwIde
 		recorder:        recorder,
 		configCache:     to.ByteSlicePtr([]byte{}),
 		ipAddressMap:    map[string]k8scontext.IPAddress{},
+		ownership:       &ownershipStore{},
 		stopChannel:     make(chan struct{}),
 	}
 
@@ -0,0 +1,123 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure/tags"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/metrics"
+)
+
+// guardedResources are the App Gateway sub-resources the deletion guard protects from mass removal.
+var guardedResources = []string{
+	"httpListeners",
+	"requestRoutingRules",
+	"backendAddressPools",
+}
+
+// getMassDeletions describes each guarded resource type, of which the changes remove more than thresholdPercent of the existing ones.
+func getMassDeletions(existingJSON []byte, changes []configChange, thresholdPercent int) ([]string, error) {
+	existing, err := getSubResourcesByName(existingJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make(map[string]int)
+	for _, change := range changes {
+		if change.Type == changeRemoved {
+			removed[change.Resource]++
+		}
+	}
+
+	var massDeletions []string
+	for _, resource := range guardedResources {
+		total := len(existing[resource])
+		if total == 0 || removed[resource] == 0 {
+			continue
+		}
+		if removed[resource]*100 > thresholdPercent*total {
+			massDeletions = append(massDeletions, fmt.Sprintf("%d of %d %s", removed[resource], total, resource))
+		}
+	}
+	return massDeletions, nil
+}
+
+// isDeletionGuardOverridden looks for an Ingress annotated with the override annotation.
+func isDeletionGuardOverridden(ingressList []*v1beta1.Ingress) bool {
+	for _, ingress := range ingressList {
+		if overridden, _ := annotations.IsDeletionGuardOverridden(ingress); overridden {
+			glog.V(3).Infof("Deletion guard is overridden by Ingress %s/%s", ingress.Namespace, ingress.Name)
+			return true
+		}
+	}
+	return false
+}
+
+// getMassDeletionToken identifies the guarded sub-resources the changes remove; An operator confirms exactly this
+// deletion by setting the token as the value of tags.ConfirmMassDeletion.
+func getMassDeletionToken(changes []configChange) string {
+	var removed []string
+	for _, change := range changes {
+		if change.Type != changeRemoved {
+			continue
+		}
+		for _, resource := range guardedResources {
+			if change.Resource == resource {
+				removed = append(removed, change.Resource+"/"+change.Name)
+			}
+		}
+	}
+	sort.Strings(removed)
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(removed, ","))))[:10]
+}
+
+// isBlockedByDeletionGuard determines whether deploying the generated config would remove too many listeners, rules or pools.
+// An operator confirms a blocked deployment by tagging App Gateway with tags.ConfirmMassDeletion, set to the token of the
+// blocked deletion. A tag left over from an earlier deletion, or set before the deletion was blocked, does not match.
+func (c AppGwIngressController) isBlockedByDeletionGuard(generatedAppGw *n.ApplicationGateway, existingJSON []byte, changes []configChange, ingressList []*v1beta1.Ingress, threshold string) bool {
+	thresholdPercent, err := strconv.Atoi(threshold)
+	if err != nil {
+		glog.Errorf("Deletion guard threshold %q is not a number; Deletion guard is disabled", threshold)
+		return false
+	}
+
+	massDeletions, err := getMassDeletions(existingJSON, changes, thresholdPercent)
+	if err != nil {
+		glog.Error("Could not evaluate the deletion guard: ", err)
+		return false
+	}
+	if len(massDeletions) == 0 || isDeletionGuardOverridden(ingressList) {
+		return false
+	}
+
+	token := getMassDeletionToken(changes)
+	if confirmation := generatedAppGw.Tags[tags.ConfirmMassDeletion]; confirmation != nil && *confirmation == token {
+		glog.V(3).Infof("Mass deletion of %s is confirmed by App Gateway tag %s", strings.Join(massDeletions, ", "), tags.ConfirmMassDeletion)
+		return false
+	}
+
+	metrics.DeletionGuardBlockedDeployments.Add(1)
+	errorLine := fmt.Sprintf("refusing to update App Gateway %s as it would remove %s, which is more than the %d%% allowed by %s; Annotate an Ingress with %s: \"true\" or tag App Gateway with %s=%s to proceed",
+		c.appGwIdentifier.AppGwName, strings.Join(massDeletions, ", "), thresholdPercent, environment.DeletionGuardThresholdVarName, annotations.OverrideDeletionGuardKey, tags.ConfirmMassDeletion, token)
+	glog.Error(errorLine)
+	for _, ingress := range ingressList {
+		c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonMassDeletionBlocked, errorLine)
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/api/extensions/v1beta1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure/tags"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("test deletion guard", func() {
+	var controller *AppGwIngressController
+	var recorder *record.FakeRecorder
+	var generated n.ApplicationGateway
+	var ingressList []*v1beta1.Ingress
+
+	existing := fixtures.GetAppGateway()
+	existingJSON, _ := existing.MarshalJSON()
+
+	// Removes 3 of the 5 listeners in the fixture.
+	changes := []configChange{
+		{Type: changeRemoved, Resource: "httpListeners", Name: fixtures.DefaultHTTPListenerName},
+		{Type: changeRemoved, Resource: "httpListeners", Name: fixtures.HTTPListenerNameBasic},
+		{Type: changeRemoved, Resource: "httpListeners", Name: fixtures.HTTPListenerUnassociated},
+		{Type: changeAdded, Resource: "backendAddressPools", Name: fixtures.BackendAddressPoolName1},
+	}
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(100)
+		controller = &AppGwIngressController{recorder: recorder}
+		generated = fixtures.GetAppGateway()
+		ingressList = []*v1beta1.Ingress{tests.NewIngressFixture()}
+	})
+
+	Context("ensure getMassDeletions compares against the threshold", func() {
+		It("should report listeners above the threshold", func() {
+			massDeletions, err := getMassDeletions(existingJSON, changes, 50)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(massDeletions).To(Equal([]string{"3 of 5 httpListeners"}))
+		})
+
+		It("should not report listeners at or below the threshold", func() {
+			massDeletions, err := getMassDeletions(existingJSON, changes, 60)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(massDeletions).To(BeEmpty())
+		})
+	})
+
+	Context("ensure isBlockedByDeletionGuard blocks mass deletions", func() {
+		It("should block and emit an event", func() {
+			Expect(controller.isBlockedByDeletionGuard(&generated, existingJSON, changes, ingressList, "50")).To(BeTrue())
+			Expect(len(recorder.Events)).To(Equal(1))
+		})
+
+		It("should let the deployment through when an Ingress overrides the guard", func() {
+			ingressList[0].Annotations[annotations.OverrideDeletionGuardKey] = "true"
+			Expect(controller.isBlockedByDeletionGuard(&generated, existingJSON, changes, ingressList, "50")).To(BeFalse())
+			Expect(len(recorder.Events)).To(Equal(0))
+		})
+
+		It("should let the deployment through when the confirmation tag holds the token of the blocked deletion", func() {
+			Expect(controller.isBlockedByDeletionGuard(&generated, existingJSON, changes, ingressList, "50")).To(BeTrue())
+			event := <-recorder.Events
+			token := getMassDeletionToken(changes)
+			Expect(event).To(ContainSubstring(tags.ConfirmMassDeletion + "=" + token))
+
+			generated.Tags = map[string]*string{tags.ConfirmMassDeletion: to.StringPtr(token)}
+			Expect(controller.isBlockedByDeletionGuard(&generated, existingJSON, changes, ingressList, "50")).To(BeFalse())
+		})
+
+		It("should ignore a confirmation tag of another deletion", func() {
+			generated.Tags = map[string]*string{tags.ConfirmMassDeletion: to.StringPtr("true")}
+			Expect(controller.isBlockedByDeletionGuard(&generated, existingJSON, changes, ingressList, "50")).To(BeTrue())
+
+			otherToken := getMassDeletionToken(changes[1:])
+			Expect(otherToken).ToNot(Equal(getMassDeletionToken(changes)))
+			generated.Tags = map[string]*string{tags.ConfirmMassDeletion: to.StringPtr(otherToken)}
+			Expect(controller.isBlockedByDeletionGuard(&generated, existingJSON, changes, ingressList, "50")).To(BeTrue())
+		})
+	})
+})
@@ -10,4 +10,6 @@ import "errors"
 var (
 	ErrFetchingAppGatewayConfig  = errors.New("unable to get specified AppGateway")
 	ErrDeployingAppGatewayConfig = errors.New("unable to deploy App Gateway config")
+	ErrDeletionGuard             = errors.New("deletion guard refused to deploy App Gateway config")
+	ErrAppGatewayChanged         = errors.New("App Gateway was changed since its config was read")
 )
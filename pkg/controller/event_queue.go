@@ -0,0 +1,103 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+)
+
+// eventQueue holds the events a controller has yet to process without ever blocking the sender. Every event makes the
+// controller reprocess the whole cluster, so only the latest event of every object is kept; The queue is bounded by
+// the number of objects, and a controller busy deploying does not hold back the others.
+type eventQueue struct {
+	mutex   sync.Mutex
+	keys    []string
+	pending map[string]events.Event
+	ready   chan struct{}
+
+	// unkeyed counts the events without an object to key them by, which are never coalesced.
+	unkeyed int
+}
+
+func newEventQueue() *eventQueue {
+	return &eventQueue{
+		pending: make(map[string]events.Event),
+		ready:   make(chan struct{}, 1),
+	}
+}
+
+// push queues the event, replacing the pending event of the same object.
+func (q *eventQueue) push(event events.Event) {
+	q.mutex.Lock()
+	key, ok := getEventKey(event)
+	if !ok {
+		q.unkeyed++
+		key = fmt.Sprintf("unkeyed/%d", q.unkeyed)
+	}
+	if _, exists := q.pending[key]; !exists {
+		q.keys = append(q.keys, key)
+	}
+	q.pending[key] = event
+	q.mutex.Unlock()
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// pop takes the oldest pending event from the queue.
+func (q *eventQueue) pop() (events.Event, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if len(q.keys) == 0 {
+		return events.Event{}, false
+	}
+	key := q.keys[0]
+	q.keys = q.keys[1:]
+	event := q.pending[key]
+	delete(q.pending, key)
+	return event, true
+}
+
+// forward delivers the queued events to the work channel of a worker until stopChannel is closed.
+func (q *eventQueue) forward(work chan events.Event, stopChannel chan struct{}) {
+	for {
+		event, ok := q.pop()
+		if !ok {
+			select {
+			case <-q.ready:
+				continue
+			case <-stopChannel:
+				return
+			}
+		}
+		select {
+		case work <- event:
+		case <-stopChannel:
+			return
+		}
+	}
+}
+
+// getEventKey identifies the object of the event by its type, namespace and name.
+func getEventKey(event events.Event) (string, bool) {
+	obj := event.Value
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%T/%s/%s", obj, accessor.GetNamespace(), accessor.GetName()), true
+}
@@ -0,0 +1,58 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("test eventQueue", func() {
+	newPod := func(name string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: tests.Namespace, Name: name}}
+	}
+
+	It("should keep the latest event of every object, in order", func() {
+		queue := newEventQueue()
+		queue.push(events.Event{Type: events.Create, Value: newPod("one")})
+		queue.push(events.Event{Type: events.Create, Value: newPod("two")})
+		queue.push(events.Event{Type: events.Delete, Value: cache.DeletedFinalStateUnknown{Obj: newPod("one")}})
+		queue.push(events.Event{Type: events.Create, Value: "not an object"})
+		queue.push(events.Event{Type: events.Create, Value: "not an object"})
+
+		var types []events.EventType
+		for event, ok := queue.pop(); ok; event, ok = queue.pop() {
+			types = append(types, event.Type)
+		}
+		Expect(types).To(Equal([]events.EventType{events.Delete, events.Create, events.Create, events.Create}))
+	})
+
+	It("should not block the sender while the worker is busy", func() {
+		queue := newEventQueue()
+		work := make(chan events.Event)
+		stopChannel := make(chan struct{})
+		defer close(stopChannel)
+		go queue.forward(work, stopChannel)
+
+		pushed := make(chan interface{})
+		go func() {
+			for i := 0; i < 10000; i++ {
+				queue.push(events.Event{Type: events.Update, Value: newPod("pod")})
+			}
+			close(pushed)
+		}()
+		Eventually(pushed, 2*time.Second).Should(BeClosed())
+		Eventually(work).Should(Receive())
+	})
+})
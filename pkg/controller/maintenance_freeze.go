@@ -0,0 +1,59 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+
+	freezev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmaintenancefreeze/v1"
+)
+
+// isFreezeInEffect determines whether the given freeze withholds deployments at the given time.
+func isFreezeInEffect(freeze *freezev1.AzureIngressMaintenanceFreeze, now time.Time) bool {
+	return freeze.Spec.Expiry == nil || now.Before(freeze.Spec.Expiry.Time)
+}
+
+// applyMaintenanceFreezes records the pending changes on every maintenance freeze in effect and
+// returns true when the App Gateway deployment must be skipped. Expired freezes are marked as no longer frozen.
+// The freezes are shared by all App Gateways, so only the controller of the default App Gateway writes their status;
+// The others log their pending changes.
+func (c AppGwIngressController) applyMaintenanceFreezes(changes []configChange, now time.Time) bool {
+	var pendingChanges []string
+	for _, change := range changes {
+		pendingChanges = append(pendingChanges, change.String())
+	}
+
+	var freezesInEffect []string
+	for _, freeze := range c.k8sContext.ListAzureIngressMaintenanceFreezes() {
+		status := freezev1.AzureIngressMaintenanceFreezeStatus{}
+		if isFreezeInEffect(freeze, now) {
+			freezesInEffect = append(freezesInEffect, freeze.Name)
+			status.Frozen = true
+			status.PendingChanges = pendingChanges
+		} else if freeze.Status.Frozen {
+			glog.V(3).Infof("Maintenance freeze %s expired at %s", freeze.Name, freeze.Spec.Expiry.Time.String())
+		}
+
+		if c.isAdditionalAppGw || reflect.DeepEqual(freeze.Status, status) {
+			continue
+		}
+		updated := freeze.DeepCopy()
+		updated.Status = status
+		_ = c.k8sContext.UpdateAzureIngressMaintenanceFreezeStatus(updated)
+	}
+
+	if len(freezesInEffect) == 0 {
+		return false
+	}
+
+	glog.Warningf("Maintenance freeze in effect (%s); App Gateway %s will not be updated. Pending changes: [%s]",
+		strings.Join(freezesInEffect, ","), c.appGwIdentifier.AppGwName, strings.Join(pendingChanges, ", "))
+	return true
+}
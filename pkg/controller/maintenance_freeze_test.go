@@ -0,0 +1,100 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	freezev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmaintenancefreeze/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+)
+
+var _ = Describe("test maintenance freeze", func() {
+	var controller *AppGwIngressController
+	var crdClient *fake.Clientset
+	now := time.Now()
+	changes := []configChange{
+		{Type: changeRemoved, Resource: "httpListeners", Name: "fl-80"},
+	}
+
+	addFreeze := func(name string, expiry *metav1.Time, status freezev1.AzureIngressMaintenanceFreezeStatus) {
+		freeze := &freezev1.AzureIngressMaintenanceFreeze{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       freezev1.AzureIngressMaintenanceFreezeSpec{Expiry: expiry},
+			Status:     status,
+		}
+		_, err := crdClient.AzureingressmaintenancefreezesV1().AzureIngressMaintenanceFreezes().Create(freeze)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(controller.k8sContext.Caches.AzureIngressMaintenanceFreeze.Add(freeze)).To(Succeed())
+	}
+
+	getStatus := func(name string) freezev1.AzureIngressMaintenanceFreezeStatus {
+		freeze, err := crdClient.AzureingressmaintenancefreezesV1().AzureIngressMaintenanceFreezes().Get(name, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return freeze.Status
+	}
+
+	BeforeEach(func() {
+		crdClient = fake.NewSimpleClientset()
+		ctxt := k8scontext.NewContext(testclient.NewSimpleClientset(), crdClient, istio_fake.NewSimpleClientset(), []string{tests.Namespace}, 1000*time.Second)
+		controller = &AppGwIngressController{k8sContext: ctxt}
+	})
+
+	Context("without any freezes", func() {
+		It("should not skip the deployment", func() {
+			Expect(controller.applyMaintenanceFreezes(changes, now)).To(BeFalse())
+		})
+	})
+
+	Context("with a freeze without expiry", func() {
+		It("should skip the deployment and record the pending changes", func() {
+			addFreeze("freeze", nil, freezev1.AzureIngressMaintenanceFreezeStatus{})
+			Expect(controller.applyMaintenanceFreezes(changes, now)).To(BeTrue())
+			Expect(getStatus("freeze")).To(Equal(freezev1.AzureIngressMaintenanceFreezeStatus{
+				Frozen:         true,
+				PendingChanges: []string{"removed httpListeners fl-80"},
+			}))
+		})
+	})
+
+	Context("with an expired freeze", func() {
+		It("should not skip the deployment and clear the status", func() {
+			expiry := metav1.NewTime(now.Add(-1 * time.Minute))
+			addFreeze("expired", &expiry, freezev1.AzureIngressMaintenanceFreezeStatus{
+				Frozen:         true,
+				PendingChanges: []string{"removed httpListeners fl-80"},
+			})
+			Expect(controller.applyMaintenanceFreezes(changes, now)).To(BeFalse())
+			Expect(getStatus("expired")).To(Equal(freezev1.AzureIngressMaintenanceFreezeStatus{}))
+		})
+	})
+
+	Context("with a freeze expiring in the future", func() {
+		It("should skip the deployment", func() {
+			expiry := metav1.NewTime(now.Add(time.Hour))
+			addFreeze("future", &expiry, freezev1.AzureIngressMaintenanceFreezeStatus{})
+			Expect(controller.applyMaintenanceFreezes(changes, now)).To(BeTrue())
+			Expect(getStatus("future").Frozen).To(BeTrue())
+		})
+	})
+
+	Context("with several App Gateways", func() {
+		It("should skip the deployment of an additional App Gateway without writing the status", func() {
+			controller.isAdditionalAppGw = true
+			addFreeze("freeze", nil, freezev1.AzureIngressMaintenanceFreezeStatus{})
+			Expect(controller.applyMaintenanceFreezes(changes, now)).To(BeTrue())
+			Expect(getStatus("freeze")).To(Equal(freezev1.AzureIngressMaintenanceFreezeStatus{}))
+		})
+	})
+})
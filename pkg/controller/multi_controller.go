@@ -0,0 +1,140 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/extensions/v1beta1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+)
+
+// MultiAppGwIngressController runs one AppGwIngressController per App Gateway on top of a single k8scontext.
+// Every controller has its own worker, event queue, cache and builder context; Kubernetes events are delivered to all of them.
+type MultiAppGwIngressController struct {
+	k8sContext  *k8scontext.Context
+	controllers []*AppGwIngressController
+
+	stopChannel chan struct{}
+}
+
+// NewMultiAppGwIngressController constructs a controller managing the App Gateways of the given controllers.
+// The first controller manages the default App Gateway, to which Ingresses not selecting an App Gateway are applied.
+func NewMultiAppGwIngressController(k8sContext *k8scontext.Context, controllers ...*AppGwIngressController) *MultiAppGwIngressController {
+	for idx, controller := range controllers {
+		controller.isAdditionalAppGw = idx > 0
+	}
+	return &MultiAppGwIngressController{
+		k8sContext:  k8sContext,
+		controllers: controllers,
+		stopChannel: make(chan struct{}),
+	}
+}
+
+// Start runs the k8scontext once, starts a worker per App Gateway and fans the k8scontext events out to them.
+func (m *MultiAppGwIngressController) Start(envVariables environment.EnvVariables) error {
+	if err := m.k8sContext.Run(m.stopChannel, false, envVariables); err != nil {
+		glog.Error("Could not start Kubernetes Context: ", err)
+		return err
+	}
+
+	var queues []*eventQueue
+	for _, controller := range m.controllers {
+		queue := newEventQueue()
+		queues = append(queues, queue)
+		work := make(chan events.Event)
+		glog.V(3).Infof("Starting worker for App Gateway %s", controller.appGwIdentifier.AppGwName)
+		go queue.forward(work, m.stopChannel)
+		go controller.worker.Run(work, m.stopChannel)
+	}
+
+	go func() {
+		for {
+			select {
+			case event := <-m.k8sContext.Work:
+				m.reportUnmanagedAppGw(event)
+				for _, queue := range queues {
+					queue.push(event)
+				}
+			case <-m.stopChannel:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop signals the k8scontext, the workers and the event fan-out to stop.
+func (m *MultiAppGwIngressController) Stop() {
+	close(m.stopChannel)
+}
+
+// Liveness fulfills the health.HealthProbe interface.
+func (m *MultiAppGwIngressController) Liveness() bool {
+	for _, controller := range m.controllers {
+		if !controller.Liveness() {
+			return false
+		}
+	}
+	return true
+}
+
+// Readiness fulfills the health.HealthProbe interface; All controllers share the caches of the k8scontext.
+func (m *MultiAppGwIngressController) Readiness() bool {
+	_, isOpen := <-m.k8sContext.CacheSynced
+	return !isOpen
+}
+
+// reportUnmanagedAppGw emits an event on an Ingress, which selects an App Gateway none of the controllers manages; No
+// App Gateway would apply it otherwise without a word.
+func (m *MultiAppGwIngressController) reportUnmanagedAppGw(event events.Event) {
+	ingress, ok := event.Value.(*v1beta1.Ingress)
+	if !ok || event.Type == events.Delete || !k8scontext.IsIngressApplicationGateway(ingress) {
+		return
+	}
+	appGwName, err := annotations.AppGwName(ingress)
+	if err != nil {
+		return
+	}
+	var managedAppGws []string
+	for _, controller := range m.controllers {
+		if controller.isIngressForThisAppGw(ingress) {
+			return
+		}
+		managedAppGws = append(managedAppGws, controller.appGwIdentifier.AppGwName)
+	}
+
+	logLine := fmt.Sprintf("Ingress %s/%s selects App Gateway %s, which AGIC does not manage; AGIC manages %s", ingress.Namespace, ingress.Name, appGwName, strings.Join(managedAppGws, ", "))
+	glog.Warning(logLine)
+	m.controllers[0].recorder.Event(ingress, v1.EventTypeWarning, events.ReasonUnmanagedAppGw, logLine)
+}
+
+// isIngressForThisAppGw determines whether the Ingress is to be applied to the App Gateway managed by this controller.
+func (c AppGwIngressController) isIngressForThisAppGw(ingress *v1beta1.Ingress) bool {
+	appGwName, err := annotations.AppGwName(ingress)
+	if err != nil {
+		return !c.isAdditionalAppGw
+	}
+	return strings.EqualFold(appGwName, c.appGwIdentifier.AppGwName)
+}
+
+// listHTTPIngressesForThisAppGw returns the HTTP Ingresses, which are to be applied to the App Gateway managed by this controller.
+func (c AppGwIngressController) listHTTPIngressesForThisAppGw() []*v1beta1.Ingress {
+	var ingressList []*v1beta1.Ingress
+	for _, ingress := range c.k8sContext.ListHTTPIngresses() {
+		if c.isIngressForThisAppGw(ingress) {
+			ingressList = append(ingressList, ingress)
+		}
+	}
+	return ingressList
+}
@@ -0,0 +1,101 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/worker"
+)
+
+var _ = Describe("test MultiAppGwIngressController", func() {
+	var ctxt *k8scontext.Context
+	var first, second *AppGwIngressController
+	var multi *MultiAppGwIngressController
+
+	BeforeEach(func() {
+		ctxt = k8scontext.NewContext(testclient.NewSimpleClientset(), fake.NewSimpleClientset(), istio_fake.NewSimpleClientset(), []string{tests.Namespace}, 1000*time.Second)
+		first = &AppGwIngressController{appGwIdentifier: appgw.Identifier{AppGwName: "appgw-one"}, k8sContext: ctxt, recorder: record.NewFakeRecorder(100)}
+		second = &AppGwIngressController{appGwIdentifier: appgw.Identifier{AppGwName: "appgw-two"}, k8sContext: ctxt}
+		multi = NewMultiAppGwIngressController(ctxt, first, second)
+	})
+
+	Context("ensure Ingresses are matched to App Gateways", func() {
+		It("should apply Ingresses without a selection to the first App Gateway only", func() {
+			ingress := tests.NewIngressFixture()
+			Expect(first.isIngressForThisAppGw(ingress)).To(BeTrue())
+			Expect(second.isIngressForThisAppGw(ingress)).To(BeFalse())
+		})
+
+		It("should apply Ingresses to the App Gateway they select", func() {
+			ingress := tests.NewIngressFixture()
+			ingress.Annotations[annotations.AppGwNameKey] = "APPGW-TWO"
+			Expect(first.isIngressForThisAppGw(ingress)).To(BeFalse())
+			Expect(second.isIngressForThisAppGw(ingress)).To(BeTrue())
+		})
+	})
+
+	Context("ensure events are delivered to every App Gateway", func() {
+		It("should run Process on all controllers", func() {
+			processed := make(chan string, 2)
+			for _, controller := range []*AppGwIngressController{first, second} {
+				appGwName := controller.appGwIdentifier.AppGwName
+				controller.worker = &worker.Worker{
+					EventProcessor: worker.NewFakeProcessor(func(events.Event) error {
+						processed <- appGwName
+						return nil
+					}),
+				}
+			}
+
+			Expect(multi.Start(environment.GetEnv())).To(Succeed())
+			defer multi.Stop()
+
+			ctxt.Work <- events.Event{Type: events.Create, Value: tests.NewIngressFixture()}
+
+			var received []string
+			for len(received) < 2 {
+				select {
+				case appGwName := <-processed:
+					received = append(received, appGwName)
+				case <-time.After(2 * time.Second):
+					Fail("event was not delivered to all controllers")
+				}
+			}
+			Expect(received).To(ConsistOf("appgw-one", "appgw-two"))
+		})
+	})
+
+	Context("ensure Ingresses selecting an unmanaged App Gateway are reported", func() {
+		It("should emit an event on the Ingress", func() {
+			recorder := first.recorder.(*record.FakeRecorder)
+			ingress := tests.NewIngressFixture()
+			multi.reportUnmanagedAppGw(events.Event{Type: events.Create, Value: ingress})
+			Expect(recorder.Events).To(BeEmpty())
+
+			ingress.Annotations[annotations.AppGwNameKey] = "appgw-three"
+			multi.reportUnmanagedAppGw(events.Event{Type: events.Create, Value: ingress})
+			Expect(recorder.Events).To(HaveLen(1))
+			event := <-recorder.Events
+			Expect(event).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonUnmanagedAppGw))
+			Expect(event).To(ContainSubstring("appgw-three"))
+		})
+	})
+})
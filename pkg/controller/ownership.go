@@ -0,0 +1,54 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"sync"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+)
+
+// ownershipStore holds the ownership report of the last config AGIC built; Process runs on a copy of the
+// controller, so the report is kept behind a pointer shared by all copies.
+type ownershipStore struct {
+	sync.RWMutex
+	report *brownfield.OwnershipReport
+}
+
+func (s *ownershipStore) set(report *brownfield.OwnershipReport) {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.report = report
+}
+
+func (s *ownershipStore) get() *brownfield.OwnershipReport {
+	if s == nil {
+		return nil
+	}
+	s.RLock()
+	defer s.RUnlock()
+	return s.report
+}
+
+// OwnershipReport returns the brownfield ownership report of the App Gateway; It is nil until AGIC has built
+// config with brownfield deployment enabled.
+func (c *AppGwIngressController) OwnershipReport() *brownfield.OwnershipReport {
+	return c.ownership.get()
+}
+
+// OwnershipReports fulfills the health.OwnershipReporter interface.
+func (m *MultiAppGwIngressController) OwnershipReports() []brownfield.OwnershipReport {
+	reports := []brownfield.OwnershipReport{}
+	for _, controller := range m.controllers {
+		if report := controller.OwnershipReport(); report != nil {
+			reports = append(reports, *report)
+		}
+	}
+	return reports
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -21,15 +22,29 @@ import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure/tags"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/environment"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
 )
 
+// maxAppGwChangedRetries is how often Process reads and updates the App Gateway again, when it was changed by
+// another writer while AGIC built its config.
+const maxAppGwChangedRetries = 3
+
 // Process is the callback function that will be executed for every event
 // in the EventQueue.
 func (c AppGwIngressController) Process(event events.Event) error {
+	err := c.process(event)
+	for retry := 0; err == ErrAppGatewayChanged && retry < maxAppGwChangedRetries; retry++ {
+		glog.Warningf("App Gateway %s was changed since AGIC read its config; Reading it again", c.appGwIdentifier.AppGwName)
+		err = c.process(event)
+	}
+	return err
+}
+
+func (c AppGwIngressController) process(event events.Event) error {
 	ctx := context.Background()
 
 	// Get current application gateway config
@@ -44,9 +59,15 @@ func (c AppGwIngressController) Process(event events.Event) error {
 	existingConfigJSON, _ := dumpSanitizedJSON(&appGw, false, to.StringPtr("-- Existing App Gwy Config --"))
 	glog.V(5).Info("Existing App Gateway config: ", string(existingConfigJSON))
 
+	// The config builder mutates appGw; Keep the original to be able to tell what a deployment would change.
+	existingAppGwJSON, err := appGw.MarshalJSON()
+	if err != nil {
+		glog.Error("Could not marshal existing App Gwy config: ", err)
+	}
+
 	cbCtx := &appgw.ConfigBuilderContext{
 		ServiceList:  c.k8sContext.ListServices(),
-		IngressList:  c.k8sContext.ListHTTPIngresses(),
+		IngressList:  c.listHTTPIngressesForThisAppGw(),
 		EnvVariables: environment.GetEnv(),
 
 		DefaultAddressPoolID:  to.StringPtr(c.appGwIdentifier.AddressPoolID(appgw.DefaultBackendAddressPoolName)),
@@ -63,8 +84,28 @@ func (c AppGwIngressController) Process(event events.Event) error {
 				prohibitedTargetsList = append(prohibitedTargetsList, string(targetJSON))
 			}
 			glog.V(3).Infof("[brownfield] Prohibited targets: %s", strings.Join(prohibitedTargetsList, ", "))
-		} else {
-			glog.Warning("Brownfield Deployment is enabled, but AGIC did not find any AzureProhibitedTarget CRDs; Disabling brownfield deployment feature.")
+
+			// The config builder mutates appGw; The statuses reflect the config as it was before this sync. The targets
+			// are shared by all App Gateways, so only the controller of the default App Gateway writes their status.
+			if !c.isAdditionalAppGw {
+				statuses := getProhibitedTargetStatuses(appGw, prohibitedTargets, time.Now())
+				defer c.updateProhibitedTargetStatuses(prohibitedTargets, statuses)
+			}
+		}
+
+		managedTargets := c.k8sContext.ListAzureIngressManagedTargets()
+		if len(managedTargets) > 0 {
+			cbCtx.ManagedTargets = managedTargets
+			var managedTargetsList []string
+			for _, target := range *brownfield.GetTargetWhitelist(managedTargets) {
+				targetJSON, _ := json.Marshal(target)
+				managedTargetsList = append(managedTargetsList, string(targetJSON))
+			}
+			glog.V(3).Infof("[brownfield] Managed targets: %s", strings.Join(managedTargetsList, ", "))
+		}
+
+		if len(prohibitedTargets) == 0 && len(managedTargets) == 0 {
+			glog.Warning("Brownfield Deployment is enabled, but AGIC did not find any AzureProhibitedTarget or AzureIngressManagedTarget CRDs; Disabling brownfield deployment feature.")
 			cbCtx.EnvVariables.EnableBrownfieldDeployment = false
 		}
 	}
@@ -75,6 +116,7 @@ func (c AppGwIngressController) Process(event events.Event) error {
 		if len(istioGateways) > 0 && len(istioServices) > 0 {
 			cbCtx.IstioGateways = istioGateways
 			cbCtx.IstioVirtualServices = istioServices
+			cbCtx.IstioDestinationRules = c.k8sContext.ListIstioDestinationRules()
 		} else {
 			glog.Warning("Istio Integration is enabled, but AGIC needs Istio Gateways and Virtual Services; Disabling Istio integration.")
 			cbCtx.EnvVariables.EnableIstioIntegration = false
@@ -117,11 +159,37 @@ func (c AppGwIngressController) Process(event events.Event) error {
 		return err
 	}
 
+	if report := configBuilder.OwnershipReport(); report != nil {
+		report.AppGateway = c.appGwIdentifier.AppGwName
+		c.ownership.set(report)
+	}
+
 	// Run post validations to report errors in the config generation.
 	if err = configBuilder.PostBuildValidate(cbCtx); err != nil {
 		glog.Error("ConfigBuilder PostBuildValidate returned error:", err)
 	}
 
+	guardDeletions := cbCtx.EnvVariables.DeletionGuardThreshold != ""
+	var changes []configChange
+	if cbCtx.EnvVariables.EnableMaintenanceFreeze || guardDeletions {
+		if generatedAppGwJSON, err := generatedAppGw.MarshalJSON(); err == nil && existingAppGwJSON != nil {
+			if changes, err = getConfigChanges(existingAppGwJSON, generatedAppGwJSON); err != nil {
+				glog.Error("Could not determine pending App Gwy changes: ", err)
+			}
+		}
+	}
+
+	if cbCtx.EnvVariables.EnableMaintenanceFreeze && c.applyMaintenanceFreezes(changes, time.Now()) {
+		return nil
+	}
+
+	if guardDeletions && c.isBlockedByDeletionGuard(generatedAppGw, existingAppGwJSON, changes, cbCtx.IngressList, cbCtx.EnvVariables.DeletionGuardThreshold) {
+		return ErrDeletionGuard
+	}
+
+	// A confirmation of the deletion guard applies to a single deployment; It is never written back, even when unused.
+	delete(generatedAppGw.Tags, tags.ConfirmMassDeletion)
+
 	if c.configIsSame(&appGw) {
 		// update ingresses with appgw gateway ip address
 		c.updateIngressStatus(generatedAppGw, cbCtx, event)
@@ -135,10 +203,20 @@ func (c AppGwIngressController) Process(event events.Event) error {
 
 	deploymentStart := time.Now()
 	// Initiate deployment
-	appGwFuture, err := c.appGwClient.CreateOrUpdate(ctx, c.appGwIdentifier.ResourceGroup, c.appGwIdentifier.AppGwName, *generatedAppGw)
+	// Clusters sharing the App Gateway read, modify and write the same config; The update must not overwrite the
+	// ownership record and pool addresses another cluster wrote after the config was read.
+	var etag *string
+	if cbCtx.EnvVariables.ClusterID != "" {
+		etag = appGw.Etag
+	}
+	appGwFuture, err := c.putAppGw(ctx, *generatedAppGw, etag)
 	if err != nil {
 		// Reset cache
 		c.configCache = nil
+		if response := appGwFuture.Response(); response != nil && response.StatusCode == http.StatusPreconditionFailed {
+			glog.Warning("App Gwy config was changed by another writer during the update: ", err)
+			return ErrAppGatewayChanged
+		}
 		configJSON, _ := dumpSanitizedJSON(&appGw, cbCtx.EnvVariables.EnableSaveConfigToFile, nil)
 		glogIt := glog.Errorf
 		if cbCtx.EnvVariables.EnablePanicOnPutError {
@@ -171,12 +249,31 @@ func (c AppGwIngressController) Process(event events.Event) error {
 	return nil
 }
 
+// putAppGw starts the deployment of the App Gateway config. With an etag the request carries If-Match, and ARM
+// refuses it with 412 Precondition Failed when the App Gateway changed since it was read.
+func (c AppGwIngressController) putAppGw(ctx context.Context, appGw n.ApplicationGateway, etag *string) (n.ApplicationGatewaysCreateOrUpdateFuture, error) {
+	if etag == nil {
+		return c.appGwClient.CreateOrUpdate(ctx, c.appGwIdentifier.ResourceGroup, c.appGwIdentifier.AppGwName, appGw)
+	}
+	request, err := c.appGwClient.CreateOrUpdatePreparer(ctx, c.appGwIdentifier.ResourceGroup, c.appGwIdentifier.AppGwName, appGw)
+	if err != nil {
+		return n.ApplicationGatewaysCreateOrUpdateFuture{}, err
+	}
+	request.Header.Set("If-Match", *etag)
+	return c.appGwClient.CreateOrUpdateSender(request)
+}
+
 func (c AppGwIngressController) updateIngressStatus(appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, event events.Event) {
 	ingress, ok := event.Value.(*v1beta1.Ingress)
 	if !ok {
 		return
 	}
 
+	// the status of an ingress applied to another App Gateway is updated by the controller of that App Gateway
+	if k8scontext.IsIngressApplicationGateway(ingress) && !c.isIngressForThisAppGw(ingress) {
+		return
+	}
+
 	// check if this ingress is for AGIC or not, it might have been updated
 	if !k8scontext.IsIngressApplicationGateway(ingress) || !cbCtx.InIngressList(ingress) {
 		if err := c.k8sContext.UpdateIngressStatus(*ingress, ""); err != nil {
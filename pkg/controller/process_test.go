@@ -6,9 +6,13 @@
 package controller
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"time"
 
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/Azure/go-autorest/autorest/to"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
@@ -127,4 +131,43 @@ var _ = Describe("process function tests", func() {
 			Expect(len(updatedIngress.Status.LoadBalancer.Ingress)).To(Equal(1))
 		})
 	})
+
+	Context("test putAppGw", func() {
+		var server *httptest.Server
+		var ifMatch []string
+		BeforeEach(func() {
+			ifMatch = nil
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ifMatch = append(ifMatch, r.Header.Get("If-Match"))
+				w.Header().Set("Content-Type", "application/json")
+				if match := r.Header.Get("If-Match"); match != "" && match != "etag-2" {
+					w.WriteHeader(http.StatusPreconditionFailed)
+					_, _ = w.Write([]byte(`{"error":{"code":"PreconditionFailed","message":"etag mismatch"}}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"properties":{"provisioningState":"Succeeded"}}`))
+			}))
+			controller.appGwClient = n.NewApplicationGatewaysClientWithBaseURI(server.URL, "subscription")
+			controller.appGwIdentifier = appgw.Identifier{SubscriptionID: "subscription", ResourceGroup: "group", AppGwName: "appgw"}
+		})
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("sends no If-Match without an etag", func() {
+			_, err := controller.putAppGw(context.Background(), appGw, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ifMatch).To(Equal([]string{""}))
+		})
+
+		It("fails with 412 when the App Gateway changed since it was read", func() {
+			future, err := controller.putAppGw(context.Background(), appGw, to.StringPtr("etag-1"))
+			Expect(err).To(HaveOccurred())
+			Expect(future.Response().StatusCode).To(Equal(http.StatusPreconditionFailed))
+
+			_, err = controller.putAppGw(context.Background(), appGw, to.StringPtr("etag-2"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ifMatch).To(Equal([]string{"etag-1", "etag-2"}))
+		})
+	})
 })
@@ -0,0 +1,87 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"reflect"
+	"time"
+
+	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
+	"github.com/golang/glog"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+)
+
+// getProhibitedTargetStatus computes the status of a prohibited target from its spec and the existing App Gateway config.
+func getProhibitedTargetStatus(appGw n.ApplicationGateway, target *ptv1.AzureIngressProhibitedTarget, now time.Time) ptv1.AzureIngressProhibitedTargetStatus {
+	matches := brownfield.GetProhibitedTargetMatches(appGw, target)
+	status := ptv1.AzureIngressProhibitedTargetStatus{
+		ObservedGeneration:  target.Generation,
+		Listeners:           matches.Listeners,
+		RoutingRules:        matches.RoutingRules,
+		BackendPools:        matches.BackendPools,
+		BackendHTTPSettings: matches.BackendHTTPSettings,
+		Probes:              matches.Probes,
+	}
+
+	accepted := ptv1.AzureIngressProhibitedTargetCondition{
+		Type:    ptv1.Accepted,
+		Status:  v1.ConditionTrue,
+		Reason:  "Observed",
+		Message: "AGIC does not modify the App Gateway config matched by this target",
+	}
+	invalid := ptv1.AzureIngressProhibitedTargetCondition{
+		Type:   ptv1.Invalid,
+		Status: v1.ConditionFalse,
+		Reason: "Valid",
+	}
+
+	// An invalid target is still honored as written; AGIC would rather leave too much config alone than too little.
+	if err := brownfield.ValidateProhibitedTarget(target); err != nil {
+		accepted.Status = v1.ConditionFalse
+		accepted.Reason = "InvalidSpec"
+		accepted.Message = "AGIC does not modify the App Gateway config matched by this target as written, but the spec needs fixing"
+		invalid.Status = v1.ConditionTrue
+		invalid.Reason = "InvalidSpec"
+		invalid.Message = err.Error()
+	}
+
+	for _, condition := range []ptv1.AzureIngressProhibitedTargetCondition{accepted, invalid} {
+		condition.LastTransitionTime = metav1.NewTime(now)
+		for _, previous := range target.Status.Conditions {
+			if previous.Type == condition.Type && previous.Status == condition.Status {
+				condition.LastTransitionTime = previous.LastTransitionTime
+			}
+		}
+		status.Conditions = append(status.Conditions, condition)
+	}
+	return status
+}
+
+// getProhibitedTargetStatuses computes the status of each prohibited target, in the order of the given targets.
+func getProhibitedTargetStatuses(appGw n.ApplicationGateway, targets []*ptv1.AzureIngressProhibitedTarget, now time.Time) []ptv1.AzureIngressProhibitedTargetStatus {
+	var statuses []ptv1.AzureIngressProhibitedTargetStatus
+	for _, target := range targets {
+		statuses = append(statuses, getProhibitedTargetStatus(appGw, target, now))
+	}
+	return statuses
+}
+
+// updateProhibitedTargetStatuses writes the status of each prohibited target, which has changed since it was last written.
+func (c AppGwIngressController) updateProhibitedTargetStatuses(targets []*ptv1.AzureIngressProhibitedTarget, statuses []ptv1.AzureIngressProhibitedTargetStatus) {
+	for idx, target := range targets {
+		if reflect.DeepEqual(target.Status, statuses[idx]) {
+			continue
+		}
+		glog.V(5).Infof("[brownfield] Updating status of AzureIngressProhibitedTarget %s/%s", target.Namespace, target.Name)
+		updated := target.DeepCopy()
+		updated.Status = statuses[idx]
+		_ = c.k8sContext.UpdateAzureIngressProhibitedTargetStatus(updated)
+	}
+}
@@ -0,0 +1,93 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	ptv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	istio_fake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+)
+
+var _ = Describe("test prohibited target status", func() {
+	appGw := fixtures.GetAppGateway()
+	now := time.Now()
+
+	newTarget := func(paths ...string) *ptv1.AzureIngressProhibitedTarget {
+		return &ptv1.AzureIngressProhibitedTarget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "prohibited",
+				Namespace:  tests.Namespace,
+				Generation: 3,
+			},
+			Spec: ptv1.AzureIngressProhibitedTargetSpec{
+				Hostname: tests.Host,
+				Paths:    paths,
+			},
+		}
+	}
+
+	Context("with a valid prohibited target", func() {
+		It("should be accepted and list the matched resources", func() {
+			status := getProhibitedTargetStatus(appGw, newTarget("/foo/*"), now)
+			Expect(status.ObservedGeneration).To(Equal(int64(3)))
+			Expect(status.Listeners).To(ContainElement(fixtures.HTTPListenerPathBased1))
+			Expect(status.RoutingRules).ToNot(BeEmpty())
+			Expect(status.Conditions).To(HaveLen(2))
+			Expect(status.Conditions[0].Type).To(Equal(ptv1.Accepted))
+			Expect(status.Conditions[0].Status).To(Equal(v1.ConditionTrue))
+			Expect(status.Conditions[1].Type).To(Equal(ptv1.Invalid))
+			Expect(status.Conditions[1].Status).To(Equal(v1.ConditionFalse))
+		})
+	})
+
+	Context("with an invalid path", func() {
+		It("should be marked invalid", func() {
+			status := getProhibitedTargetStatus(appGw, newTarget("/foo"), now)
+			Expect(status.Conditions[0].Status).To(Equal(v1.ConditionFalse))
+			Expect(status.Conditions[1].Status).To(Equal(v1.ConditionTrue))
+			Expect(status.Conditions[1].Message).To(ContainSubstring("/foo"))
+		})
+	})
+
+	Context("with an unchanged status", func() {
+		It("should keep the last transition time and not update the target", func() {
+			crdClient := fake.NewSimpleClientset()
+			ctxt := k8scontext.NewContext(testclient.NewSimpleClientset(), crdClient, istio_fake.NewSimpleClientset(), []string{tests.Namespace}, 1000*time.Second)
+			controller := &AppGwIngressController{k8sContext: ctxt}
+
+			target := newTarget("/foo/*")
+			target.Status = getProhibitedTargetStatus(appGw, target, now.Add(-1*time.Hour))
+			_, err := crdClient.AzureingressprohibitedtargetsV1().AzureIngressProhibitedTargets(tests.Namespace).Create(target)
+			Expect(err).ToNot(HaveOccurred())
+
+			targets := []*ptv1.AzureIngressProhibitedTarget{target}
+			statuses := getProhibitedTargetStatuses(appGw, targets, now)
+			Expect(statuses[0]).To(Equal(target.Status))
+
+			crdClient.ClearActions()
+			controller.updateProhibitedTargetStatuses(targets, statuses)
+			Expect(crdClient.Actions()).To(BeEmpty())
+
+			target.Spec.Paths = []string{"/foo"}
+			controller.updateProhibitedTargetStatuses(targets, getProhibitedTargetStatuses(appGw, targets, now))
+			updated, err := crdClient.AzureingressprohibitedtargetsV1().AzureIngressProhibitedTargets(tests.Namespace).Get(target.Name, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated.Status.Conditions[1].Status).To(Equal(v1.ConditionTrue))
+			Expect(updated.Status.Conditions[1].LastTransitionTime.Time).To(Equal(metav1.NewTime(now).Time))
+		})
+	})
+})
@@ -1,7 +1,9 @@
 package controller
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
@@ -14,6 +16,8 @@ import (
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/errors"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/sorter"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
 type pruneFunc func(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*v1beta1.Ingress) []*v1beta1.Ingress
@@ -29,6 +33,7 @@ func (c *AppGwIngressController) PruneIngress(appGw *n.ApplicationGateway, cbCtx
 		}
 		pruneFuncList = append(pruneFuncList, pruneNoPrivateIP)
 		pruneFuncList = append(pruneFuncList, pruneRedirectWithNoTLS)
+		pruneFuncList = append(pruneFuncList, pruneHostnameConflicts)
 	})
 	prunedIngresses := cbCtx.IngressList
 	for _, prune := range pruneFuncList {
@@ -40,10 +45,29 @@ func (c *AppGwIngressController) PruneIngress(appGw *n.ApplicationGateway, cbCtx
 
 // pruneProhibitedIngress filters rules that are specified by prohibited target CRD
 func pruneProhibitedIngress(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*v1beta1.Ingress) []*v1beta1.Ingress {
+	var frontendIPs []n.ApplicationGatewayFrontendIPConfiguration
+	if appGw.FrontendIPConfigurations != nil {
+		frontendIPs = *appGw.FrontendIPConfigurations
+	}
+
 	// Mutate the list of Ingresses by removing ones that AGIC should not be creating configuration.
 	for idx, ingress := range ingressList {
+		usePrivateIP, _ := annotations.UsePrivateIP(ingress)
+		usePrivateIP = usePrivateIP || cbCtx.EnvVariables.UsePrivateIP == "true"
 		glog.V(5).Infof("Original Ingress[%d] Rules: %+v", idx, ingress.Spec.Rules)
-		ingressList[idx].Spec.Rules = brownfield.PruneIngressRules(ingress, cbCtx.ProhibitedTargets)
+		var pruned []brownfield.PrunedPath
+		useDefaultCertificate := c.getDefaultSecretKey(ingress, cbCtx) != ""
+		ingressList[idx].Spec.Rules, pruned = brownfield.PruneIngressRules(ingress, cbCtx.ProhibitedTargets, cbCtx.ManagedTargets, frontendIPs, usePrivateIP, useDefaultCertificate)
+		for _, prunedPath := range pruned {
+			if !prunedPath.IsPartialOverlap() {
+				continue
+			}
+			target, _ := json.Marshal(prunedPath.Target)
+			prohibitedBy, _ := json.Marshal(prunedPath.ProhibitedBy)
+			message := fmt.Sprintf("ignoring %s of Ingress %s/%s as it partially overlaps with prohibited target %s; App Gateway cannot exclude the prohibited paths, so the whole path was dropped", target, ingress.Namespace, ingress.Name, prohibitedBy)
+			glog.Warning(message)
+			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonPathOverlapsProhibitedTarget, message)
+		}
 		glog.V(5).Infof("Sanitized Ingress[%d] Rules: %+v", idx, ingress.Spec.Rules)
 	}
 
@@ -90,3 +114,149 @@ func pruneRedirectWithNoTLS(c *AppGwIngressController, appGw *n.ApplicationGatew
 
 	return prunedIngresses
 }
+
+// hostnameListener identifies an App Gateway listener by hostname, frontend port and frontend IP, like the
+// listener identifiers of the config builder.
+type hostnameListener struct {
+	hostname     string
+	port         int32
+	usePrivateIP bool
+}
+
+// pruneHostnameConflicts filters ingresses which serve a listener with another certificate than an ingress of another
+// namespace does. App Gateway has a single certificate for the listener, so the ingress created first keeps it; The
+// ingresses filtered out do not claim their other listeners.
+func pruneHostnameConflicts(c *AppGwIngressController, appGw *n.ApplicationGateway, cbCtx *appgw.ConfigBuilderContext, ingressList []*v1beta1.Ingress) []*v1beta1.Ingress {
+	byCreationTime := append([]*v1beta1.Ingress{}, ingressList...)
+	sort.Sort(sorter.ByIngressCreationTime(byCreationTime))
+
+	claims := make(map[hostnameListener]*v1beta1.Ingress)
+	claimedSecrets := make(map[hostnameListener]string)
+	conflicting := make(map[*v1beta1.Ingress]interface{})
+	for _, ingress := range byCreationTime {
+		listenerSecrets := c.getListenerSecrets(ingress, cbCtx)
+
+		var listeners []hostnameListener
+		for listener := range listenerSecrets {
+			listeners = append(listeners, listener)
+		}
+		sort.Slice(listeners, func(i, j int) bool {
+			if listeners[i].hostname != listeners[j].hostname {
+				return listeners[i].hostname < listeners[j].hostname
+			}
+			return listeners[i].port < listeners[j].port
+		})
+
+		var winner *v1beta1.Ingress
+		var conflictingListener hostnameListener
+		for _, listener := range listeners {
+			claim, claimed := claims[listener]
+			if claimed && claim.Namespace != ingress.Namespace && !c.sameCertificate(claimedSecrets[listener], listenerSecrets[listener]) {
+				winner, conflictingListener = claim, listener
+				break
+			}
+		}
+
+		if winner != nil {
+			errorLine := fmt.Sprintf("ignoring Ingress %s/%s as host %q on port %d is served with a different TLS secret by Ingress %s/%s, which was created earlier", ingress.Namespace, ingress.Name, conflictingListener.hostname, conflictingListener.port, winner.Namespace, winner.Name)
+			glog.Error(errorLine)
+			c.recorder.Event(ingress, v1.EventTypeWarning, events.ReasonHostnameConflict, errorLine)
+			conflicting[ingress] = nil
+			continue
+		}
+
+		for _, listener := range listeners {
+			if _, claimed := claims[listener]; !claimed {
+				claims[listener] = ingress
+				claimedSecrets[listener] = listenerSecrets[listener]
+			}
+		}
+	}
+
+	var prunedIngresses []*v1beta1.Ingress
+	for _, ingress := range ingressList {
+		if _, exists := conflicting[ingress]; !exists {
+			prunedIngresses = append(prunedIngresses, ingress)
+		}
+	}
+
+	return prunedIngresses
+}
+
+// getListenerSecrets maps the listeners the rules of the ingress get to the key of the TLS secret serving them. As in
+// the config builder, a hostname with a TLS secret, or with the default certificate, gets an HTTPS listener on port 443,
+// and an HTTP listener on port 80 only with ssl-redirect; A hostname without gets an HTTP listener on port 80. HTTP
+// listeners map to an empty key.
+func (c *AppGwIngressController) getListenerSecrets(ingress *v1beta1.Ingress, cbCtx *appgw.ConfigBuilderContext) map[hostnameListener]string {
+	tlsSecretNames := make(map[string]string)
+	for _, tls := range ingress.Spec.TLS {
+		if len(tls.SecretName) == 0 {
+			continue
+		}
+		if len(tls.Hosts) == 0 {
+			tlsSecretNames[""] = tls.SecretName
+		}
+		for _, hostname := range tls.Hosts {
+			tlsSecretNames[hostname] = tls.SecretName
+		}
+	}
+
+	usePrivateIP, _ := annotations.UsePrivateIP(ingress)
+	usePrivateIP = usePrivateIP || cbCtx.EnvVariables.UsePrivateIP == "true"
+	sslRedirect, _ := annotations.IsSslRedirect(ingress)
+	defaultSecret := c.getDefaultSecretKey(ingress, cbCtx)
+
+	listenerSecrets := make(map[hostnameListener]string)
+	for _, rule := range ingress.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		var secretKey string
+		secretName, exists := tlsSecretNames[rule.Host]
+		if !exists {
+			// a TLS section without hosts serves all hosts
+			secretName, exists = tlsSecretNames[""]
+		}
+		if exists {
+			secretKey = utils.GetResourceKey(annotations.TLSSecretNamespace(ingress, secretName), secretName)
+		} else if appgw.IsDefaultCertificateHost(ingress, rule.Host) {
+			secretKey = defaultSecret
+		}
+		if secretKey != "" {
+			listenerSecrets[hostnameListener{hostname: rule.Host, port: 443, usePrivateIP: usePrivateIP}] = secretKey
+		}
+		if secretKey == "" || sslRedirect {
+			listenerSecrets[hostnameListener{hostname: rule.Host, port: 80, usePrivateIP: usePrivateIP}] = ""
+		}
+	}
+	return listenerSecrets
+}
+
+// sameCertificate tells whether the two TLS secrets hold the same certificate chain; App Gateway gets a single SSL
+// certificate for secrets in several namespaces holding the same certificate.
+func (c *AppGwIngressController) sameCertificate(firstKey string, secondKey string) bool {
+	if firstKey == secondKey {
+		return true
+	}
+	if firstKey == "" || secondKey == "" {
+		return false
+	}
+	firstHash := c.k8sContext.CertificateSecretStore.GetCertificateHash(firstKey)
+	return firstHash != "" && firstHash == c.k8sContext.CertificateSecretStore.GetCertificateHash(secondKey)
+}
+
+// getDefaultSecretKey returns the key of the default certificate secret, when it exists and the ingress does not
+// opt out of it.
+func (c *AppGwIngressController) getDefaultSecretKey(ingress *v1beta1.Ingress, cbCtx *appgw.ConfigBuilderContext) string {
+	secretKey := cbCtx.EnvVariables.DefaultSSLCertificateSecret
+	if secretKey == "" {
+		return ""
+	}
+	if disabled, _ := annotations.IsDefaultCertificateDisabled(ingress); disabled {
+		return ""
+	}
+	if c.k8sContext.CertificateSecretStore.GetPfxCertificate(secretKey) == nil {
+		return ""
+	}
+	return secretKey
+}
@@ -6,15 +6,20 @@
 package controller
 
 import (
+	"time"
+
 	n "github.com/Azure/azure-sdk-for-go/services/network/mgmt/2019-06-01/network"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
 	v1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/appgw"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/k8scontext"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
 )
@@ -29,7 +34,8 @@ var _ = Describe("prune function tests", func() {
 				ResourceGroup:  "xxxx",
 				AppGwName:      "appgw",
 			},
-			recorder: record.NewFakeRecorder(100),
+			k8sContext: &k8scontext.Context{CertificateSecretStore: k8scontext.NewSecretStore()},
+			recorder:   record.NewFakeRecorder(100),
 		}
 	})
 
@@ -121,4 +127,101 @@ var _ = Describe("prune function tests", func() {
 			Expect(prunedIngresses).To(ContainElement(ingressValid2))
 		})
 	})
+
+	Context("ensure pruneHostnameConflicts prunes ingress", func() {
+		created := time.Date(2019, 10, 1, 0, 0, 0, 0, time.UTC)
+		newIngress := func(namespace, name, secretName string, age time.Duration) *v1beta1.Ingress {
+			ingress := tests.NewIngressFixture()
+			ingress.Namespace = namespace
+			ingress.Name = name
+			ingress.CreationTimestamp = metav1.NewTime(created.Add(-age))
+			ingress.Spec.TLS = []v1beta1.IngressTLS{{SecretName: secretName}}
+			return ingress
+		}
+		appGw := fixtures.GetAppGateway()
+
+		It("keeps the oldest ingress serving a hostname with another secret", func() {
+			newer := newIngress("team-a", "newer", "secret-a", time.Hour)
+			older := newIngress("team-b", "older", "secret-b", 2*time.Hour)
+			cbCtx := &appgw.ConfigBuilderContext{IngressList: []*v1beta1.Ingress{newer, older}}
+
+			prunedIngresses := pruneHostnameConflicts(controller, &appGw, cbCtx, cbCtx.IngressList)
+			Expect(prunedIngresses).To(Equal([]*v1beta1.Ingress{older}))
+
+			recorder := controller.recorder.(*record.FakeRecorder)
+			Expect(recorder.Events).To(HaveLen(1))
+			event := <-recorder.Events
+			Expect(event).To(HavePrefix(v1.EventTypeWarning + " " + events.ReasonHostnameConflict))
+			Expect(event).To(ContainSubstring("team-a/newer"))
+			Expect(event).To(ContainSubstring("team-b/older"))
+		})
+
+		It("breaks ties by namespace and name", func() {
+			ingressB := newIngress("team-b", "ingress", "secret-b", time.Hour)
+			ingressA := newIngress("team-a", "ingress", "secret-a", time.Hour)
+			cbCtx := &appgw.ConfigBuilderContext{IngressList: []*v1beta1.Ingress{ingressB, ingressA}}
+
+			prunedIngresses := pruneHostnameConflicts(controller, &appGw, cbCtx, cbCtx.IngressList)
+			Expect(prunedIngresses).To(Equal([]*v1beta1.Ingress{ingressA}))
+		})
+
+		It("keeps ingresses serving a hostname with the same secret, or from the same namespace", func() {
+			ingressA := newIngress("team-a", "ingress", "shared", time.Hour)
+			ingressB := newIngress("team-b", "ingress", "shared", time.Hour)
+			ingressB.Annotations[annotations.CrossNamespaceSecretsKey] = "team-a/shared"
+			otherIngressA := newIngress("team-a", "other", "secret-a", time.Hour)
+			cbCtx := &appgw.ConfigBuilderContext{IngressList: []*v1beta1.Ingress{ingressA, ingressB, otherIngressA}}
+
+			prunedIngresses := pruneHostnameConflicts(controller, &appGw, cbCtx, cbCtx.IngressList)
+			Expect(prunedIngresses).To(Equal(cbCtx.IngressList))
+		})
+
+		It("keeps ingresses serving a hostname with secrets holding the same certificate", func() {
+			ingressA := newIngress("team-a", "ingress", "secret", 2*time.Hour)
+			ingressB := newIngress("team-b", "ingress", "secret", time.Hour)
+			controller.k8sContext.CertificateSecretStore = hashedSecretStore{
+				SecretsKeeper: controller.k8sContext.CertificateSecretStore,
+				hashes: map[string]string{
+					"team-a/secret": "0123456789abcdef",
+					"team-b/secret": "0123456789abcdef",
+				},
+			}
+			cbCtx := &appgw.ConfigBuilderContext{IngressList: []*v1beta1.Ingress{ingressA, ingressB}}
+
+			prunedIngresses := pruneHostnameConflicts(controller, &appGw, cbCtx, cbCtx.IngressList)
+			Expect(prunedIngresses).To(Equal(cbCtx.IngressList))
+		})
+
+		It("keeps an HTTP ingress and an HTTPS ingress serving a hostname on different ports", func() {
+			https := newIngress("team-a", "https", "secret-a", 2*time.Hour)
+			delete(https.Annotations, annotations.SslRedirectKey)
+			http := newIngress("team-b", "http", "", time.Hour)
+			http.Spec.TLS = nil
+			delete(http.Annotations, annotations.SslRedirectKey)
+			cbCtx := &appgw.ConfigBuilderContext{IngressList: []*v1beta1.Ingress{https, http}}
+
+			prunedIngresses := pruneHostnameConflicts(controller, &appGw, cbCtx, cbCtx.IngressList)
+			Expect(prunedIngresses).To(Equal(cbCtx.IngressList))
+		})
+
+		It("keeps ingresses serving a hostname on different frontend IPs", func() {
+			public := newIngress("team-a", "public", "secret-a", 2*time.Hour)
+			private := newIngress("team-b", "private", "secret-b", time.Hour)
+			private.Annotations[annotations.UsePrivateIPKey] = "true"
+			cbCtx := &appgw.ConfigBuilderContext{IngressList: []*v1beta1.Ingress{public, private}}
+
+			prunedIngresses := pruneHostnameConflicts(controller, &appGw, cbCtx, cbCtx.IngressList)
+			Expect(prunedIngresses).To(Equal(cbCtx.IngressList))
+		})
+	})
 })
+
+// hashedSecretStore reports the given certificate hashes, as the secret store does for converted secrets.
+type hashedSecretStore struct {
+	k8scontext.SecretsKeeper
+	hashes map[string]string
+}
+
+func (s hashedSecretStore) GetCertificateHash(secretKey string) string {
+	return s.hashes[secretKey]
+}
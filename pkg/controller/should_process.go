@@ -19,8 +19,9 @@ func (c AppGwIngressController) ShouldProcess(event events.Event) (bool, string)
 			// Ignore kube-system namespace events
 			return false, ""
 		}
-		// this pod is not used by any ingress, skip any event for this
-		return c.k8sContext.IsPodReferencedByAnyIngress(pod), fmt.Sprintf("Skipping pod %s/%s as it is not used by any ingress", pod.Namespace, pod.Name)
+		// this pod is not used by any ingress or virtual service, skip any event for this
+		isReferenced := c.k8sContext.IsPodReferencedByAnyIngress(pod) || c.k8sContext.IsPodReferencedByAnyIstioVirtualService(pod)
+		return isReferenced, fmt.Sprintf("Skipping pod %s/%s as it is not used by any ingress or virtual service", pod.Namespace, pod.Name)
 	}
 
 	if endpoints, ok := event.Value.(*v1.Endpoints); ok {
@@ -28,8 +29,9 @@ func (c AppGwIngressController) ShouldProcess(event events.Event) (bool, string)
 			// Ignore kube-system namespace events
 			return false, ""
 		}
-		// this pod is not used by any ingress, skip any event for this
-		return c.k8sContext.IsEndpointReferencedByAnyIngress(endpoints), fmt.Sprintf("Skipping endpoints %s/%s as it is not used by any ingress", endpoints.Namespace, endpoints.Name)
+		// these endpoints are not used by any ingress or virtual service, skip any event for this
+		isReferenced := c.k8sContext.IsEndpointReferencedByAnyIngress(endpoints) || c.k8sContext.IsEndpointReferencedByAnyIstioVirtualService(endpoints)
+		return isReferenced, fmt.Sprintf("Skipping endpoints %s/%s as it is not used by any ingress or virtual service", endpoints.Namespace, endpoints.Name)
 	}
 
 	return true, ""
@@ -19,6 +19,8 @@ limitations under the License.
 package versioned
 
 import (
+	azureingressmaintenancefreezesv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/typed/azureingressmaintenancefreeze/v1"
+	azureingressmanagedtargetsv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/typed/azureingressmanagedtarget/v1"
 	azureingressprohibitedtargetsv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/typed/azureingressprohibitedtarget/v1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
@@ -27,6 +29,8 @@ import (
 
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
+	AzureingressmaintenancefreezesV1() azureingressmaintenancefreezesv1.AzureingressmaintenancefreezesV1Interface
+	AzureingressmanagedtargetsV1() azureingressmanagedtargetsv1.AzureingressmanagedtargetsV1Interface
 	AzureingressprohibitedtargetsV1() azureingressprohibitedtargetsv1.AzureingressprohibitedtargetsV1Interface
 }
 
@@ -34,7 +38,19 @@ type Interface interface {
 // version included in a Clientset.
 type Clientset struct {
 	*discovery.DiscoveryClient
-	azureingressprohibitedtargetsV1 *azureingressprohibitedtargetsv1.AzureingressprohibitedtargetsV1Client
+	azureingressmaintenancefreezesV1 *azureingressmaintenancefreezesv1.AzureingressmaintenancefreezesV1Client
+	azureingressmanagedtargetsV1     *azureingressmanagedtargetsv1.AzureingressmanagedtargetsV1Client
+	azureingressprohibitedtargetsV1  *azureingressprohibitedtargetsv1.AzureingressprohibitedtargetsV1Client
+}
+
+// AzureingressmaintenancefreezesV1 retrieves the AzureingressmaintenancefreezesV1Client
+func (c *Clientset) AzureingressmaintenancefreezesV1() azureingressmaintenancefreezesv1.AzureingressmaintenancefreezesV1Interface {
+	return c.azureingressmaintenancefreezesV1
+}
+
+// AzureingressmanagedtargetsV1 retrieves the AzureingressmanagedtargetsV1Client
+func (c *Clientset) AzureingressmanagedtargetsV1() azureingressmanagedtargetsv1.AzureingressmanagedtargetsV1Interface {
+	return c.azureingressmanagedtargetsV1
 }
 
 // AzureingressprohibitedtargetsV1 retrieves the AzureingressprohibitedtargetsV1Client
@@ -58,6 +74,14 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	}
 	var cs Clientset
 	var err error
+	cs.azureingressmaintenancefreezesV1, err = azureingressmaintenancefreezesv1.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
+	cs.azureingressmanagedtargetsV1, err = azureingressmanagedtargetsv1.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 	cs.azureingressprohibitedtargetsV1, err = azureingressprohibitedtargetsv1.NewForConfig(&configShallowCopy)
 	if err != nil {
 		return nil, err
@@ -74,6 +98,8 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 // panics if there is an error in the config.
 func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
+	cs.azureingressmaintenancefreezesV1 = azureingressmaintenancefreezesv1.NewForConfigOrDie(c)
+	cs.azureingressmanagedtargetsV1 = azureingressmanagedtargetsv1.NewForConfigOrDie(c)
 	cs.azureingressprohibitedtargetsV1 = azureingressprohibitedtargetsv1.NewForConfigOrDie(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
@@ -83,6 +109,8 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 // New creates a new Clientset for the given RESTClient.
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
+	cs.azureingressmaintenancefreezesV1 = azureingressmaintenancefreezesv1.New(c)
+	cs.azureingressmanagedtargetsV1 = azureingressmanagedtargetsv1.New(c)
 	cs.azureingressprohibitedtargetsV1 = azureingressprohibitedtargetsv1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
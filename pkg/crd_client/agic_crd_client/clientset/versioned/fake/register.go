@@ -19,6 +19,8 @@ limitations under the License.
 package fake
 
 import (
+	azureingressmaintenancefreezesv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmaintenancefreeze/v1"
+	azureingressmanagedtargetsv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
 	azureingressprohibitedtargetsv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -31,6 +33,8 @@ var scheme = runtime.NewScheme()
 var codecs = serializer.NewCodecFactory(scheme)
 var parameterCodec = runtime.NewParameterCodec(scheme)
 var localSchemeBuilder = runtime.SchemeBuilder{
+	azureingressmaintenancefreezesv1.AddToScheme,
+	azureingressmanagedtargetsv1.AddToScheme,
 	azureingressprohibitedtargetsv1.AddToScheme,
 }
 
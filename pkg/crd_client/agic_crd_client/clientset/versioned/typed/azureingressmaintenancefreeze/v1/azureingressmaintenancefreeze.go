@@ -0,0 +1,180 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"time"
+
+	v1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmaintenancefreeze/v1"
+	scheme "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// AzureIngressMaintenanceFreezesGetter has a method to return a AzureIngressMaintenanceFreezeInterface.
+// A group's client should implement this interface.
+type AzureIngressMaintenanceFreezesGetter interface {
+	AzureIngressMaintenanceFreezes() AzureIngressMaintenanceFreezeInterface
+}
+
+// AzureIngressMaintenanceFreezeInterface has methods to work with AzureIngressMaintenanceFreeze resources.
+type AzureIngressMaintenanceFreezeInterface interface {
+	Create(*v1.AzureIngressMaintenanceFreeze) (*v1.AzureIngressMaintenanceFreeze, error)
+	Update(*v1.AzureIngressMaintenanceFreeze) (*v1.AzureIngressMaintenanceFreeze, error)
+	UpdateStatus(*v1.AzureIngressMaintenanceFreeze) (*v1.AzureIngressMaintenanceFreeze, error)
+	Delete(name string, options *metav1.DeleteOptions) error
+	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
+	Get(name string, options metav1.GetOptions) (*v1.AzureIngressMaintenanceFreeze, error)
+	List(opts metav1.ListOptions) (*v1.AzureIngressMaintenanceFreezeList, error)
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.AzureIngressMaintenanceFreeze, err error)
+	AzureIngressMaintenanceFreezeExpansion
+}
+
+// azureIngressMaintenanceFreezes implements AzureIngressMaintenanceFreezeInterface
+type azureIngressMaintenanceFreezes struct {
+	client rest.Interface
+}
+
+// newAzureIngressMaintenanceFreezes returns a AzureIngressMaintenanceFreezes
+func newAzureIngressMaintenanceFreezes(c *AzureingressmaintenancefreezesV1Client) *azureIngressMaintenanceFreezes {
+	return &azureIngressMaintenanceFreezes{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the azureIngressMaintenanceFreeze, and returns the corresponding azureIngressMaintenanceFreeze object, and an error if there is any.
+func (c *azureIngressMaintenanceFreezes) Get(name string, options metav1.GetOptions) (result *v1.AzureIngressMaintenanceFreeze, err error) {
+	result = &v1.AzureIngressMaintenanceFreeze{}
+	err = c.client.Get().
+		Resource("azureingressmaintenancefreezes").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of AzureIngressMaintenanceFreezes that match those selectors.
+func (c *azureIngressMaintenanceFreezes) List(opts metav1.ListOptions) (result *v1.AzureIngressMaintenanceFreezeList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.AzureIngressMaintenanceFreezeList{}
+	err = c.client.Get().
+		Resource("azureingressmaintenancefreezes").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested azureIngressMaintenanceFreezes.
+func (c *azureIngressMaintenanceFreezes) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("azureingressmaintenancefreezes").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a azureIngressMaintenanceFreeze and creates it.  Returns the server's representation of the azureIngressMaintenanceFreeze, and an error, if there is any.
+func (c *azureIngressMaintenanceFreezes) Create(azureIngressMaintenanceFreeze *v1.AzureIngressMaintenanceFreeze) (result *v1.AzureIngressMaintenanceFreeze, err error) {
+	result = &v1.AzureIngressMaintenanceFreeze{}
+	err = c.client.Post().
+		Resource("azureingressmaintenancefreezes").
+		Body(azureIngressMaintenanceFreeze).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a azureIngressMaintenanceFreeze and updates it. Returns the server's representation of the azureIngressMaintenanceFreeze, and an error, if there is any.
+func (c *azureIngressMaintenanceFreezes) Update(azureIngressMaintenanceFreeze *v1.AzureIngressMaintenanceFreeze) (result *v1.AzureIngressMaintenanceFreeze, err error) {
+	result = &v1.AzureIngressMaintenanceFreeze{}
+	err = c.client.Put().
+		Resource("azureingressmaintenancefreezes").
+		Name(azureIngressMaintenanceFreeze.Name).
+		Body(azureIngressMaintenanceFreeze).
+		Do().
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+
+func (c *azureIngressMaintenanceFreezes) UpdateStatus(azureIngressMaintenanceFreeze *v1.AzureIngressMaintenanceFreeze) (result *v1.AzureIngressMaintenanceFreeze, err error) {
+	result = &v1.AzureIngressMaintenanceFreeze{}
+	err = c.client.Put().
+		Resource("azureingressmaintenancefreezes").
+		Name(azureIngressMaintenanceFreeze.Name).
+		SubResource("status").
+		Body(azureIngressMaintenanceFreeze).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the azureIngressMaintenanceFreeze and deletes it. Returns an error if one occurs.
+func (c *azureIngressMaintenanceFreezes) Delete(name string, options *metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("azureingressmaintenancefreezes").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *azureIngressMaintenanceFreezes) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("azureingressmaintenancefreezes").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched azureIngressMaintenanceFreeze.
+func (c *azureIngressMaintenanceFreezes) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.AzureIngressMaintenanceFreeze, err error) {
+	result = &v1.AzureIngressMaintenanceFreeze{}
+	err = c.client.Patch(pt).
+		Resource("azureingressmaintenancefreezes").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,131 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	azureingressmaintenancefreezev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmaintenancefreeze/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeAzureIngressMaintenanceFreezes implements AzureIngressMaintenanceFreezeInterface
+type FakeAzureIngressMaintenanceFreezes struct {
+	Fake *FakeAzureingressmaintenancefreezesV1
+}
+
+var azureingressmaintenancefreezesResource = schema.GroupVersionResource{Group: "azureingressmaintenancefreezes.appgw.ingress.k8s.io", Version: "v1", Resource: "azureingressmaintenancefreezes"}
+
+var azureingressmaintenancefreezesKind = schema.GroupVersionKind{Group: "azureingressmaintenancefreezes.appgw.ingress.k8s.io", Version: "v1", Kind: "AzureIngressMaintenanceFreeze"}
+
+// Get takes name of the azureIngressMaintenanceFreeze, and returns the corresponding azureIngressMaintenanceFreeze object, and an error if there is any.
+func (c *FakeAzureIngressMaintenanceFreezes) Get(name string, options v1.GetOptions) (result *azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(azureingressmaintenancefreezesResource, name), &azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze), err
+}
+
+// List takes label and field selectors, and returns the list of AzureIngressMaintenanceFreezes that match those selectors.
+func (c *FakeAzureIngressMaintenanceFreezes) List(opts v1.ListOptions) (result *azureingressmaintenancefreezev1.AzureIngressMaintenanceFreezeList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(azureingressmaintenancefreezesResource, azureingressmaintenancefreezesKind, opts), &azureingressmaintenancefreezev1.AzureIngressMaintenanceFreezeList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &azureingressmaintenancefreezev1.AzureIngressMaintenanceFreezeList{ListMeta: obj.(*azureingressmaintenancefreezev1.AzureIngressMaintenanceFreezeList).ListMeta}
+	for _, item := range obj.(*azureingressmaintenancefreezev1.AzureIngressMaintenanceFreezeList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested azureIngressMaintenanceFreezes.
+func (c *FakeAzureIngressMaintenanceFreezes) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(azureingressmaintenancefreezesResource, opts))
+}
+
+// Create takes the representation of a azureIngressMaintenanceFreeze and creates it.  Returns the server's representation of the azureIngressMaintenanceFreeze, and an error, if there is any.
+func (c *FakeAzureIngressMaintenanceFreezes) Create(azureIngressMaintenanceFreeze *azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze) (result *azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(azureingressmaintenancefreezesResource, azureIngressMaintenanceFreeze), &azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze), err
+}
+
+// Update takes the representation of a azureIngressMaintenanceFreeze and updates it. Returns the server's representation of the azureIngressMaintenanceFreeze, and an error, if there is any.
+func (c *FakeAzureIngressMaintenanceFreezes) Update(azureIngressMaintenanceFreeze *azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze) (result *azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(azureingressmaintenancefreezesResource, azureIngressMaintenanceFreeze), &azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeAzureIngressMaintenanceFreezes) UpdateStatus(azureIngressMaintenanceFreeze *azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze) (*azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateSubresourceAction(azureingressmaintenancefreezesResource, "status", azureIngressMaintenanceFreeze), &azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze), err
+}
+
+// Delete takes name of the azureIngressMaintenanceFreeze and deletes it. Returns an error if one occurs.
+func (c *FakeAzureIngressMaintenanceFreezes) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteAction(azureingressmaintenancefreezesResource, name), &azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeAzureIngressMaintenanceFreezes) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(azureingressmaintenancefreezesResource, listOptions)
+
+	_, err := c.Fake.Invokes(action, &azureingressmaintenancefreezev1.AzureIngressMaintenanceFreezeList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched azureIngressMaintenanceFreeze.
+func (c *FakeAzureIngressMaintenanceFreezes) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(azureingressmaintenancefreezesResource, name, pt, data, subresources...), &azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze), err
+}
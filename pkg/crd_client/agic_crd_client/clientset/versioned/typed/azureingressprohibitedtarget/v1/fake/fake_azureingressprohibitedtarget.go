@@ -100,6 +100,18 @@ func (c *FakeAzureIngressProhibitedTargets) Update(azureIngressProhibitedTarget
 	return obj.(*azureingressprohibitedtargetv1.AzureIngressProhibitedTarget), err
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeAzureIngressProhibitedTargets) UpdateStatus(azureIngressProhibitedTarget *azureingressprohibitedtargetv1.AzureIngressProhibitedTarget) (*azureingressprohibitedtargetv1.AzureIngressProhibitedTarget, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(azureingressprohibitedtargetsResource, "status", c.ns, azureIngressProhibitedTarget), &azureingressprohibitedtargetv1.AzureIngressProhibitedTarget{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*azureingressprohibitedtargetv1.AzureIngressProhibitedTarget), err
+}
+
 // Delete takes name of the azureIngressProhibitedTarget and deletes it. Returns an error if one occurs.
 func (c *FakeAzureIngressProhibitedTargets) Delete(name string, options *v1.DeleteOptions) error {
 	_, err := c.Fake.
@@ -0,0 +1,88 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	time "time"
+
+	azureingressmaintenancefreezev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmaintenancefreeze/v1"
+	versioned "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned"
+	internalinterfaces "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/informers/externalversions/internalinterfaces"
+	v1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/listers/azureingressmaintenancefreeze/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// AzureIngressMaintenanceFreezeInformer provides access to a shared informer and lister for
+// AzureIngressMaintenanceFreezes.
+type AzureIngressMaintenanceFreezeInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.AzureIngressMaintenanceFreezeLister
+}
+
+type azureIngressMaintenanceFreezeInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewAzureIngressMaintenanceFreezeInformer constructs a new informer for AzureIngressMaintenanceFreeze type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewAzureIngressMaintenanceFreezeInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredAzureIngressMaintenanceFreezeInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredAzureIngressMaintenanceFreezeInformer constructs a new informer for AzureIngressMaintenanceFreeze type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredAzureIngressMaintenanceFreezeInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.AzureingressmaintenancefreezesV1().AzureIngressMaintenanceFreezes().List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.AzureingressmaintenancefreezesV1().AzureIngressMaintenanceFreezes().Watch(options)
+			},
+		},
+		&azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *azureIngressMaintenanceFreezeInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredAzureIngressMaintenanceFreezeInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *azureIngressMaintenanceFreezeInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&azureingressmaintenancefreezev1.AzureIngressMaintenanceFreeze{}, f.defaultInformer)
+}
+
+func (f *azureIngressMaintenanceFreezeInformer) Lister() v1.AzureIngressMaintenanceFreezeLister {
+	return v1.NewAzureIngressMaintenanceFreezeLister(f.Informer().GetIndexer())
+}
@@ -0,0 +1,89 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	time "time"
+
+	azureingressmanagedtargetv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
+	versioned "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned"
+	internalinterfaces "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/informers/externalversions/internalinterfaces"
+	v1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/listers/azureingressmanagedtarget/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// AzureIngressManagedTargetInformer provides access to a shared informer and lister for
+// AzureIngressManagedTargets.
+type AzureIngressManagedTargetInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1.AzureIngressManagedTargetLister
+}
+
+type azureIngressManagedTargetInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewAzureIngressManagedTargetInformer constructs a new informer for AzureIngressManagedTarget type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewAzureIngressManagedTargetInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredAzureIngressManagedTargetInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredAzureIngressManagedTargetInformer constructs a new informer for AzureIngressManagedTarget type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredAzureIngressManagedTargetInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.AzureingressmanagedtargetsV1().AzureIngressManagedTargets(namespace).List(options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.AzureingressmanagedtargetsV1().AzureIngressManagedTargets(namespace).Watch(options)
+			},
+		},
+		&azureingressmanagedtargetv1.AzureIngressManagedTarget{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *azureIngressManagedTargetInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredAzureIngressManagedTargetInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *azureIngressManagedTargetInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&azureingressmanagedtargetv1.AzureIngressManagedTarget{}, f.defaultInformer)
+}
+
+func (f *azureIngressManagedTargetInformer) Lister() v1.AzureIngressManagedTargetLister {
+	return v1.NewAzureIngressManagedTargetLister(f.Informer().GetIndexer())
+}
@@ -23,6 +23,9 @@ const (
 	// AppGwNameVarName is the name of the APPGW_NAME
 	AppGwNameVarName = "APPGW_NAME"
 
+	// AdditionalAppGwIDsVarName is the name of the APPGW_ADDITIONAL_GATEWAY_IDS; A comma separated list of resource IDs of App Gateways managed in addition to APPGW_NAME
+	AdditionalAppGwIDsVarName = "APPGW_ADDITIONAL_GATEWAY_IDS"
+
 	// AuthLocationVarName is the name of the AZURE_AUTH_LOCATION
 	AuthLocationVarName = "AZURE_AUTH_LOCATION"
 
@@ -47,44 +50,79 @@ const (
 	// EnablePanicOnPutErrorVarName is a feature flag.
 	EnablePanicOnPutErrorVarName = "APPGW_ENABLE_PANIC_ON_PUT_ERROR"
 
+	// EnableMaintenanceFreezeVarName is a feature flag enabling observation of the AzureIngressMaintenanceFreeze CRD
+	EnableMaintenanceFreezeVarName = "APPGW_ENABLE_MAINTENANCE_FREEZE"
+
+	// EnableOwnershipRecordVarName is a feature flag making AGIC record the sub-resources it creates in App Gateway tags and retain all others
+	EnableOwnershipRecordVarName = "APPGW_ENABLE_OWNERSHIP_RECORD"
+
+	// ClusterIDVarName is the name of the APPGW_CLUSTER_ID; It identifies the cluster when several clusters share an App Gateway, and enables the ownership record
+	ClusterIDVarName = "APPGW_CLUSTER_ID"
+
+	// DeletionGuardThresholdVarName is the percentage of listeners, rules or pools a deployment may remove before the deletion guard blocks it
+	DeletionGuardThresholdVarName = "APPGW_DELETION_GUARD_THRESHOLD"
+
+	// CertificateExpiryWarningDaysVarName is the number of days before the expiry of a certificate in use AGIC starts warning about it
+	CertificateExpiryWarningDaysVarName = "APPGW_CERTIFICATE_EXPIRY_WARNING_DAYS"
+
+	// DefaultSSLCertificateSecretVarName is the "namespace/name" of the TLS secret whose certificate serves the hosts of ingresses without a tls section or a secret name
+	DefaultSSLCertificateSecretVarName = "APPGW_DEFAULT_SSL_CERTIFICATE_SECRET"
+
 	// HealthProbeServicePortVarName is an environment variable name.
 	HealthProbeServicePortVarName = "HEALTH_PROBE_SERVICE_PORT"
 )
 
 // EnvVariables is a struct storing values for environment variables.
 type EnvVariables struct {
-	SubscriptionID             string
-	ResourceGroupName          string
-	AppGwName                  string
-	AuthLocation               string
-	WatchNamespace             string
-	UsePrivateIP               string
-	VerbosityLevel             string
-	EnableBrownfieldDeployment bool
-	EnableIstioIntegration     bool
-	EnableSaveConfigToFile     bool
-	EnablePanicOnPutError      bool
-	HealthProbeServicePort     string
+	SubscriptionID               string
+	ResourceGroupName            string
+	AppGwName                    string
+	AdditionalAppGwIDs           string
+	AuthLocation                 string
+	WatchNamespace               string
+	UsePrivateIP                 string
+	VerbosityLevel               string
+	EnableBrownfieldDeployment   bool
+	EnableIstioIntegration       bool
+	EnableSaveConfigToFile       bool
+	EnablePanicOnPutError        bool
+	EnableMaintenanceFreeze      bool
+	EnableOwnershipRecord        bool
+	ClusterID                    string
+	DeletionGuardThreshold       string
+	CertificateExpiryWarningDays string
+	DefaultSSLCertificateSecret  string
+	HealthProbeServicePort       string
 }
 
 var portNumberValidator = regexp.MustCompile(`^[0-9]{4,5}$`)
 var boolValidator = regexp.MustCompile(`^(?i)(true|false)$`)
+var percentValidator = regexp.MustCompile(`^(100|[1-9]?[0-9])$`)
+var daysValidator = regexp.MustCompile(`^[0-9]{1,4}$`)
+var secretKeyValidator = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?/[a-z0-9]([-.a-z0-9]*[a-z0-9])?$`)
 
 // GetEnv returns values for defined environment variables for Ingress Controller.
 func GetEnv() EnvVariables {
 	env := EnvVariables{
-		SubscriptionID:             os.Getenv(SubscriptionIDVarName),
-		ResourceGroupName:          os.Getenv(ResourceGroupNameVarName),
-		AppGwName:                  os.Getenv(AppGwNameVarName),
-		AuthLocation:               os.Getenv(AuthLocationVarName),
-		WatchNamespace:             os.Getenv(WatchNamespaceVarName),
-		UsePrivateIP:               os.Getenv(UsePrivateIPVarName),
-		VerbosityLevel:             os.Getenv(VerbosityLevelVarName),
-		EnableBrownfieldDeployment: GetEnvironmentVariable(EnableBrownfieldDeploymentVarName, "false", boolValidator) == "true",
-		EnableIstioIntegration:     GetEnvironmentVariable(EnableIstioIntegrationVarName, "false", boolValidator) == "true",
-		EnableSaveConfigToFile:     GetEnvironmentVariable(EnableSaveConfigToFileVarName, "false", boolValidator) == "true",
-		EnablePanicOnPutError:      GetEnvironmentVariable(EnablePanicOnPutErrorVarName, "false", boolValidator) == "true",
-		HealthProbeServicePort:     GetEnvironmentVariable(HealthProbeServicePortVarName, "8123", portNumberValidator),
+		SubscriptionID:               os.Getenv(SubscriptionIDVarName),
+		ResourceGroupName:            os.Getenv(ResourceGroupNameVarName),
+		AppGwName:                    os.Getenv(AppGwNameVarName),
+		AdditionalAppGwIDs:           os.Getenv(AdditionalAppGwIDsVarName),
+		AuthLocation:                 os.Getenv(AuthLocationVarName),
+		WatchNamespace:               os.Getenv(WatchNamespaceVarName),
+		UsePrivateIP:                 os.Getenv(UsePrivateIPVarName),
+		VerbosityLevel:               os.Getenv(VerbosityLevelVarName),
+		EnableBrownfieldDeployment:   GetEnvironmentVariable(EnableBrownfieldDeploymentVarName, "false", boolValidator) == "true",
+		EnableIstioIntegration:       GetEnvironmentVariable(EnableIstioIntegrationVarName, "false", boolValidator) == "true",
+		EnableSaveConfigToFile:       GetEnvironmentVariable(EnableSaveConfigToFileVarName, "false", boolValidator) == "true",
+		EnablePanicOnPutError:        GetEnvironmentVariable(EnablePanicOnPutErrorVarName, "false", boolValidator) == "true",
+		EnableMaintenanceFreeze:      GetEnvironmentVariable(EnableMaintenanceFreezeVarName, "false", boolValidator) == "true",
+		EnableOwnershipRecord:        GetEnvironmentVariable(EnableOwnershipRecordVarName, "false", boolValidator) == "true",
+		ClusterID:                    os.Getenv(ClusterIDVarName),
+		DeletionGuardThreshold:       GetEnvironmentVariable(DeletionGuardThresholdVarName, "", percentValidator),
+		CertificateExpiryWarningDays: GetEnvironmentVariable(CertificateExpiryWarningDaysVarName, "30", daysValidator),
+		DefaultSSLCertificateSecret:  GetEnvironmentVariable(DefaultSSLCertificateSecretVarName, "", secretKeyValidator),
+		HealthProbeServicePort:       GetEnvironmentVariable(HealthProbeServicePortVarName, "8123", portNumberValidator),
 	}
 
 	return env
@@ -64,20 +64,23 @@ var _ = Describe("Environment", func() {
 				_ = os.Setenv(EnableIstioIntegrationVarName, "true")
 				_ = os.Setenv(EnableSaveConfigToFileVarName, "false")
 				_ = os.Setenv(EnablePanicOnPutErrorVarName, "true")
+				_ = os.Setenv(DefaultSSLCertificateSecretVarName, "default/tls-default")
 
 				expected := EnvVariables{
-					SubscriptionID:             "SubscriptionIDVarName",
-					ResourceGroupName:          "ResourceGroupNameVarName",
-					AppGwName:                  "AppGwNameVarName",
-					AuthLocation:               "AuthLocationVarName",
-					WatchNamespace:             "WatchNamespaceVarName",
-					UsePrivateIP:               "UsePrivateIPVarName",
-					VerbosityLevel:             "VerbosityLevelVarName",
-					EnableBrownfieldDeployment: false,
-					EnableIstioIntegration:     true,
-					EnableSaveConfigToFile:     false,
-					EnablePanicOnPutError:      true,
-					HealthProbeServicePort:     "8123",
+					SubscriptionID:               "SubscriptionIDVarName",
+					ResourceGroupName:            "ResourceGroupNameVarName",
+					AppGwName:                    "AppGwNameVarName",
+					AuthLocation:                 "AuthLocationVarName",
+					WatchNamespace:               "WatchNamespaceVarName",
+					UsePrivateIP:                 "UsePrivateIPVarName",
+					VerbosityLevel:               "VerbosityLevelVarName",
+					EnableBrownfieldDeployment:   false,
+					EnableIstioIntegration:       true,
+					EnableSaveConfigToFile:       false,
+					EnablePanicOnPutError:        true,
+					CertificateExpiryWarningDays: "30",
+					DefaultSSLCertificateSecret:  "default/tls-default",
+					HealthProbeServicePort:       "8123",
 				}
 
 				Expect(GetEnv()).To(Equal(expected))
@@ -18,6 +18,24 @@ const (
 	// ReasonSecretNotFound is a reason for an event to be emitted.
 	ReasonSecretNotFound = "SecretNotFound"
 
+	// ReasonSecretNotAllowed is a reason for an event to be emitted.
+	ReasonSecretNotAllowed = "SecretNotAllowed"
+
+	// ReasonSecretNamespaceNotWatched is a reason for an event to be emitted.
+	ReasonSecretNamespaceNotWatched = "SecretNamespaceNotWatched"
+
+	// ReasonInvalidSecret is a reason for an event to be emitted.
+	ReasonInvalidSecret = "InvalidSecret"
+
+	// ReasonCertificateExpiring is a reason for an event to be emitted.
+	ReasonCertificateExpiring = "CertificateExpiring"
+
+	// ReasonCertificateExpired is a reason for an event to be emitted.
+	ReasonCertificateExpired = "CertificateExpired"
+
+	// ReasonCertificateHostMismatch is a reason for an event to be emitted.
+	ReasonCertificateHostMismatch = "CertificateHostMismatch"
+
 	// ReasonServiceNotFound is a reason for an event to be emitted.
 	ReasonServiceNotFound = "ServiceNotFound"
 
@@ -35,4 +53,28 @@ const (
 
 	// ReasonInvalidAnnotation is a reason for an event to be emitted.
 	ReasonInvalidAnnotation = "InvalidAnnotation"
+
+	// ReasonMassDeletionBlocked is a reason for an event to be emitted.
+	ReasonMassDeletionBlocked = "MassDeletionBlocked"
+
+	// ReasonPathOverlapsProhibitedTarget is a reason for an event to be emitted.
+	ReasonPathOverlapsProhibitedTarget = "PathOverlapsProhibitedTarget"
+
+	// ReasonListenerConflict is a reason for an event to be emitted.
+	ReasonListenerConflict = "ListenerConflict"
+
+	// ReasonWeightsApproximated is a reason for an event to be emitted.
+	ReasonWeightsApproximated = "WeightsApproximated"
+
+	// ReasonUnsupportedFeature is a reason for an event to be emitted.
+	ReasonUnsupportedFeature = "UnsupportedFeature"
+
+	// ReasonSubsetNotFound is a reason for an event to be emitted.
+	ReasonSubsetNotFound = "SubsetNotFound"
+
+	// ReasonHostnameConflict is a reason for an event to be emitted.
+	ReasonHostnameConflict = "HostnameConflict"
+
+	// ReasonUnmanagedAppGw is a reason for an event to be emitted.
+	ReasonUnmanagedAppGw = "UnmanagedAppGw"
 )
@@ -5,7 +5,13 @@
 
 package health
 
-import "net/http"
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/brownfield"
+)
 
 type Probe func() bool
 
@@ -14,6 +20,11 @@ type HealthProbes interface {
 	Readiness() bool
 }
 
+// OwnershipReporter provides the brownfield ownership reports of the App Gateways AGIC manages.
+type OwnershipReporter interface {
+	OwnershipReports() []brownfield.OwnershipReport
+}
+
 func makeHandler(router *http.ServeMux, url string, probe Probe) {
 	router.Handle(url, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		w.WriteHeader(map[bool]int{
@@ -33,5 +44,23 @@ func NewHealthMux(healthProbes HealthProbes) *http.ServeMux {
 	for url, probe := range handlers {
 		makeHandler(router, url, probe)
 	}
+	// Counters from pkg/metrics
+	router.Handle("/debug/vars", expvar.Handler())
+	if reporter, ok := healthProbes.(OwnershipReporter); ok {
+		router.Handle("/debug/brownfield", newOwnershipHandler(reporter))
+	}
 	return router
 }
+
+// newOwnershipHandler serves the ownership reports as JSON.
+func newOwnershipHandler(reporter OwnershipReporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		reportsJSON, err := json.MarshalIndent(reporter.OwnershipReports(), "", "  ")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(reportsJSON)
+	})
+}
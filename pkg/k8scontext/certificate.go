@@ -0,0 +1,53 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+
+	"github.com/golang/glog"
+)
+
+// verifyCertificateChain makes sure App Gateway can serve the given certificates with the given key: The key must
+// belong to the first certificate, and every certificate must be issued by the one following it.
+func verifyCertificateChain(certificates []*x509.Certificate, key interface{}) error {
+	if !isKeyOfCertificate(key, certificates[0]) {
+		return ErrorPrivateKeyMismatch
+	}
+	for idx := 1; idx < len(certificates); idx++ {
+		if err := certificates[idx-1].CheckSignatureFrom(certificates[idx]); err != nil {
+			glog.Errorf("certificate %d [%s] is not issued by certificate %d [%s]: %v", idx, certificates[idx-1].Subject, idx+1, certificates[idx].Subject, err)
+			return ErrorCertificateChainOrder
+		}
+	}
+	return nil
+}
+
+// isKeyOfCertificate tells whether the given RSA or ECDSA private key is the one of the public key of the certificate.
+func isKeyOfCertificate(key interface{}, certificate *x509.Certificate) bool {
+	switch privateKey := key.(type) {
+	case *rsa.PrivateKey:
+		publicKey, ok := certificate.PublicKey.(*rsa.PublicKey)
+		return ok && publicKey.E == privateKey.E && publicKey.N.Cmp(privateKey.N) == 0
+	case *ecdsa.PrivateKey:
+		publicKey, ok := certificate.PublicKey.(*ecdsa.PublicKey)
+		return ok && publicKey.Curve == privateKey.Curve && publicKey.X.Cmp(privateKey.X) == 0 && publicKey.Y.Cmp(privateKey.Y) == 0
+	}
+	return false
+}
+
+// hashCertificateChain hashes the DER encoding of the given certificates, in order.
+func hashCertificateChain(certificates []*x509.Certificate) string {
+	hash := sha256.New()
+	for _, certificate := range certificates {
+		_, _ = hash.Write(certificate.Raw)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -22,6 +22,8 @@ import (
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
+	freezev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmaintenancefreeze/v1"
+	managedv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmanagedtarget/v1"
 	prohibitedv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/azure"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned"
@@ -56,21 +58,27 @@ func NewContext(kubeClient kubernetes.Interface, crdClient versioned.Interface,
 		Secret:    informerFactory.Core().V1().Secrets().Informer(),
 		Service:   informerFactory.Core().V1().Services().Informer(),
 
-		AzureIngressProhibitedTarget: crdInformerFactory.Azureingressprohibitedtargets().V1().AzureIngressProhibitedTargets().Informer(),
+		AzureIngressManagedLocation:   crdInformerFactory.Azureingressmanagedtargets().V1().AzureIngressManagedTargets().Informer(),
+		AzureIngressProhibitedTarget:  crdInformerFactory.Azureingressprohibitedtargets().V1().AzureIngressProhibitedTargets().Informer(),
+		AzureIngressMaintenanceFreeze: crdInformerFactory.Azureingressmaintenancefreezes().V1().AzureIngressMaintenanceFreezes().Informer(),
 
-		IstioGateway:        istioCrdInformerFactory.Networking().V1alpha3().Gateways().Informer(),
-		IstioVirtualService: istioCrdInformerFactory.Networking().V1alpha3().VirtualServices().Informer(),
+		IstioGateway:         istioCrdInformerFactory.Networking().V1alpha3().Gateways().Informer(),
+		IstioVirtualService:  istioCrdInformerFactory.Networking().V1alpha3().VirtualServices().Informer(),
+		IstioDestinationRule: istioCrdInformerFactory.Networking().V1alpha3().DestinationRules().Informer(),
 	}
 
 	cacheCollection := CacheCollection{
-		Endpoints:                    informerCollection.Endpoints.GetStore(),
-		Ingress:                      informerCollection.Ingress.GetStore(),
-		Pods:                         informerCollection.Pods.GetStore(),
-		Secret:                       informerCollection.Secret.GetStore(),
-		Service:                      informerCollection.Service.GetStore(),
-		AzureIngressProhibitedTarget: informerCollection.AzureIngressProhibitedTarget.GetStore(),
-		IstioGateway:                 informerCollection.IstioGateway.GetStore(),
-		IstioVirtualService:          informerCollection.IstioVirtualService.GetStore(),
+		Endpoints:                     informerCollection.Endpoints.GetStore(),
+		Ingress:                       informerCollection.Ingress.GetStore(),
+		Pods:                          informerCollection.Pods.GetStore(),
+		Secret:                        informerCollection.Secret.GetStore(),
+		Service:                       informerCollection.Service.GetStore(),
+		AzureIngressManagedLocation:   informerCollection.AzureIngressManagedLocation.GetStore(),
+		AzureIngressProhibitedTarget:  informerCollection.AzureIngressProhibitedTarget.GetStore(),
+		AzureIngressMaintenanceFreeze: informerCollection.AzureIngressMaintenanceFreeze.GetStore(),
+		IstioGateway:                  informerCollection.IstioGateway.GetStore(),
+		IstioVirtualService:           informerCollection.IstioVirtualService.GetStore(),
+		IstioDestinationRule:          informerCollection.IstioDestinationRule.GetStore(),
 	}
 
 	context := &Context{
@@ -80,10 +88,12 @@ func NewContext(kubeClient kubernetes.Interface, crdClient versioned.Interface,
 
 		informers:              &informerCollection,
 		ingressSecretsMap:      utils.NewThreadsafeMultimap(),
+		istioGatewaySecretsMap: utils.NewThreadsafeMultimap(),
 		Caches:                 &cacheCollection,
 		CertificateSecretStore: NewSecretStore(),
 		Work:                   make(chan events.Event, workBuffer),
 		CacheSynced:            make(chan interface{}),
+		namespaces:             namespaces,
 	}
 
 	h := handlers{context}
@@ -106,13 +116,24 @@ func NewContext(kubeClient kubernetes.Interface, crdClient versioned.Interface,
 		DeleteFunc: h.secretDelete,
 	}
 
+	istioGatewayResourceHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    h.istioGatewayAdd,
+		UpdateFunc: h.istioGatewayUpdate,
+		DeleteFunc: h.istioGatewayDelete,
+	}
+
 	// Register event handlers.
 	informerCollection.Endpoints.AddEventHandler(resourceHandler)
 	informerCollection.Ingress.AddEventHandler(ingressResourceHandler)
 	informerCollection.Pods.AddEventHandler(resourceHandler)
 	informerCollection.Secret.AddEventHandler(secretResourceHandler)
 	informerCollection.Service.AddEventHandler(resourceHandler)
+	informerCollection.AzureIngressManagedLocation.AddEventHandler(resourceHandler)
 	informerCollection.AzureIngressProhibitedTarget.AddEventHandler(resourceHandler)
+	informerCollection.AzureIngressMaintenanceFreeze.AddEventHandler(resourceHandler)
+	informerCollection.IstioGateway.AddEventHandler(istioGatewayResourceHandler)
+	informerCollection.IstioVirtualService.AddEventHandler(resourceHandler)
+	informerCollection.IstioDestinationRule.AddEventHandler(resourceHandler)
 
 	return context
 }
@@ -126,9 +147,12 @@ func (c *Context) Run(stopChannel chan struct{}, omitCRDs bool, envVariables env
 		return ErrorInformersNotInitialized
 	}
 	crds := map[cache.SharedInformer]interface{}{
-		c.informers.AzureIngressProhibitedTarget: nil,
-		c.informers.IstioGateway:                 nil,
-		c.informers.IstioVirtualService:          nil,
+		c.informers.AzureIngressManagedLocation:   nil,
+		c.informers.AzureIngressProhibitedTarget:  nil,
+		c.informers.AzureIngressMaintenanceFreeze: nil,
+		c.informers.IstioGateway:                  nil,
+		c.informers.IstioVirtualService:           nil,
+		c.informers.IstioDestinationRule:          nil,
 	}
 
 	sharedInformers := []cache.SharedInformer{
@@ -141,13 +165,22 @@ func (c *Context) Run(stopChannel chan struct{}, omitCRDs bool, envVariables env
 
 	// For AGIC to watch for these CRDs the EnableBrownfieldDeploymentVarName env variable must be set to true
 	if envVariables.EnableBrownfieldDeployment {
-		sharedInformers = append(sharedInformers, c.informers.AzureIngressProhibitedTarget)
+		sharedInformers = append(sharedInformers, c.informers.AzureIngressProhibitedTarget, c.informers.AzureIngressManagedLocation)
+	}
+
+	// The maintenance freeze CRD is observed only when EnableMaintenanceFreezeVarName is set to true
+	if envVariables.EnableMaintenanceFreeze {
+		sharedInformers = append(sharedInformers, c.informers.AzureIngressMaintenanceFreeze)
 	}
 
 	if envVariables.EnableIstioIntegration {
-		sharedInformers = append(sharedInformers, c.informers.IstioGateway, c.informers.IstioVirtualService)
+		sharedInformers = append(sharedInformers, c.informers.IstioGateway, c.informers.IstioVirtualService, c.informers.IstioDestinationRule)
 	}
 
+	// The default certificate is converted and watched like the secrets ingresses reference; It must be known before
+	// the secret informer lists the secrets.
+	c.defaultSecretKey = envVariables.DefaultSSLCertificateSecret
+
 	for _, informer := range sharedInformers {
 		go informer.Run(stopChannel)
 		// NOTE: Delyan could not figure out how to make informer.HasSynced == true for the CRDs in unit tests
@@ -276,6 +309,51 @@ func (c *Context) ListAzureProhibitedTargets() []*prohibitedv1.AzureIngressProhi
 	return targets
 }
 
+// UpdateAzureIngressProhibitedTargetStatus writes the status of the given prohibited target.
+func (c *Context) UpdateAzureIngressProhibitedTargetStatus(target *prohibitedv1.AzureIngressProhibitedTarget) error {
+	if _, err := c.crdClient.AzureingressprohibitedtargetsV1().AzureIngressProhibitedTargets(target.Namespace).UpdateStatus(target); err != nil {
+		glog.Errorf("Unable to update status of AzureIngressProhibitedTarget %s/%s: %s", target.Namespace, target.Name, err)
+		return ErrorUpdatingProhibitedTarget
+	}
+	return nil
+}
+
+// ListAzureIngressManagedTargets returns a list of App Gwy configs, which AGIC is exclusively allowed to modify.
+func (c *Context) ListAzureIngressManagedTargets() []*managedv1.AzureIngressManagedTarget {
+	var targets []*managedv1.AzureIngressManagedTarget
+	for _, obj := range c.Caches.AzureIngressManagedLocation.List() {
+		targets = append(targets, obj.(*managedv1.AzureIngressManagedTarget))
+	}
+
+	var managedTargets []string
+	for _, target := range targets {
+		managedTargets = append(managedTargets, fmt.Sprintf("%s/%s", target.Namespace, target.Name))
+	}
+
+	glog.V(5).Infof("AzureIngressManagedTargets: %+v", strings.Join(managedTargets, ","))
+
+	return targets
+}
+
+// ListAzureIngressMaintenanceFreezes returns a list of the cluster wide maintenance freezes.
+func (c *Context) ListAzureIngressMaintenanceFreezes() []*freezev1.AzureIngressMaintenanceFreeze {
+	var freezes []*freezev1.AzureIngressMaintenanceFreeze
+	for _, obj := range c.Caches.AzureIngressMaintenanceFreeze.List() {
+		freezes = append(freezes, obj.(*freezev1.AzureIngressMaintenanceFreeze))
+	}
+	sort.Slice(freezes, func(i, j int) bool { return freezes[i].Name < freezes[j].Name })
+	return freezes
+}
+
+// UpdateAzureIngressMaintenanceFreezeStatus writes the status of the given maintenance freeze.
+func (c *Context) UpdateAzureIngressMaintenanceFreezeStatus(freeze *freezev1.AzureIngressMaintenanceFreeze) error {
+	if _, err := c.crdClient.AzureingressmaintenancefreezesV1().AzureIngressMaintenanceFreezes().UpdateStatus(freeze); err != nil {
+		glog.Errorf("Unable to update status of AzureIngressMaintenanceFreeze %s: %s", freeze.Name, err)
+		return ErrorUpdatingMaintenanceFreeze
+	}
+	return nil
+}
+
 // GetService returns the service identified by the key.
 func (c *Context) GetService(serviceKey string) *v1.Service {
 	serviceInterface, exist, err := c.Caches.Service.GetByKey(serviceKey)
@@ -312,21 +390,31 @@ func (c *Context) GetSecret(secretKey string) *v1.Secret {
 	return secret
 }
 
+// IsNamespaceWatched tells whether the resources of the given namespace are watched; Secrets of other namespaces are
+// never cached, so ingresses cannot use them.
+func (c *Context) IsNamespaceWatched(namespace string) bool {
+	if len(c.namespaces) == 0 {
+		return true
+	}
+	for _, watched := range c.namespaces {
+		if watched == namespace {
+			return true
+		}
+	}
+	return false
+}
+
 // GetVirtualServicesForGateway returns the VirtualServices for the provided gateway
 func (c *Context) GetVirtualServicesForGateway(gateway v1alpha3.Gateway) []*v1alpha3.VirtualService {
 	virtualServices := make([]*v1alpha3.VirtualService, 0)
 	allVirtualServices := c.ListIstioVirtualServices()
-	gatewayName := gateway.Name
 	for _, service := range allVirtualServices {
-		hasGateway := false
-		for _, serviceGateway := range service.Spec.Gateways {
-			if gatewayName == serviceGateway {
-				hasGateway = true
+		for _, gatewayRef := range service.Spec.Gateways {
+			if isIstioGatewayReference(gatewayRef, service.Namespace, &gateway) {
+				virtualServices = append(virtualServices, service)
+				break
 			}
 		}
-		if hasGateway {
-			virtualServices = append(virtualServices, service)
-		}
 	}
 	var virtualServiceLogging []string
 	for _, virtualService := range virtualServices {
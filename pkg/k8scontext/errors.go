@@ -10,13 +10,21 @@ import "errors"
 var (
 	ErrorFetchingEnpdoints              = errors.New("FetchingEndpoints")
 	ErrorUnknownSecretType              = errors.New("unknown secret type")
-	ErrorCreatingFile                   = errors.New("unable to create temp file")
 	ErrorMalformedSecret                = errors.New("malformed secret")
-	ErrorWritingToFile                  = errors.New("unable to write to file")
-	ErrorExportingWithOpenSSL           = errors.New("failed export with OpenSSL")
+	ErrorNoCertificate                  = errors.New("tls.crt holds no PEM encoded certificate")
+	ErrorInvalidCertificate             = errors.New("tls.crt holds a certificate that cannot be parsed")
+	ErrorNoPrivateKey                   = errors.New("tls.key holds no PEM encoded private key")
+	ErrorInvalidPrivateKey              = errors.New("tls.key holds a private key that cannot be parsed")
+	ErrorEncryptedPrivateKey            = errors.New("tls.key holds an encrypted private key")
+	ErrorUnsupportedPrivateKey          = errors.New("tls.key holds a private key that is neither RSA nor ECDSA")
+	ErrorPrivateKeyMismatch             = errors.New("tls.key holds a private key that does not belong to the first certificate of tls.crt")
+	ErrorCertificateChainOrder          = errors.New("tls.crt holds a certificate that is not issued by the certificate following it")
+	ErrorEncodingPfx                    = errors.New("unable to encode PFX")
 	ErrorInformersNotInitialized        = errors.New("informers are not initialized")
 	ErrorFailedInitialCacheSync         = errors.New("failed initial sync of resources required for ingress")
 	ErrorNoNodesFound                   = errors.New("no nodes were found in the node list")
 	ErrorUnrecognizedNodeProviderPrefix = errors.New("providerID is not prefixed with azure://")
 	ErrorUnableToUpdateIngress          = errors.New("ingress status update")
+	ErrorUpdatingMaintenanceFreeze      = errors.New("maintenance freeze status update")
+	ErrorUpdatingProhibitedTarget       = errors.New("prohibited target status update")
 )
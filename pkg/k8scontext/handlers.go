@@ -3,6 +3,8 @@ package k8scontext
 import (
 	"reflect"
 
+	freezev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmaintenancefreeze/v1"
+	prohibitedv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 )
 
@@ -19,7 +21,7 @@ func (h handlers) addFunc(obj interface{}) {
 }
 
 func (h handlers) updateFunc(oldObj, newObj interface{}) {
-	if reflect.DeepEqual(oldObj, newObj) {
+	if reflect.DeepEqual(oldObj, newObj) || isStatusUpdate(oldObj, newObj) {
 		return
 	}
 	h.context.Work <- events.Event{
@@ -28,6 +30,21 @@ func (h handlers) updateFunc(oldObj, newObj interface{}) {
 	}
 }
 
+// isStatusUpdate tells whether only the status of a CRD AGIC writes the status of has changed; AGIC writes the status
+// after processing, so reprocessing on the update would never settle. The generation of CRDs with a status subresource
+// changes with their spec only.
+func isStatusUpdate(oldObj, newObj interface{}) bool {
+	switch newCRD := newObj.(type) {
+	case *prohibitedv1.AzureIngressProhibitedTarget:
+		oldCRD, ok := oldObj.(*prohibitedv1.AzureIngressProhibitedTarget)
+		return ok && oldCRD.Generation == newCRD.Generation && reflect.DeepEqual(oldCRD.Spec, newCRD.Spec)
+	case *freezev1.AzureIngressMaintenanceFreeze:
+		oldCRD, ok := oldObj.(*freezev1.AzureIngressMaintenanceFreeze)
+		return ok && oldCRD.Generation == newCRD.Generation && reflect.DeepEqual(oldCRD.Spec, newCRD.Spec)
+	}
+	return false
+}
+
 func (h handlers) deleteFunc(obj interface{}) {
 	h.context.Work <- events.Event{
 		Type:  events.Delete,
@@ -0,0 +1,55 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	freezev1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressmaintenancefreeze/v1"
+	prohibitedv1 "github.com/Azure/application-gateway-kubernetes-ingress/pkg/apis/azureingressprohibitedtarget/v1"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+)
+
+var _ = ginkgo.Describe("K8scontext General Cache Handlers", func() {
+	ginkgo.Context("Test updateFunc", func() {
+		var h handlers
+		ginkgo.BeforeEach(func() {
+			h = handlers{context: &Context{Work: make(chan events.Event, 10)}}
+		})
+
+		ginkgo.It("ignores updates to the status of prohibited targets", func() {
+			oldTarget := &prohibitedv1.AzureIngressProhibitedTarget{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+			newTarget := oldTarget.DeepCopy()
+			newTarget.Status.Listeners = []string{"listener"}
+			h.updateFunc(oldTarget, newTarget)
+			Expect(h.context.Work).To(BeEmpty())
+
+			newTarget.Generation = 2
+			newTarget.Spec.Hostname = "www.contoso.com"
+			h.updateFunc(oldTarget, newTarget)
+			Expect(h.context.Work).To(HaveLen(1))
+		})
+
+		ginkgo.It("ignores updates to the status of maintenance freezes", func() {
+			oldFreeze := &freezev1.AzureIngressMaintenanceFreeze{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+			newFreeze := oldFreeze.DeepCopy()
+			newFreeze.Status.Frozen = true
+			h.updateFunc(oldFreeze, newFreeze)
+			Expect(h.context.Work).To(BeEmpty())
+		})
+
+		ginkgo.It("passes updates to the status of other resources", func() {
+			oldPod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+			newPod := oldPod.DeepCopy()
+			newPod.Status.PodIP = "10.0.0.1"
+			h.updateFunc(oldPod, newPod)
+			Expect(h.context.Work).To(HaveLen(1))
+		})
+	})
+})
@@ -7,6 +7,7 @@ import (
 	"k8s.io/api/extensions/v1beta1"
 	"k8s.io/client-go/tools/cache"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
@@ -19,26 +20,8 @@ func (h handlers) ingressAdd(obj interface{}) {
 		return
 	}
 
-	if ing.Spec.TLS != nil && len(ing.Spec.TLS) > 0 {
-		ingKey := utils.GetResourceKey(ing.Namespace, ing.Name)
-		for _, tls := range ing.Spec.TLS {
-			secKey := utils.GetResourceKey(ing.Namespace, tls.SecretName)
-
-			if h.context.ingressSecretsMap.ContainsPair(ingKey, secKey) {
-				continue
-			}
+	h.trackIngressSecrets(ing)
 
-			if secret, exists, err := h.context.Caches.Secret.GetByKey(secKey); exists && err == nil {
-				if !h.context.ingressSecretsMap.ContainsValue(secKey) {
-					if err := h.context.CertificateSecretStore.convertSecret(secKey, secret.(*v1.Secret)); err != nil {
-						continue
-					}
-				}
-			}
-
-			h.context.ingressSecretsMap.Insert(ingKey, secKey)
-		}
-	}
 	h.context.Work <- events.Event{
 		Type:  events.Create,
 		Value: obj,
@@ -80,25 +63,8 @@ func (h handlers) ingressUpdate(oldObj, newObj interface{}) {
 		return
 	}
 	if ing.Spec.TLS != nil && len(ing.Spec.TLS) > 0 {
-		ingKey := utils.GetResourceKey(ing.Namespace, ing.Name)
-		h.context.ingressSecretsMap.Clear(ingKey)
-		for _, tls := range ing.Spec.TLS {
-			secKey := utils.GetResourceKey(ing.Namespace, tls.SecretName)
-
-			if h.context.ingressSecretsMap.ContainsPair(ingKey, secKey) {
-				continue
-			}
-
-			if secret, exists, err := h.context.Caches.Secret.GetByKey(secKey); exists && err == nil {
-				if !h.context.ingressSecretsMap.ContainsValue(secKey) {
-					if err := h.context.CertificateSecretStore.convertSecret(secKey, secret.(*v1.Secret)); err != nil {
-						continue
-					}
-				}
-			}
-
-			h.context.ingressSecretsMap.Insert(ingKey, secKey)
-		}
+		h.context.ingressSecretsMap.Clear(utils.GetResourceKey(ing.Namespace, ing.Name))
+		h.trackIngressSecrets(ing)
 	}
 
 	h.context.Work <- events.Event{
@@ -106,3 +72,25 @@ func (h handlers) ingressUpdate(oldObj, newObj interface{}) {
 		Value: newObj,
 	}
 }
+
+// trackIngressSecrets records the TLS secrets the ingress references, so that changing one of them updates App Gateway,
+// and converts those not converted yet. A secret referenced in another namespace is tracked whether or not it grants
+// access, so that granting access updates App Gateway. A secret that fails to convert is tracked too, so that fixing it
+// updates App Gateway; The reason it failed is reported on the Ingress when App Gateway is configured.
+func (h handlers) trackIngressSecrets(ing *v1beta1.Ingress) {
+	ingKey := utils.GetResourceKey(ing.Namespace, ing.Name)
+	for _, tls := range ing.Spec.TLS {
+		secKey := utils.GetResourceKey(annotations.TLSSecretNamespace(ing, tls.SecretName), tls.SecretName)
+		if h.context.ingressSecretsMap.ContainsPair(ingKey, secKey) {
+			continue
+		}
+
+		if secret, exists, err := h.context.Caches.Secret.GetByKey(secKey); exists && err == nil {
+			if !h.context.ingressSecretsMap.ContainsValue(secKey) {
+				_ = h.context.CertificateSecretStore.convertSecret(secKey, secret.(*v1.Secret))
+			}
+		}
+
+		h.context.ingressSecretsMap.Insert(ingKey, secKey)
+	}
+}
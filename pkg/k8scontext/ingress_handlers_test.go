@@ -9,11 +9,15 @@ import (
 	"time"
 
 	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
 	"k8s.io/client-go/kubernetes"
 
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/annotations"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
 	istioFake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests"
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/tests/fixtures"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
 )
 
 var _ = ginkgo.Describe("K8scontext Ingress Cache Handlers", func() {
@@ -30,5 +34,23 @@ var _ = ginkgo.Describe("K8scontext Ingress Cache Handlers", func() {
 			h.ingressDelete(ing)
 			h.ingressUpdate(ing, ing)
 		})
+
+		ginkgo.It("tracks secrets referenced in other namespaces", func() {
+			ing := fixtures.GetIngress()
+			ing.Annotations[annotations.CrossNamespaceSecretsKey] = "shared/" + tests.NameOfSecret
+			h.ingressAdd(ing)
+
+			ingKey := utils.GetResourceKey(ing.Namespace, ing.Name)
+			Expect(h.context.ingressSecretsMap.ContainsPair(ingKey, utils.GetResourceKey("shared", tests.NameOfSecret))).To(BeTrue())
+			Expect(h.context.ingressSecretsMap.ContainsPair(ingKey, utils.GetResourceKey(ing.Namespace, tests.NameOfSecret))).To(BeFalse())
+		})
+
+		ginkgo.It("tells whether the secrets of a namespace can be referenced", func() {
+			Expect(h.context.IsNamespaceWatched("ns")).To(BeTrue())
+			Expect(h.context.IsNamespaceWatched("shared")).To(BeFalse())
+
+			allNamespaces := NewContext(k8sClient, fake.NewSimpleClientset(), istioFake.NewSimpleClientset(), nil, 1000*time.Second)
+			Expect(allNamespaces.IsNamespaceWatched("shared")).To(BeTrue())
+		})
 	})
 })
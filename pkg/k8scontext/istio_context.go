@@ -5,7 +5,18 @@
 
 package k8scontext
 
-import "github.com/knative/pkg/apis/istio/v1alpha3"
+import (
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// istioMeshGateway is the name VirtualServices use for the sidecars of the mesh, as opposed to a Gateway.
+const istioMeshGateway = "mesh"
 
 // ListIstioGateways returns a list of discovered Istio Gateways
 func (c *Context) ListIstioGateways() []*v1alpha3.Gateway {
@@ -24,3 +35,96 @@ func (c *Context) ListIstioVirtualServices() []*v1alpha3.VirtualService {
 	}
 	return virtualServices
 }
+
+// ListIstioDestinationRules returns a list of discovered Istio Destination Rules
+func (c *Context) ListIstioDestinationRules() []*v1alpha3.DestinationRule {
+	var destinationRules []*v1alpha3.DestinationRule
+	for _, destinationRule := range c.Caches.IstioDestinationRule.List() {
+		destinationRules = append(destinationRules, destinationRule.(*v1alpha3.DestinationRule))
+	}
+	return destinationRules
+}
+
+// GetIstioGatewayCertificate returns the PFX certificate of the TLS secret, in the namespace of the given Gateway,
+// which a server of the Gateway names as its credential. The secret is converted on first use and tracked from then on,
+// so that changes to it trigger an update of App Gateway like changes to the TLS secrets of Ingresses do.
+func (c *Context) GetIstioGatewayCertificate(gateway *v1alpha3.Gateway, credentialName string) []byte {
+	gatewayKey := utils.GetResourceKey(gateway.Namespace, gateway.Name)
+	secretKey := utils.GetResourceKey(gateway.Namespace, credentialName)
+	if c.istioGatewaySecretsMap != nil {
+		c.istioGatewaySecretsMap.Insert(gatewayKey, secretKey)
+	}
+	if cert := c.CertificateSecretStore.GetPfxCertificate(secretKey); cert != nil {
+		return cert
+	}
+
+	secret, exists, err := c.Caches.Secret.GetByKey(secretKey)
+	if err != nil || !exists {
+		glog.Errorf("[istio] Unable to find secret %s of Gateway %s", secretKey, gatewayKey)
+		return nil
+	}
+	if err := c.CertificateSecretStore.convertSecret(secretKey, secret.(*v1.Secret)); err != nil {
+		glog.Errorf("[istio] Unable to convert secret %s of Gateway %s: %s", secretKey, gatewayKey, err)
+		return nil
+	}
+	return c.CertificateSecretStore.GetPfxCertificate(secretKey)
+}
+
+// IsPodReferencedByAnyIstioVirtualService tells whether a service selecting the given pod is the destination of an
+// HTTP route of a VirtualService.
+func (c *Context) IsPodReferencedByAnyIstioVirtualService(pod *v1.Pod) bool {
+	for _, service := range c.listServicesByPodSelector(pod) {
+		if c.isServiceReferencedByAnyIstioVirtualService(service) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEndpointReferencedByAnyIstioVirtualService tells whether the service of the given endpoints is the destination of
+// an HTTP route of a VirtualService.
+func (c *Context) IsEndpointReferencedByAnyIstioVirtualService(endpoints *v1.Endpoints) bool {
+	service := c.GetService(utils.GetResourceKey(endpoints.Namespace, endpoints.Name))
+	return service != nil && c.isServiceReferencedByAnyIstioVirtualService(service)
+}
+
+// isServiceReferencedByAnyIstioVirtualService tells whether the given service is the destination of an HTTP route.
+func (c *Context) isServiceReferencedByAnyIstioVirtualService(service *v1.Service) bool {
+	for _, virtualService := range c.ListIstioVirtualServices() {
+		for _, http := range virtualService.Spec.HTTP {
+			for _, route := range http.Route {
+				if isIstioDestinationHost(route.Destination.Host, virtualService.Namespace, service) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isIstioDestinationHost tells whether the host of a destination of a VirtualService in the given namespace is the given
+// service. As in Istio, a service is named "service" in the namespace of the VirtualService, "service.namespace", or
+// "service.namespace.svc.cluster.local".
+func isIstioDestinationHost(host string, namespace string, service *v1.Service) bool {
+	name := host
+	if labels := strings.Split(host, "."); len(labels) > 1 {
+		namespace, name = labels[1], labels[0]
+	}
+	return name == service.Name && namespace == service.Namespace
+}
+
+// isIstioGatewayReference tells whether a gateway named by a VirtualService in the given namespace is the given Gateway.
+// As in Istio, a gateway is named "gateway" in the namespace of the VirtualService, "namespace/gateway", or
+// "gateway.namespace.svc.cluster.local"; The reserved name "mesh" stands for the sidecars and names no Gateway.
+func isIstioGatewayReference(gatewayRef string, namespace string, gateway *v1alpha3.Gateway) bool {
+	if gatewayRef == istioMeshGateway {
+		return false
+	}
+	name := gatewayRef
+	if separator := strings.Index(gatewayRef, "/"); separator >= 0 {
+		namespace, name = gatewayRef[:separator], gatewayRef[separator+1:]
+	} else if labels := strings.Split(gatewayRef, "."); len(labels) > 1 {
+		namespace, name = labels[1], labels[0]
+	}
+	return name == gateway.Name && namespace == gateway.Namespace
+}
@@ -0,0 +1,95 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"time"
+
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	istioFake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+)
+
+var _ = ginkgo.Describe("K8scontext Istio", func() {
+	gateway := &v1alpha3.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "ns"}}
+
+	ginkgo.Context("Test gateways of VirtualServices", func() {
+		ginkgo.It("should resolve short names in the namespace of the VirtualService", func() {
+			Expect(isIstioGatewayReference("gateway", "ns", gateway)).To(BeTrue())
+			Expect(isIstioGatewayReference("gateway", "other", gateway)).To(BeFalse())
+		})
+
+		ginkgo.It("should resolve names qualified with a namespace", func() {
+			Expect(isIstioGatewayReference("ns/gateway", "other", gateway)).To(BeTrue())
+			Expect(isIstioGatewayReference("other/gateway", "ns", gateway)).To(BeFalse())
+			Expect(isIstioGatewayReference("gateway.ns.svc.cluster.local", "other", gateway)).To(BeTrue())
+		})
+
+		ginkgo.It("should not take the mesh for a Gateway", func() {
+			mesh := &v1alpha3.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "mesh", Namespace: "ns"}}
+			Expect(isIstioGatewayReference("mesh", "ns", mesh)).To(BeFalse())
+		})
+
+		ginkgo.It("should list the VirtualServices of a Gateway", func() {
+			ctx := NewContext(testclient.NewSimpleClientset(), fake.NewSimpleClientset(), istioFake.NewSimpleClientset(), []string{"ns"}, 1000*time.Second)
+			newVirtualService := func(name, namespace string, gateways ...string) *v1alpha3.VirtualService {
+				return &v1alpha3.VirtualService{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+					Spec:       v1alpha3.VirtualServiceSpec{Gateways: gateways},
+				}
+			}
+			_ = ctx.Caches.IstioVirtualService.Add(newVirtualService("local", "ns", "gateway"))
+			_ = ctx.Caches.IstioVirtualService.Add(newVirtualService("qualified", "other", "mesh", "ns/gateway"))
+			_ = ctx.Caches.IstioVirtualService.Add(newVirtualService("elsewhere", "other", "gateway"))
+			_ = ctx.Caches.IstioVirtualService.Add(newVirtualService("sidecars", "ns"))
+
+			var names []string
+			for _, virtualService := range ctx.GetVirtualServicesForGateway(*gateway) {
+				names = append(names, virtualService.Name)
+			}
+			Expect(names).To(ConsistOf("local", "qualified"))
+		})
+	})
+
+	ginkgo.Context("Test destinations of VirtualServices", func() {
+		service := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "ns"}}
+
+		ginkgo.It("should resolve short names in the namespace of the VirtualService", func() {
+			Expect(isIstioDestinationHost("reviews", "ns", service)).To(BeTrue())
+			Expect(isIstioDestinationHost("reviews", "other", service)).To(BeFalse())
+		})
+
+		ginkgo.It("should resolve names qualified with a namespace", func() {
+			Expect(isIstioDestinationHost("reviews.ns", "other", service)).To(BeTrue())
+			Expect(isIstioDestinationHost("reviews.ns.svc.cluster.local", "other", service)).To(BeTrue())
+			Expect(isIstioDestinationHost("reviews.other.svc.cluster.local", "ns", service)).To(BeFalse())
+		})
+
+		ginkgo.It("should find the endpoints of a destination written as a fully qualified name", func() {
+			ctx := NewContext(testclient.NewSimpleClientset(), fake.NewSimpleClientset(), istioFake.NewSimpleClientset(), []string{"ns"}, 1000*time.Second)
+			_ = ctx.Caches.Service.Add(service)
+			_ = ctx.Caches.IstioVirtualService.Add(&v1alpha3.VirtualService{
+				ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "other"},
+				Spec: v1alpha3.VirtualServiceSpec{
+					HTTP: []v1alpha3.HTTPRoute{{
+						Route: []v1alpha3.HTTPRouteDestination{{Destination: v1alpha3.Destination{Host: "reviews.ns.svc.cluster.local"}}},
+					}},
+				},
+			})
+
+			endpoints := &v1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "ns"}}
+			Expect(ctx.IsEndpointReferencedByAnyIstioVirtualService(endpoints)).To(BeTrue())
+			otherEndpoints := &v1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "ratings", Namespace: "ns"}}
+			Expect(ctx.IsEndpointReferencedByAnyIstioVirtualService(otherEndpoints)).To(BeFalse())
+		})
+	})
+})
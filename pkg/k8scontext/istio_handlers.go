@@ -0,0 +1,57 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"reflect"
+
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/utils"
+)
+
+// Istio Gateway resource handlers; The secrets of a Gateway are tracked as its servers are translated, in
+// GetIstioGatewayCertificate, and forgotten here when the Gateway changes or goes away.
+func (h handlers) istioGatewayAdd(obj interface{}) {
+	h.context.Work <- events.Event{
+		Type:  events.Create,
+		Value: obj,
+	}
+}
+
+func (h handlers) istioGatewayUpdate(oldObj, newObj interface{}) {
+	if reflect.DeepEqual(oldObj, newObj) {
+		return
+	}
+	gateway := newObj.(*v1alpha3.Gateway)
+	h.context.istioGatewaySecretsMap.Clear(utils.GetResourceKey(gateway.Namespace, gateway.Name))
+	h.context.Work <- events.Event{
+		Type:  events.Update,
+		Value: newObj,
+	}
+}
+
+func (h handlers) istioGatewayDelete(obj interface{}) {
+	gateway, ok := obj.(*v1alpha3.Gateway)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			// unable to get from tombstone
+			return
+		}
+		gateway, ok = tombstone.Obj.(*v1alpha3.Gateway)
+	}
+	if gateway == nil {
+		return
+	}
+	h.context.istioGatewaySecretsMap.Erase(utils.GetResourceKey(gateway.Namespace, gateway.Name))
+	h.context.Work <- events.Event{
+		Type:  events.Delete,
+		Value: obj,
+	}
+}
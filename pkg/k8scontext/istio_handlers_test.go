@@ -0,0 +1,90 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"time"
+
+	"github.com/knative/pkg/apis/istio/v1alpha3"
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
+	istioFake "github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/istio_crd_client/clientset/versioned/fake"
+	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/events"
+)
+
+var _ = ginkgo.Describe("K8scontext Istio Cache Handlers", func() {
+	var k8sClient kubernetes.Interface
+	var h handlers
+
+	gateway := &v1alpha3.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "ns"}}
+	virtualService := &v1alpha3.VirtualService{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "ns"},
+		Spec: v1alpha3.VirtualServiceSpec{
+			HTTP: []v1alpha3.HTTPRoute{{
+				Route: []v1alpha3.HTTPRouteDestination{{Destination: v1alpha3.Destination{Host: "reviews"}}},
+			}},
+		},
+	}
+
+	ginkgo.BeforeEach(func() {
+		h = handlers{
+			context: NewContext(k8sClient, fake.NewSimpleClientset(), istioFake.NewSimpleClientset(), []string{"ns"}, 1000*time.Second),
+		}
+	})
+
+	ginkgo.Context("Test Istio Gateway handlers", func() {
+		ginkgo.It("enqueues an event for each change", func() {
+			updated := gateway.DeepCopy()
+			updated.Spec.Servers = []v1alpha3.Server{{Hosts: []string{"*"}}}
+
+			h.istioGatewayAdd(gateway)
+			h.istioGatewayUpdate(gateway, gateway)
+			h.istioGatewayUpdate(gateway, updated)
+			h.istioGatewayDelete(updated)
+
+			Expect(h.context.Work).To(HaveLen(3))
+			Expect((<-h.context.Work).Type).To(Equal(events.Create))
+			Expect((<-h.context.Work).Type).To(Equal(events.Update))
+			Expect((<-h.context.Work).Type).To(Equal(events.Delete))
+		})
+
+		ginkgo.It("forgets the secrets of a deleted Gateway", func() {
+			Expect(h.context.GetIstioGatewayCertificate(gateway, "credential")).To(BeNil())
+			Expect(h.isReferencedSecret("ns/credential")).To(BeTrue())
+
+			h.istioGatewayDelete(gateway)
+			Expect(h.isReferencedSecret("ns/credential")).To(BeFalse())
+		})
+	})
+
+	ginkgo.Context("Test Istio VirtualService references", func() {
+		ginkgo.It("selects the endpoints of destinations", func() {
+			_ = h.context.Caches.IstioVirtualService.Add(virtualService)
+			_ = h.context.Caches.Service.Add(&v1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "ns"},
+				Spec: v1.ServiceSpec{
+					Ports:    []v1.ServicePort{{Protocol: v1.ProtocolTCP, Port: 80}},
+					Selector: map[string]string{"app": "reviews"},
+				},
+			})
+
+			endpoints := &v1.Endpoints{ObjectMeta: metav1.ObjectMeta{Name: "reviews", Namespace: "ns"}}
+			Expect(h.context.IsEndpointReferencedByAnyIstioVirtualService(endpoints)).To(BeTrue())
+			endpoints.Name = "ratings"
+			Expect(h.context.IsEndpointReferencedByAnyIstioVirtualService(endpoints)).To(BeFalse())
+
+			pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "reviews-v1", Namespace: "ns", Labels: map[string]string{"app": "reviews"}}}
+			Expect(h.context.IsPodReferencedByAnyIstioVirtualService(pod)).To(BeTrue())
+			pod.Labels = map[string]string{"app": "ratings"}
+			Expect(h.context.IsPodReferencedByAnyIstioVirtualService(pod)).To(BeFalse())
+		})
+	})
+})
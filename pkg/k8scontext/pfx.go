@@ -0,0 +1,380 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/golang/glog"
+)
+
+// The PFX (PKCS#12, RFC 7292) App Gateway takes holds the certificates in an encrypted safe, and the PKCS#8 private key
+// in a shrouded key bag of a plain safe, as "openssl pkcs12 -export" writes it. Both are encrypted with
+// pbeWithSHAAnd3-KeyTripleDES-CBC, which every PKCS#12 reader supports, and the PFX is protected with an HMAC-SHA1.
+const (
+	pfxIterations = 2048
+	pfxSaltLength = 8
+)
+
+var (
+	oidDataContentType               = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidEncryptedDataContentType      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+	oidPKCS8ShroudedKeyBag           = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag                       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidCertTypeX509Certificate       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+	oidLocalKeyID                    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+	oidPBEWithSHAAnd3KeyTripleDESCBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 3}
+	oidSHA1                          = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+)
+
+type pfxPdu struct {
+	Version  int
+	AuthSafe pfxContentInfo
+	MacData  pfxMacData
+}
+
+type pfxContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue
+}
+
+type pfxEncryptedData struct {
+	Version              int
+	EncryptedContentInfo pfxEncryptedContentInfo
+}
+
+type pfxEncryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           []byte `asn1:"tag:0,optional"`
+}
+
+type pfxMacData struct {
+	Mac        pfxDigestInfo
+	MacSalt    []byte
+	Iterations int
+}
+
+type pfxDigestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+type pfxSafeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue
+	Attributes []pfxAttribute `asn1:"set,optional"`
+}
+
+type pfxAttribute struct {
+	ID    asn1.ObjectIdentifier
+	Value asn1.RawValue
+}
+
+type pfxCertBag struct {
+	ID   asn1.ObjectIdentifier
+	Data asn1.RawValue
+}
+
+type pfxEncryptedPrivateKeyInfo struct {
+	AlgorithmIdentifier pkix.AlgorithmIdentifier
+	EncryptedData       []byte
+}
+
+type pfxPBEParams struct {
+	Salt       []byte
+	Iterations int
+}
+
+// pemToPfx converts the PEM encoded certificates and private key of a TLS secret to a PFX protected by the given
+// password, and returns it along with the parsed certificates. The first certificate is the one of the key; The others
+// complete its chain.
+func pemToPfx(certPEM []byte, keyPEM []byte, password string) ([]byte, []*x509.Certificate, error) {
+	certificates, err := parsePEMCertificates(certPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := parsePEMPrivateKey(keyPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := verifyCertificateChain(certificates, key); err != nil {
+		return nil, nil, err
+	}
+	pkcs8Key, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		glog.Errorf("unable to marshal private key to PKCS#8: %v", err)
+		return nil, nil, ErrorUnsupportedPrivateKey
+	}
+
+	pfx, err := encodePfx(certificates, pkcs8Key, password)
+	if err != nil {
+		glog.Errorf("unable to encode PFX: %v", err)
+		return nil, nil, ErrorEncodingPfx
+	}
+	return pfx, certificates, nil
+}
+
+// parsePEMCertificates parses the certificates of the given PEM data, in order.
+func parsePEMCertificates(certPEM []byte) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+	for block, rest := pem.Decode(certPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			glog.Errorf("unable to parse certificate %d: %v", len(certificates)+1, err)
+			return nil, ErrorInvalidCertificate
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		return nil, ErrorNoCertificate
+	}
+	return certificates, nil
+}
+
+// parsePEMPrivateKey parses the first private key of the given PEM data; RSA and ECDSA keys are supported in PKCS#8,
+// PKCS#1 and SEC 1 form.
+func parsePEMPrivateKey(keyPEM []byte) (interface{}, error) {
+	for block, rest := pem.Decode(keyPEM); block != nil; block, rest = pem.Decode(rest) {
+		if !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			continue
+		}
+		if block.Type == "ENCRYPTED PRIVATE KEY" || x509.IsEncryptedPEMBlock(block) {
+			return nil, ErrorEncryptedPrivateKey
+		}
+
+		var key interface{}
+		var err error
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		default:
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		}
+		if err != nil {
+			glog.Errorf("unable to parse %s: %v", strings.ToLower(block.Type), err)
+			return nil, ErrorInvalidPrivateKey
+		}
+
+		switch key.(type) {
+		case *rsa.PrivateKey, *ecdsa.PrivateKey:
+			return key, nil
+		}
+		glog.Errorf("private key of type %T is not supported", key)
+		return nil, ErrorUnsupportedPrivateKey
+	}
+	return nil, ErrorNoPrivateKey
+}
+
+func encodePfx(certificates []*x509.Certificate, pkcs8Key []byte, password string) ([]byte, error) {
+	encodedPassword := bmpStringZeroTerminated(password)
+
+	// The key and the certificate of the key share a local key ID.
+	localKeyID := sha1.Sum(certificates[0].Raw)
+	localKeyIDValue, err := asn1.MarshalWithParams([][]byte{localKeyID[:]}, "set")
+	if err != nil {
+		return nil, err
+	}
+	localKeyIDAttributes := []pfxAttribute{{ID: oidLocalKeyID, Value: asn1.RawValue{FullBytes: localKeyIDValue}}}
+
+	var certBags []pfxSafeBag
+	for idx, certificate := range certificates {
+		certValue, err := asn1.Marshal(certificate.Raw)
+		if err != nil {
+			return nil, err
+		}
+		certBag, err := asn1.Marshal(pfxCertBag{ID: oidCertTypeX509Certificate, Data: explicitContent(certValue)})
+		if err != nil {
+			return nil, err
+		}
+		safeBag := pfxSafeBag{ID: oidCertBag, Value: explicitContent(certBag)}
+		if idx == 0 {
+			safeBag.Attributes = localKeyIDAttributes
+		}
+		certBags = append(certBags, safeBag)
+	}
+	certSafe, err := asn1.Marshal(certBags)
+	if err != nil {
+		return nil, err
+	}
+	certAlgorithm, encryptedCertSafe, err := pbeEncrypt(certSafe, encodedPassword)
+	if err != nil {
+		return nil, err
+	}
+	encryptedData, err := asn1.Marshal(pfxEncryptedData{
+		Version: 0,
+		EncryptedContentInfo: pfxEncryptedContentInfo{
+			ContentType:                oidDataContentType,
+			ContentEncryptionAlgorithm: certAlgorithm,
+			EncryptedContent:           encryptedCertSafe,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keyAlgorithm, encryptedKey, err := pbeEncrypt(pkcs8Key, encodedPassword)
+	if err != nil {
+		return nil, err
+	}
+	shroudedKey, err := asn1.Marshal(pfxEncryptedPrivateKeyInfo{AlgorithmIdentifier: keyAlgorithm, EncryptedData: encryptedKey})
+	if err != nil {
+		return nil, err
+	}
+	keySafe, err := asn1.Marshal([]pfxSafeBag{{ID: oidPKCS8ShroudedKeyBag, Value: explicitContent(shroudedKey), Attributes: localKeyIDAttributes}})
+	if err != nil {
+		return nil, err
+	}
+	keySafeOctets, err := asn1.Marshal(keySafe)
+	if err != nil {
+		return nil, err
+	}
+
+	authenticatedSafe, err := asn1.Marshal([]pfxContentInfo{
+		{ContentType: oidEncryptedDataContentType, Content: explicitContent(encryptedData)},
+		{ContentType: oidDataContentType, Content: explicitContent(keySafeOctets)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	authenticatedSafeOctets, err := asn1.Marshal(authenticatedSafe)
+	if err != nil {
+		return nil, err
+	}
+
+	macSalt, err := randomBytes(pfxSaltLength)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha1.New, pkcs12KDF(3, encodedPassword, macSalt, pfxIterations, sha1.Size))
+	mac.Write(authenticatedSafe)
+
+	return asn1.Marshal(pfxPdu{
+		Version:  3,
+		AuthSafe: pfxContentInfo{ContentType: oidDataContentType, Content: explicitContent(authenticatedSafeOctets)},
+		MacData: pfxMacData{
+			Mac: pfxDigestInfo{
+				Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA1, Parameters: asn1.NullRawValue},
+				Digest:    mac.Sum(nil),
+			},
+			MacSalt:    macSalt,
+			Iterations: pfxIterations,
+		},
+	})
+}
+
+// explicitContent wraps the given DER in the [0] EXPLICIT tag PKCS#12 puts contents and bag values in.
+func explicitContent(der []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der}
+}
+
+// pbeEncrypt encrypts the given data with pbeWithSHAAnd3-KeyTripleDES-CBC, with a random salt.
+func pbeEncrypt(data []byte, encodedPassword []byte) (pkix.AlgorithmIdentifier, []byte, error) {
+	salt, err := randomBytes(pfxSaltLength)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	params, err := asn1.Marshal(pfxPBEParams{Salt: salt, Iterations: pfxIterations})
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+
+	block, err := des.NewTripleDESCipher(pkcs12KDF(1, encodedPassword, salt, pfxIterations, 24))
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, nil, err
+	}
+	iv := pkcs12KDF(2, encodedPassword, salt, pfxIterations, block.BlockSize())
+
+	// PKCS#7 padding
+	padding := block.BlockSize() - len(data)%block.BlockSize()
+	encrypted := append(append([]byte{}, data...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, encrypted)
+
+	algorithm := pkix.AlgorithmIdentifier{
+		Algorithm:  oidPBEWithSHAAnd3KeyTripleDESCBC,
+		Parameters: asn1.RawValue{FullBytes: params},
+	}
+	return algorithm, encrypted, nil
+}
+
+// pkcs12KDF derives key material of the given size from a password, as in RFC 7292 appendix B.2 with SHA-1. The ID
+// tells what the material is for: 1 for keys, 2 for IVs and 3 for MAC keys.
+func pkcs12KDF(id byte, encodedPassword []byte, salt []byte, iterations int, size int) []byte {
+	const u = sha1.Size
+	const v = 64
+
+	diversifier := bytes.Repeat([]byte{id}, v)
+	input := append(fillBlocks(salt, v), fillBlocks(encodedPassword, v)...)
+
+	var derived []byte
+	for len(derived) < size {
+		digest := sha1.Sum(append(append([]byte{}, diversifier...), input...))
+		for i := 1; i < iterations; i++ {
+			digest = sha1.Sum(digest[:])
+		}
+		derived = append(derived, digest[:]...)
+
+		// Every block I_j of the input becomes (I_j + B + 1) mod 2^(v*8), where B repeats the digest to fill v bytes.
+		filler := fillBlocks(digest[:u], v)
+		for j := 0; j < len(input); j += v {
+			carry := 1
+			for k := v - 1; k >= 0; k-- {
+				sum := int(input[j+k]) + int(filler[k]) + carry
+				input[j+k] = byte(sum)
+				carry = sum >> 8
+			}
+		}
+	}
+	return derived[:size]
+}
+
+// fillBlocks repeats the given data to fill whole blocks of the given size; Empty data fills none.
+func fillBlocks(data []byte, blockSize int) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+	length := blockSize * ((len(data) + blockSize - 1) / blockSize)
+	filled := make([]byte, length)
+	for i := range filled {
+		filled[i] = data[i%len(data)]
+	}
+	return filled
+}
+
+// bmpStringZeroTerminated encodes a password as PKCS#12 takes it: in UTF-16 big endian, followed by two zero bytes.
+func bmpStringZeroTerminated(password string) []byte {
+	encoded := make([]byte, 0, 2*len(password)+2)
+	for _, r := range utf16.Encode([]rune(password)) {
+		encoded = append(encoded, byte(r>>8), byte(r))
+	}
+	return append(encoded, 0, 0)
+}
+
+func randomBytes(size int) ([]byte, error) {
+	random := make([]byte, size)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	return random, nil
+}
@@ -0,0 +1,165 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+package k8scontext
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/pkcs12"
+)
+
+const testPfxPassword = "password"
+
+var _ = ginkgo.Describe("Testing PFX conversion", func() {
+	newCertificate := func(commonName string, key interface{}, parent *x509.Certificate, parentKey interface{}) *x509.Certificate {
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(time.Now().UnixNano()),
+			Subject:               pkix.Name{CommonName: commonName},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  parent == nil,
+			BasicConstraintsValid: true,
+		}
+		if parent == nil {
+			parent, parentKey = template, key
+		}
+		var publicKey interface{}
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			publicKey = &k.PublicKey
+		case *ecdsa.PrivateKey:
+			publicKey = &k.PublicKey
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, parentKey)
+		Expect(err).ToNot(HaveOccurred())
+		certificate, err := x509.ParseCertificate(der)
+		Expect(err).ToNot(HaveOccurred())
+		return certificate
+	}
+	encodeCertificates := func(certificates ...*x509.Certificate) []byte {
+		var encoded []byte
+		for _, certificate := range certificates {
+			encoded = append(encoded, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})...)
+		}
+		return encoded
+	}
+	encodePKCS8 := func(key interface{}) []byte {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		Expect(err).ToNot(HaveOccurred())
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	}
+	// decodePfx lists the PEM block types of the given PFX, and the common names of its certificates.
+	decodePfx := func(pfx []byte) ([]string, []string) {
+		blocks, err := pkcs12.ToPEM(pfx, testPfxPassword)
+		Expect(err).ToNot(HaveOccurred())
+		var types, commonNames []string
+		for _, block := range blocks {
+			types = append(types, block.Type)
+			if block.Type == "CERTIFICATE" {
+				certificate, err := x509.ParseCertificate(block.Bytes)
+				Expect(err).ToNot(HaveOccurred())
+				commonNames = append(commonNames, certificate.Subject.CommonName)
+			}
+		}
+		return types, commonNames
+	}
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	ginkgo.Context("Test key formats", func() {
+		ginkgo.It("should convert RSA keys in PKCS#1 and PKCS#8 form", func() {
+			certPEM := encodeCertificates(newCertificate("rsa", rsaKey, nil, nil))
+
+			pkcs1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+			for _, keyPEM := range [][]byte{pkcs1, encodePKCS8(rsaKey)} {
+				pfx, _, err := pemToPfx(certPEM, keyPEM, testPfxPassword)
+				Expect(err).ToNot(HaveOccurred())
+				types, commonNames := decodePfx(pfx)
+				Expect(types).To(ConsistOf("PRIVATE KEY", "CERTIFICATE"))
+				Expect(commonNames).To(Equal([]string{"rsa"}))
+			}
+		})
+
+		ginkgo.It("should convert ECDSA keys in SEC 1 and PKCS#8 form", func() {
+			certPEM := encodeCertificates(newCertificate("ecdsa", ecdsaKey, nil, nil))
+
+			sec1DER, err := x509.MarshalECPrivateKey(ecdsaKey)
+			Expect(err).ToNot(HaveOccurred())
+			// openssl ecparam -genkey writes the parameters of the curve ahead of the key.
+			sec1 := append(pem.EncodeToMemory(&pem.Block{Type: "EC PARAMETERS", Bytes: []byte{0x06, 0x08, 0x2a, 0x86, 0x48, 0xce, 0x3d, 0x03, 0x01, 0x07}}),
+				pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1DER})...)
+			for _, keyPEM := range [][]byte{sec1, encodePKCS8(ecdsaKey)} {
+				pfx, _, err := pemToPfx(certPEM, keyPEM, testPfxPassword)
+				Expect(err).ToNot(HaveOccurred())
+				types, _ := decodePfx(pfx)
+				Expect(types).To(ConsistOf("PRIVATE KEY", "CERTIFICATE"))
+			}
+		})
+	})
+
+	ginkgo.Context("Test chains", func() {
+		ginkgo.It("should keep every certificate of the chain in order", func() {
+			caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			ca := newCertificate("ca", caKey, nil, nil)
+			leaf := newCertificate("leaf", ecdsaKey, ca, caKey)
+
+			pfx, certificates, err := pemToPfx(encodeCertificates(leaf, ca), encodePKCS8(ecdsaKey), testPfxPassword)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(certificates[0].Subject.CommonName).To(Equal("leaf"))
+			_, commonNames := decodePfx(pfx)
+			Expect(commonNames).To(Equal([]string{"leaf", "ca"}))
+		})
+
+		ginkgo.It("should refuse chains out of order", func() {
+			caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			ca := newCertificate("ca", caKey, nil, nil)
+			leaf := newCertificate("leaf", ecdsaKey, ca, caKey)
+
+			_, _, err := pemToPfx(encodeCertificates(ca, leaf), encodePKCS8(caKey), testPfxPassword)
+			Expect(err).To(Equal(ErrorCertificateChainOrder))
+		})
+	})
+
+	ginkgo.Context("Test errors", func() {
+		ginkgo.It("should tell what is wrong with the certificate", func() {
+			_, _, err := pemToPfx([]byte("not PEM"), encodePKCS8(rsaKey), testPfxPassword)
+			Expect(err).To(Equal(ErrorNoCertificate))
+
+			invalid := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("not DER")})
+			_, _, err = pemToPfx(invalid, encodePKCS8(rsaKey), testPfxPassword)
+			Expect(err).To(Equal(ErrorInvalidCertificate))
+		})
+
+		ginkgo.It("should tell what is wrong with the private key", func() {
+			certPEM := encodeCertificates(newCertificate("rsa", rsaKey, nil, nil))
+
+			_, _, err := pemToPfx(certPEM, certPEM, testPfxPassword)
+			Expect(err).To(Equal(ErrorNoPrivateKey))
+
+			invalid := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("not DER")})
+			_, _, err = pemToPfx(certPEM, invalid, testPfxPassword)
+			Expect(err).To(Equal(ErrorInvalidPrivateKey))
+
+			encrypted := pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte("encrypted")})
+			_, _, err = pemToPfx(certPEM, encrypted, testPfxPassword)
+			Expect(err).To(Equal(ErrorEncryptedPrivateKey))
+
+			_, _, err = pemToPfx(certPEM, encodePKCS8(ecdsaKey), testPfxPassword)
+			Expect(err).To(Equal(ErrorPrivateKeyMismatch))
+		})
+	})
+})
@@ -19,13 +19,13 @@ import (
 func (h handlers) secretAdd(obj interface{}) {
 	sec := obj.(*v1.Secret)
 	secKey := utils.GetResourceKey(sec.Namespace, sec.Name)
-	if h.context.ingressSecretsMap.ContainsValue(secKey) {
+	if h.isReferencedSecret(secKey) {
 		// find if this secKey exists in the map[string]UnorderedSets
-		if err := h.context.CertificateSecretStore.convertSecret(secKey, sec); err == nil {
-			h.context.Work <- events.Event{
-				Type:  events.Create,
-				Value: obj,
-			}
+		// A secret that fails to convert updates App Gateway too, which reports the reason on the resources using it.
+		_ = h.context.CertificateSecretStore.convertSecret(secKey, sec)
+		h.context.Work <- events.Event{
+			Type:  events.Create,
+			Value: obj,
 		}
 	}
 }
@@ -37,12 +37,12 @@ func (h handlers) secretUpdate(oldObj, newObj interface{}) {
 
 	sec := newObj.(*v1.Secret)
 	secKey := utils.GetResourceKey(sec.Namespace, sec.Name)
-	if h.context.ingressSecretsMap.ContainsValue(secKey) {
-		if err := h.context.CertificateSecretStore.convertSecret(secKey, sec); err == nil {
-			h.context.Work <- events.Event{
-				Type:  events.Update,
-				Value: newObj,
-			}
+	if h.isReferencedSecret(secKey) {
+		// A secret that fails to convert updates App Gateway too, which reports the reason on the resources using it.
+		_ = h.context.CertificateSecretStore.convertSecret(secKey, sec)
+		h.context.Work <- events.Event{
+			Type:  events.Update,
+			Value: newObj,
 		}
 	}
 }
@@ -63,10 +63,22 @@ func (h handlers) secretDelete(obj interface{}) {
 
 	secKey := utils.GetResourceKey(sec.Namespace, sec.Name)
 	h.context.CertificateSecretStore.delete(secKey)
-	if h.context.ingressSecretsMap.ContainsValue(secKey) {
+	if h.isReferencedSecret(secKey) {
 		h.context.Work <- events.Event{
 			Type:  events.Delete,
 			Value: obj,
 		}
 	}
 }
+
+// isReferencedSecret tells whether an Ingress or an Istio Gateway uses the given secret for TLS, or whether it is the
+// default certificate.
+func (h handlers) isReferencedSecret(secKey string) bool {
+	if h.context.defaultSecretKey != "" && secKey == h.context.defaultSecretKey {
+		return true
+	}
+	if h.context.ingressSecretsMap.ContainsValue(secKey) {
+		return true
+	}
+	return h.context.istioGatewaySecretsMap != nil && h.context.istioGatewaySecretsMap.ContainsValue(secKey)
+}
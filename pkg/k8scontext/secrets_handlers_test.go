@@ -6,10 +6,13 @@
 package k8scontext
 
 import (
-	v1 "k8s.io/api/core/v1"
 	"time"
 
+	"github.com/knative/pkg/apis/istio/v1alpha3"
 	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/Azure/application-gateway-kubernetes-ingress/pkg/crd_client/agic_crd_client/clientset/versioned/fake"
@@ -30,5 +33,23 @@ var _ = ginkgo.Describe("K8scontext Secrets Cache Handlers", func() {
 			h.secretDelete(secret)
 			h.secretUpdate(secret, secret)
 		})
+
+		ginkgo.It("tracks the credentials of Istio Gateways", func() {
+			gateway := &v1alpha3.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "ns"}}
+			Expect(h.isReferencedSecret("ns/missing")).To(BeFalse())
+			Expect(h.context.GetIstioGatewayCertificate(gateway, "missing")).To(BeNil())
+			Expect(h.isReferencedSecret("ns/missing")).To(BeTrue())
+
+			h.context.CertificateSecretStore.(*SecretsStore).Cache.Add("ns/converted", []byte("xyz"))
+			Expect(h.context.GetIstioGatewayCertificate(gateway, "converted")).To(Equal([]byte("xyz")))
+			Expect(h.isReferencedSecret("ns/converted")).To(BeTrue())
+		})
+
+		ginkgo.It("tracks the default certificate", func() {
+			Expect(h.isReferencedSecret("ns/default")).To(BeFalse())
+			h.context.defaultSecretKey = "ns/default"
+			Expect(h.isReferencedSecret("ns/default")).To(BeTrue())
+			h.context.defaultSecretKey = ""
+		})
 	})
 })
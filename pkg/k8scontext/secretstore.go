@@ -6,10 +6,8 @@
 package k8scontext
 
 import (
-	"bytes"
-	"io/ioutil"
-	"os"
-	"os/exec"
+	"crypto/x509"
+	"encoding/hex"
 	"sync"
 
 	"github.com/golang/glog"
@@ -21,11 +19,18 @@ const (
 	recognizedSecretType = "kubernetes.io/tls"
 	tlsKey               = "tls.key"
 	tlsCrt               = "tls.crt"
+
+	// pfxPasswordLength is the number of random bytes of the password of a PFX, which is hex encoded.
+	pfxPasswordLength = 16
 )
 
 // SecretsKeeper is the interface definition for secret store
 type SecretsKeeper interface {
 	GetPfxCertificate(secretKey string) []byte
+	GetConversionError(secretKey string) error
+	GetLeafCertificate(secretKey string) *x509.Certificate
+	GetPfxPassword(secretKey string) string
+	GetCertificateHash(secretKey string) string
 	convertSecret(secretKey string, secret *v1.Secret) error
 	delete(secretKey string)
 }
@@ -34,6 +39,26 @@ type SecretsKeeper interface {
 type SecretsStore struct {
 	conversionSync sync.Mutex
 	Cache          cache.ThreadSafeStore
+
+	// conversionErrors holds the reason the last conversion of a secret failed; The PFX of the last successful
+	// conversion stays in the cache meanwhile.
+	conversionErrors map[string]error
+
+	// conversions holds what else the last successful conversion of each secret produced besides the PFX.
+	conversions map[string]secretConversion
+}
+
+// secretConversion describes the PFX a secret was converted to.
+type secretConversion struct {
+	// leafCertificate is the certificate of the private key, which is inspected for its expiry and the hosts it covers.
+	leafCertificate *x509.Certificate
+
+	// password protects the PFX; A new one is generated for every conversion.
+	password string
+
+	// hash identifies the certificate chain, so that secrets holding the same certificate share an App Gateway SSL
+	// certificate.
+	hash string
 }
 
 // NewSecretStore creates a new SecretsKeeper object
@@ -54,11 +79,47 @@ func (s *SecretsStore) GetPfxCertificate(secretKey string) []byte {
 	return nil
 }
 
+// GetConversionError returns the reason the last conversion of the given secret to PFX failed, or nil if it succeeded.
+func (s *SecretsStore) GetConversionError(secretKey string) error {
+	s.conversionSync.Lock()
+	defer s.conversionSync.Unlock()
+
+	return s.conversionErrors[secretKey]
+}
+
+// GetLeafCertificate returns the certificate of the private key of the given secret, as of its last successful
+// conversion.
+func (s *SecretsStore) GetLeafCertificate(secretKey string) *x509.Certificate {
+	s.conversionSync.Lock()
+	defer s.conversionSync.Unlock()
+
+	return s.conversions[secretKey].leafCertificate
+}
+
+// GetPfxPassword returns the password of the PFX of the given secret.
+func (s *SecretsStore) GetPfxPassword(secretKey string) string {
+	s.conversionSync.Lock()
+	defer s.conversionSync.Unlock()
+
+	return s.conversions[secretKey].password
+}
+
+// GetCertificateHash returns the hex encoded SHA-256 hash of the certificate chain of the given secret, or an empty
+// string if the secret was never converted.
+func (s *SecretsStore) GetCertificateHash(secretKey string) string {
+	s.conversionSync.Lock()
+	defer s.conversionSync.Unlock()
+
+	return s.conversions[secretKey].hash
+}
+
 func (s *SecretsStore) delete(secretKey string) {
 	s.conversionSync.Lock()
 	defer s.conversionSync.Unlock()
 
 	s.Cache.Delete(secretKey)
+	s.setConversionError(secretKey, nil)
+	delete(s.conversions, secretKey)
 }
 
 func (s *SecretsStore) convertSecret(secretKey string, secret *v1.Secret) error {
@@ -68,52 +129,40 @@ func (s *SecretsStore) convertSecret(secretKey string, secret *v1.Secret) error
 	// check if this is a secret with the correct type
 	if secret.Type != recognizedSecretType {
 		glog.Errorf("secret [%v] is not type kubernetes.io/tls", secretKey)
+		s.setConversionError(secretKey, ErrorUnknownSecretType)
 		return ErrorUnknownSecretType
 	}
 
 	if len(secret.Data[tlsKey]) == 0 || len(secret.Data[tlsCrt]) == 0 {
 		glog.Errorf("secret [%v] is malformed, tls.key or tls.crt is not defined", secretKey)
+		s.setConversionError(secretKey, ErrorMalformedSecret)
 		return ErrorMalformedSecret
 	}
 
-	tempfileCert, err := ioutil.TempFile("", "appgw-ingress-cert")
+	passwordBytes, err := randomBytes(pfxPasswordLength)
 	if err != nil {
-		glog.Error("unable to create temporary file for certificate conversion")
-		return ErrorCreatingFile
+		glog.Errorf("unable to generate a PFX password for secret [%v]: %v", secretKey, err)
+		s.setConversionError(secretKey, ErrorEncodingPfx)
+		return ErrorEncodingPfx
 	}
-	defer os.Remove(tempfileCert.Name())
+	password := hex.EncodeToString(passwordBytes)
 
-	tempfileKey, err := ioutil.TempFile("", "appgw-ingress-key")
+	pfxCert, certificates, err := pemToPfx(secret.Data[tlsCrt], secret.Data[tlsKey], password)
 	if err != nil {
-		glog.Error("unable to create temporary file for certificate conversion")
-		return ErrorCreatingFile
-	}
-	defer os.Remove(tempfileKey.Name())
-
-	if err := writeFileDecode(secret.Data["tls.crt"], tempfileCert); err != nil {
-		glog.Errorf("unable to write secret [%v].tls.crt to temporary file, error: %v", secretKey, err)
-		return ErrorWritingToFile
+		glog.Errorf("unable to convert secret [%v] to PFX: %v", secretKey, err)
+		s.setConversionError(secretKey, err)
+		return err
 	}
-
-	if err := writeFileDecode(secret.Data["tls.key"], tempfileKey); err != nil {
-		glog.Errorf("unable to write secret [%v].tls.key to temporary file, error: %v", secretKey, err)
-		return ErrorWritingToFile
+	s.setConversionError(secretKey, nil)
+	if s.conversions == nil {
+		s.conversions = make(map[string]secretConversion)
 	}
-
-	// both cert and key are in temp file now, call openssl
-	var cout, cerr bytes.Buffer
-	cmd := exec.Command("openssl", "pkcs12", "-export", "-in", tempfileCert.Name(), "-inkey", tempfileKey.Name(), "-password", "pass:msazure")
-	cmd.Stderr = &cerr
-	cmd.Stdout = &cout
-
-	// if openssl exited with an error or the output is empty, report error
-	if err := cmd.Run(); err != nil || len(cout.Bytes()) == 0 {
-		glog.Errorf("unable to export using openssl, error=[%v], stderr=[%v]", err, cerr.String())
-		return ErrorExportingWithOpenSSL
+	s.conversions[secretKey] = secretConversion{
+		leafCertificate: certificates[0],
+		password:        password,
+		hash:            hashCertificateChain(certificates),
 	}
 
-	pfxCert := cout.Bytes()
-
 	glog.V(1).Infof("converted secret [%v]", secretKey)
 	// TODO i'm not sure if comparison against existing certificate can help
 	// us optimize by eliminating some events
@@ -127,12 +176,13 @@ func (s *SecretsStore) convertSecret(secretKey string, secret *v1.Secret) error
 	return nil
 }
 
-func writeFileDecode(data []byte, fileHandle *os.File) error {
-	if _, err := fileHandle.Write(data); err != nil {
-		return err
+func (s *SecretsStore) setConversionError(secretKey string, err error) {
+	if err == nil {
+		delete(s.conversionErrors, secretKey)
+		return
 	}
-	if err := fileHandle.Close(); err != nil {
-		return err
+	if s.conversionErrors == nil {
+		s.conversionErrors = make(map[string]error)
 	}
-	return nil
+	s.conversionErrors[secretKey] = err
 }
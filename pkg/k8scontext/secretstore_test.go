@@ -8,6 +8,7 @@ package k8scontext
 import (
 	"github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/pkcs12"
 	v1 "k8s.io/api/core/v1"
 )
 
@@ -32,7 +33,8 @@ var _ = ginkgo.Describe("Testing K8sContext.SecretStore", func() {
 			malformed.Data[tlsKey] = []byte("X")
 			malformed.Data[tlsCrt] = []byte("Y")
 			err := secretsStore.convertSecret("someKey", &malformed)
-			Expect(err).To(Equal(ErrorExportingWithOpenSSL))
+			Expect(err).To(Equal(ErrorNoCertificate))
+			Expect(secretsStore.GetConversionError("someKey")).To(Equal(ErrorNoCertificate))
 		})
 		ginkgo.It("", func() {
 			goodSecret := secret
@@ -89,8 +91,20 @@ var _ = ginkgo.Describe("Testing K8sContext.SecretStore", func() {
 				"-----END CERTIFICATE-----\n")
 			err := secretsStore.convertSecret("someKey", &goodSecret)
 			Expect(err).ToNot(HaveOccurred())
+			Expect(secretsStore.GetConversionError("someKey")).ToNot(HaveOccurred())
 			actual := secretsStore.GetPfxCertificate("someKey")
-			Expect(len(actual)).To(Equal(2477))
+			blocks, err := pkcs12.ToPEM(actual, secretsStore.GetPfxPassword("someKey"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(blocks).To(HaveLen(2))
+			Expect(secretsStore.GetLeafCertificate("someKey").Subject.Organization).To(Equal([]string{"Internet Widgits Pty Ltd"}))
+			hash := secretsStore.GetCertificateHash("someKey")
+			Expect(hash).To(HaveLen(64))
+
+			// Every conversion protects the PFX with a new password, while the hash of the certificate stays the same.
+			password := secretsStore.GetPfxPassword("someKey")
+			Expect(secretsStore.convertSecret("someKey", &goodSecret)).ToNot(HaveOccurred())
+			Expect(secretsStore.GetPfxPassword("someKey")).ToNot(Equal(password))
+			Expect(secretsStore.GetCertificateHash("someKey")).To(Equal(hash))
 		})
 	})
 })
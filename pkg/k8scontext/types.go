@@ -12,30 +12,34 @@ import (
 
 // InformerCollection : all the informers for k8s resources we care about.
 type InformerCollection struct {
-	Endpoints                    cache.SharedIndexInformer
-	Ingress                      cache.SharedIndexInformer
-	Pods                         cache.SharedIndexInformer
-	Secret                       cache.SharedIndexInformer
-	Service                      cache.SharedIndexInformer
-	Namespace                    cache.SharedIndexInformer
-	AzureIngressManagedLocation  cache.SharedInformer
-	AzureIngressProhibitedTarget cache.SharedInformer
-	IstioGateway                 cache.SharedIndexInformer
-	IstioVirtualService          cache.SharedIndexInformer
+	Endpoints                     cache.SharedIndexInformer
+	Ingress                       cache.SharedIndexInformer
+	Pods                          cache.SharedIndexInformer
+	Secret                        cache.SharedIndexInformer
+	Service                       cache.SharedIndexInformer
+	Namespace                     cache.SharedIndexInformer
+	AzureIngressManagedLocation   cache.SharedInformer
+	AzureIngressProhibitedTarget  cache.SharedInformer
+	AzureIngressMaintenanceFreeze cache.SharedInformer
+	IstioGateway                  cache.SharedIndexInformer
+	IstioVirtualService           cache.SharedIndexInformer
+	IstioDestinationRule          cache.SharedIndexInformer
 }
 
 // CacheCollection : all the listers from the informers.
 type CacheCollection struct {
-	Endpoints                    cache.Store
-	Ingress                      cache.Store
-	Pods                         cache.Store
-	Secret                       cache.Store
-	Service                      cache.Store
-	Namespaces                   cache.Store
-	AzureIngressManagedLocation  cache.Store
-	AzureIngressProhibitedTarget cache.Store
-	IstioGateway                 cache.Store
-	IstioVirtualService          cache.Store
+	Endpoints                     cache.Store
+	Ingress                       cache.Store
+	Pods                          cache.Store
+	Secret                        cache.Store
+	Service                       cache.Store
+	Namespaces                    cache.Store
+	AzureIngressManagedLocation   cache.Store
+	AzureIngressProhibitedTarget  cache.Store
+	AzureIngressMaintenanceFreeze cache.Store
+	IstioGateway                  cache.Store
+	IstioVirtualService           cache.Store
+	IstioDestinationRule          cache.Store
 }
 
 // Context : cache and listener for k8s resources.
@@ -51,6 +55,15 @@ type Context struct {
 
 	ingressSecretsMap utils.ThreadsafeMultiMap
 
+	// istioGatewaySecretsMap tracks the TLS secrets the servers of Istio Gateways name as credentials.
+	istioGatewaySecretsMap utils.ThreadsafeMultiMap
+
+	// defaultSecretKey is the key of the TLS secret serving the hosts no secret of their ingress covers.
+	defaultSecretKey string
+
+	// namespaces are the namespaces watched; All namespaces are watched when there are none.
+	namespaces []string
+
 	Work chan events.Event
 
 	CacheSynced chan interface{}
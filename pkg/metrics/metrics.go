@@ -0,0 +1,29 @@
+// -------------------------------------------------------------------------------------------
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License. See License.txt in the project root for license information.
+// --------------------------------------------------------------------------------------------
+
+// Package metrics holds the counters AGIC publishes; They are served in JSON format on /debug/vars by the health probe server.
+package metrics
+
+import "expvar"
+
+var (
+	// DeletionGuardBlockedDeployments counts the App Gateway deployments refused by the deletion guard.
+	DeletionGuardBlockedDeployments = expvar.NewInt("agic_deletion_guard_blocked_deployments_total")
+
+	// ExpiringCertificates is the number of certificates in use which expire within the warning window, as of the last
+	// configuration built for each App Gateway, by App Gateway ID.
+	ExpiringCertificates = expvar.NewMap("agic_certificates_expiring")
+
+	// ExpiredCertificates is the number of certificates in use which have expired, as of the last configuration built
+	// for each App Gateway, by App Gateway ID.
+	ExpiredCertificates = expvar.NewMap("agic_certificates_expired")
+)
+
+// SetGauge sets the value of the given key of a map of gauges.
+func SetGauge(gauges *expvar.Map, key string, value int64) {
+	gauge := new(expvar.Int)
+	gauge.Set(value)
+	gauges.Set(key, gauge)
+}
@@ -21,3 +21,20 @@ func (a ByIngressUID) Less(i, j int) bool {
 func getIngressUID(ingress *v1beta1.Ingress) string {
 	return string(ingress.UID)
 }
+
+// ByIngressCreationTime is a facility to sort slices of Kubernetes Ingress from the oldest to the newest; Ingresses
+// created at the same time are sorted by namespace and name.
+type ByIngressCreationTime []*v1beta1.Ingress
+
+func (a ByIngressCreationTime) Len() int      { return len(a) }
+func (a ByIngressCreationTime) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a ByIngressCreationTime) Less(i, j int) bool {
+	if !a[i].CreationTimestamp.Equal(&a[j].CreationTimestamp) {
+		return a[i].CreationTimestamp.Before(&a[j].CreationTimestamp)
+	}
+	return getIngressKey(a[i]) < getIngressKey(a[j])
+}
+
+func getIngressKey(ingress *v1beta1.Ingress) string {
+	return ingress.Namespace + "/" + ingress.Name
+}
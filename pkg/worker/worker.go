@@ -35,7 +35,7 @@ func (w *Worker) Run(work chan events.Event, stopChannel chan struct{}) {
 				glog.V(3).Infoln("Successfully processed event")
 			}
 		case <-stopChannel:
-			break
+			return
 		}
 	}
 }